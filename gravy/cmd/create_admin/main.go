@@ -6,33 +6,49 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/db"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/uploader"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
-	"golang.org/x/crypto/bcrypt"
 )
 
-func hashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
-}
-
 func main() {
 
 	email := flag.String("email", "", "Email for the new admin user (required)")
 	password := flag.String("password", "", "Password for the new admin user (required)")
 	username := flag.String("username", "", "Username for the new admin user (required)")
 	name := flag.String("name", "Admin User", "Name for the new admin user")
+	roleList := flag.String("roles", "ADMIN", "Comma-separated roles to assign (USER, EDITOR, MAP_MANAGER, MODERATOR, ADMIN, SUPERADMIN)")
+	assign := flag.Bool("assign", false, "Assign --roles to the existing user with --email instead of creating one")
 
 	flag.Parse()
 
-	if *email == "" || *password == "" || *name == "" {
+	var assigned []string
+	for _, r := range strings.Split(*roleList, ",") {
+		r = strings.ToUpper(strings.TrimSpace(r))
+		if r == "" || r == string(roles.User) {
+			continue
+		}
+		if !roles.Valid(roles.Role(r)) {
+			log.Fatalf("Unknown role %q", r)
+		}
+		assigned = append(assigned, r)
+	}
+
+	if *assign {
+		if *email == "" {
+			log.Println("Error: --email flag is required with --assign.")
+			flag.Usage()
+			return
+		}
+	} else if *email == "" || *password == "" || *name == "" {
 		log.Println("Error: --email, --password and --name flags are required.")
 		flag.Usage()
 		return
@@ -60,26 +76,36 @@ func main() {
 
 	err = userCollection.FindOne(ctx, bson.M{"email": *email}).Decode(&existingUser)
 
+	if *assign {
+		if err != nil {
+			log.Fatalf("Failed to find user with email %s: %v", *email, err)
+		}
+		repo := users.NewRepository(client.Database("wikinitt"))
+		for _, r := range assigned {
+			if _, err := repo.AddRole(ctx, existingUser.ID, r); err != nil {
+				log.Fatalf("Failed to assign role %s: %v", r, err)
+			}
+		}
+		fmt.Printf("✅ Assigned roles %s to %s\n", strings.Join(assigned, ", "), *email)
+		return
+	}
+
 	if err == nil {
 		log.Fatalf("User with email %s already exists", *email)
 	} else if err != mongo.ErrNoDocuments {
 		log.Fatalf("Error checking for existing user: %v", err)
 	}
 
-	cldName := os.Getenv("CLOUDINARY_CLOUD_NAME")
-	cldKey := os.Getenv("CLOUDINARY_API_KEY")
-	cldSecret := os.Getenv("CLOUDINARY_API_SECRET")
-
-	if cldName == "" || cldKey == "" || cldSecret == "" {
-		log.Fatal("CLOUDINARY credentials are required")
+	uploaderService, err := uploader.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure file storage: %v", err)
 	}
 
-	uploaderService, err := uploader.NewUploader(cldName, cldKey, cldSecret)
-	if err != nil {
-		log.Fatalf("Failed to create Cloudinary uploader: %v", err)
+	if err := auth.ValidatePassword(*password); err != nil {
+		log.Fatalf("Invalid password: %v", err)
 	}
 
-	hashedPassword, err := hashPassword(*password)
+	hashedPassword, err := auth.HashPassword(*password)
 	if err != nil {
 		log.Fatalf("Failed to hash password: %v", err)
 	}
@@ -96,7 +122,8 @@ func main() {
 		Email:         *email,
 		Username:      *username,
 		PasswordHash:  hashedPassword,
-		IsAdmin:       true,
+		IsAdmin:       containsRole(assigned, roles.Admin),
+		Roles:         assigned,
 		Name:          *name,
 		DisplayName:   *name,
 		Avatar:        avatarURL,
@@ -106,6 +133,7 @@ func main() {
 		PhoneNumber:   "",
 		IsBanned:      false,
 		SetupComplete: true,
+		EmailVerified: true,
 	}
 
 	_, insertErr := userCollection.InsertOne(ctx, newUser)
@@ -116,4 +144,14 @@ func main() {
 	fmt.Printf("✅ Successfully created admin user!\n")
 	fmt.Printf("   Email: %s\n", newUser.Email)
 	fmt.Printf("   Name:  %s\n", newUser.Name)
+	fmt.Printf("   Roles: %s\n", strings.Join(assigned, ", "))
+}
+
+func containsRole(list []string, role roles.Role) bool {
+	for _, r := range list {
+		if r == string(role) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/db"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/karma"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	if mongoURI == "" {
+		log.Fatal("MONGODB_URI environment variable is required")
+	}
+
+	client, err := db.Connect(mongoURI)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer func() {
+		if err := client.Disconnect(context.Background()); err != nil {
+			log.Printf("Error disconnecting: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	repo := karma.NewRepository(client.Database("wikinitt"))
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		log.Fatalf("Failed to create karma indexes: %v", err)
+	}
+	written, err := repo.Rebuild(ctx)
+	if err != nil {
+		log.Fatalf("Failed to rebuild karma: %v", err)
+	}
+	fmt.Printf("✅ Rebuilt karma for %d user and group pairs\n", written)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/db"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Report counter drift without fixing it")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	if mongoURI == "" {
+		log.Fatal("MONGODB_URI environment variable is required")
+	}
+
+	client, err := db.Connect(mongoURI)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer func() {
+		if err := client.Disconnect(context.Background()); err != nil {
+			log.Printf("Error disconnecting: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	// Recounting doesn't touch search or karma, so neither is needed.
+	repo := community.NewRepository(client.Database("wikinitt"), nil, nil)
+	report, err := repo.RecountCounters(ctx, *dryRun)
+	if err != nil {
+		log.Fatalf("Failed to recount counters: %v", err)
+	}
+
+	for _, coll := range sortedKeys(report.Scanned) {
+		fmt.Printf("Scanned %d %s\n", report.Scanned[coll], coll)
+	}
+	if report.Fixed() == 0 {
+		fmt.Println("✅ All counters are consistent")
+		return
+	}
+	for _, field := range sortedKeys(report.Drift) {
+		fmt.Printf("  %-24s %d documents out of sync\n", field, report.Drift[field])
+	}
+	if *dryRun {
+		fmt.Println("Dry run, nothing was changed")
+		return
+	}
+	fmt.Println("✅ Counters fixed")
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -9,14 +9,20 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/articles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/karma"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/rag"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/sanitization"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/webhooks"
 )
 
 // CreateArticle is the resolver for the createArticle field.
@@ -52,6 +58,10 @@ func (r *mutationResolver) CreateArticle(ctx context.Context, input model.NewArt
 	if err != nil {
 		return nil, err
 	}
+	r.recordAudit(ctx, audit.ActionArticleCreate, audit.TargetArticle, created.ID, nil, created)
+	r.awardArticleKarma(ctx, created.AuthorID, karma.ArticlePoints)
+	r.publishArticleWebhook(ctx, webhooks.EventArticlePublished, created.ID, created.Title, created.Slug)
+	r.trackMedia(ctx, media.RefArticle, created.ID, created.Content, created.Thumbnail)
 
 	articles.StartBacklinkWorkers(
 		context.Background(),
@@ -109,12 +119,21 @@ func (r *mutationResolver) UpdateArticle(ctx context.Context, input model.Update
 	}
 	if input.Featured != nil {
 		updates["featured"] = *input.Featured
+		if *input.Featured && !existing.Featured {
+			updates["featuredAt"] = time.Now()
+		}
 	}
 
 	updated, err := r.ArticleRepo.Update(ctx, input.ID, updates)
 	if err != nil {
 		return nil, err
 	}
+	r.recordAudit(ctx, audit.ActionArticleUpdate, audit.TargetArticle, updated.ID, existing, updated)
+	if user := auth.ForContext(ctx); user != nil {
+		r.notifyArticleUpdated(ctx, user, updated)
+	}
+	r.publishArticleWebhook(ctx, webhooks.EventArticleUpdated, updated.ID, updated.Title, updated.Slug)
+	r.trackMedia(ctx, media.RefArticle, updated.ID, updated.Content, updated.Thumbnail)
 
 	if r.RagClient != nil {
 		go func(a *articles.Article) {
@@ -140,10 +159,19 @@ func (r *mutationResolver) UpdateArticle(ctx context.Context, input model.Update
 
 // DeleteArticle is the resolver for the deleteArticle field.
 func (r *mutationResolver) DeleteArticle(ctx context.Context, id string) (bool, error) {
-	err := r.ArticleRepo.Delete(ctx, id)
+	existing, err := r.ArticleRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ArticleRepo.Delete(ctx, id)
 	if err != nil {
 		return false, err
 	}
+	r.recordAudit(ctx, audit.ActionArticleDelete, audit.TargetArticle, id, existing, nil)
+	r.awardArticleKarma(ctx, existing.AuthorID, -karma.ArticlePoints)
+	r.publishArticleWebhook(ctx, webhooks.EventArticleDeleted, existing.ID, existing.Title, existing.Slug)
+	r.trackMedia(ctx, media.RefArticle, existing.ID)
 
 	if r.RagClient != nil {
 		go func(articleID string) {
@@ -161,11 +189,16 @@ func (r *mutationResolver) DeleteArticle(ctx context.Context, id string) (bool,
 
 // UploadImage is the resolver for the uploadImage field.
 func (r *mutationResolver) UploadImage(ctx context.Context, file graphql.Upload) (string, error) {
-	url, err := r.Uploader.UploadImage(ctx, file.File, "wikinitt/articles")
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return "", fmt.Errorf("not authenticated")
+	}
+
+	m, err := r.uploadMedia(ctx, user, file, imaging.PurposeArticle)
 	if err != nil {
 		return "", err
 	}
-	return url, nil
+	return m.URL, nil
 }
 
 // Articles is the resolver for the articles field.
@@ -0,0 +1,49 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/attachments"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+)
+
+// Attachments is the resolver for the attachments field.
+func (r *messageResolver) Attachments(ctx context.Context, obj *model.Message) ([]*model.Attachment, error) {
+	return r.attachmentsToModel(ctx, attachments.SourceMessage, obj.ID)
+}
+
+// UploadAttachment is the resolver for the uploadAttachment field.
+func (r *mutationResolver) UploadAttachment(ctx context.Context, file graphql.Upload) (*model.Attachment, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	attachment, err := attachments.Describe(file.File, file.Filename, file.Size)
+	if err != nil {
+		return nil, err
+	}
+	attachment.OwnerID = user.ID
+	if err := r.reserveUpload(ctx, user, file.Size); err != nil {
+		return nil, err
+	}
+	err = r.storeAttachment(ctx, file, attachment)
+	r.settleUpload(ctx, user, file.Size, attachment.StoredSize, err)
+	if err != nil {
+		return nil, err
+	}
+	return mapAttachmentToModel(attachment), nil
+}
+
+// Attachments is the resolver for the attachments field.
+func (r *postResolver) Attachments(ctx context.Context, obj *model.Post) ([]*model.Attachment, error) {
+	return r.attachmentsToModel(ctx, attachments.SourcePost, obj.ID)
+}
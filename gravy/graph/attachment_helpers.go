@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/attachments"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+// storeAttachment uploads the file described by a and saves a. Images are
+// re-encoded, which strips their metadata, and get resized variants for
+// previews.
+func (r *Resolver) storeAttachment(ctx context.Context, file graphql.Upload, a *attachments.Attachment) error {
+	if a.Kind == attachments.KindImage {
+		res, variants, err := r.storeImage(ctx, file.File, imaging.PurposePost)
+		if err != nil {
+			return err
+		}
+		full := variants[len(variants)-1]
+		a.URL = full.URL
+		a.Key = full.Key
+		a.Width = full.Width
+		a.Height = full.Height
+		a.ContentType = res.ContentType
+		a.Size = int64(len(res.Full().Data))
+		a.Variants = variants
+		a.StoredSize = res.TotalSize()
+	} else {
+		stored, err := r.Uploader.UploadFile(ctx, file.File, "wikinitt/attachments")
+		if err != nil {
+			return fmt.Errorf("failed to upload attachment: %w", err)
+		}
+		a.URL = stored.URL
+		a.Key = stored.Key
+		a.StoredSize = a.Size
+	}
+
+	if err := r.AttachmentRepo.Create(ctx, a); err != nil {
+		for _, key := range a.Keys() {
+			if err := r.Uploader.Delete(ctx, key); err != nil {
+				log.Printf("Failed to delete attachment file %s: %v", key, err)
+			}
+		}
+		return fmt.Errorf("failed to save attachment: %w", err)
+	}
+	return nil
+}
+
+// checkAttachments validates the attachment IDs of new content: each must be
+// an unattached upload of the user. It returns the IDs without duplicates.
+func (r *Resolver) checkAttachments(ctx context.Context, user *users.User, ids []string) ([]string, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, nil
+	}
+	if len(unique) > attachments.MaxPerSource {
+		return nil, fmt.Errorf("at most %d attachments are allowed", attachments.MaxPerSource)
+	}
+
+	list, err := r.AttachmentRepo.GetMany(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attachments: %w", err)
+	}
+	if len(list) != len(unique) {
+		return nil, fmt.Errorf("attachment not found")
+	}
+	for _, a := range list {
+		if a.OwnerID != user.ID {
+			return nil, fmt.Errorf("attachment not found")
+		}
+		if a.Attached() || a.DetachedAt != nil {
+			return nil, fmt.Errorf("attachment %s is already in use", a.Filename)
+		}
+	}
+	return unique, nil
+}
+
+// attach links checked attachments to content that was just created.
+func (r *Resolver) attach(ctx context.Context, user *users.User, ids []string, sourceType attachments.SourceType, sourceID, groupID string) {
+	if len(ids) == 0 {
+		return
+	}
+	if err := r.AttachmentRepo.Attach(ctx, ids, user.ID, sourceType, sourceID, groupID); err != nil {
+		log.Printf("Failed to attach files to %s %s: %v", sourceType, sourceID, err)
+	}
+}
+
+// detachAttachments hands the files of deleted content to the garbage
+// collector.
+func (r *Resolver) detachAttachments(ctx context.Context, sourceType attachments.SourceType, sourceID string) {
+	if err := r.AttachmentRepo.Detach(ctx, sourceType, sourceID); err != nil {
+		log.Printf("Failed to detach attachments of %s %s: %v", sourceType, sourceID, err)
+	}
+}
+
+// detachGroupAttachments collects the files of a deleted group's posts and
+// messages.
+func (r *Resolver) detachGroupAttachments(ctx context.Context, groupID string) {
+	if err := r.AttachmentRepo.DetachGroup(ctx, groupID); err != nil {
+		log.Printf("Failed to detach attachments of group %s: %v", groupID, err)
+	}
+}
+
+func (r *Resolver) attachmentsToModel(ctx context.Context, sourceType attachments.SourceType, sourceID string) ([]*model.Attachment, error) {
+	list, err := r.AttachmentRepo.ListForSource(ctx, sourceType, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attachments: %w", err)
+	}
+	result := make([]*model.Attachment, 0, len(list))
+	for _, a := range list {
+		result = append(result, mapAttachmentToModel(a))
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+)
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, filter *model.AuditLogFilter, cursor *string, limit *int32) (*model.AuditLogPage, error) {
+	l := 50
+	if limit != nil {
+		l = int(*limit)
+	}
+	if l <= 0 || l > maxAuditPageSize {
+		l = maxAuditPageSize
+	}
+
+	var f audit.Filter
+	after := ""
+	if cursor != nil {
+		after = *cursor
+	}
+	if filter != nil {
+		f.ActorID = filter.ActorID
+		f.Action = filter.Action
+		f.TargetType = filter.TargetType
+		f.TargetID = filter.TargetID
+		for _, d := range []struct {
+			in  *string
+			out **time.Time
+		}{{filter.Since, &f.Since}, {filter.Until, &f.Until}} {
+			if d.in == nil {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, *d.in)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date %q, expected RFC3339", *d.in)
+			}
+			*d.out = &t
+		}
+	}
+
+	entries, err := r.AuditRepo.List(ctx, f, after, l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit log: %w", err)
+	}
+
+	page := &model.AuditLogPage{Entries: make([]*model.AuditEntry, 0, len(entries))}
+	for _, e := range entries {
+		page.Entries = append(page.Entries, mapAuditEntryToModel(e))
+	}
+	if len(entries) == l {
+		next := entries[len(entries)-1].ID
+		page.NextCursor = &next
+	}
+	return page, nil
+}
@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"context"
+	"log"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+const maxAuditPageSize = 200
+
+// recordAudit appends a privileged action to the audit log. A failure to
+// record is logged rather than failing an action that already happened.
+func (r *Resolver) recordAudit(ctx context.Context, action, targetType, targetID string, before, after interface{}) {
+	entry := &audit.Entry{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     audit.Snapshot(before),
+		After:      audit.Snapshot(after),
+	}
+	if actor := auth.ForContext(ctx); actor != nil {
+		entry.ActorID = actor.ID
+	}
+	if token := auth.APITokenForContext(ctx); token != nil {
+		entry.APITokenID = token.ID
+	}
+	if err := r.AuditRepo.Record(ctx, entry); err != nil {
+		log.Printf("Failed to record audit entry %s for %s %s: %v", action, targetType, targetID, err)
+	}
+}
+
+// userSnapshot leaves out credentials and 2FA secrets.
+func userSnapshot(u *users.User) interface{} {
+	if u == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"_id":      u.ID,
+		"username": u.Username,
+		"email":    u.Email,
+		"isAdmin":  u.IsAdmin,
+		"roles":    u.Roles,
+		"isBanned": u.IsBanned,
+	}
+}
+
+// groupSnapshot leaves out the invite token and member lists.
+func groupSnapshot(g *community.Group) interface{} {
+	if g == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"_id":             g.ID,
+		"name":            g.Name,
+		"description":     g.Description,
+		"slug":            g.Slug,
+		"type":            g.Type,
+		"ownerId":         g.OwnerID,
+		"moderatorIds":    g.ModeratorIDs,
+		"membersCount":    g.MembersCount,
+		"icon":            g.Icon,
+		"minPostKarma":    g.MinPostKarma,
+		"minCommentKarma": g.MinCommentKarma,
+	}
+}
+
+func memberSnapshot(groupID, userID string) interface{} {
+	return map[string]interface{}{
+		"groupId": groupID,
+		"userId":  userID,
+	}
+}
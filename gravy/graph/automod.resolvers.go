@@ -0,0 +1,178 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/automod"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+)
+
+// CreateAutomodRule is the resolver for the createAutomodRule field.
+func (r *mutationResolver) CreateAutomodRule(ctx context.Context, input model.NewAutomodRule) (*model.AutomodRule, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	rule := &automod.Rule{
+		Type:      automod.RuleType(input.Type),
+		Action:    automod.Action(input.Action),
+		Enabled:   input.Enabled == nil || *input.Enabled,
+		CreatedBy: user.ID,
+	}
+	if input.GroupID != nil {
+		if _, err := r.CommunityRepo.GetGroupByID(ctx, *input.GroupID); err != nil {
+			return nil, fmt.Errorf("group not found")
+		}
+		rule.GroupID = *input.GroupID
+	}
+	if !r.canManageAutomod(ctx, user, rule.GroupID) {
+		return nil, fmt.Errorf("access denied")
+	}
+	applyAutomodRuleInput(rule, input.Words, input.Pattern, input.MaxLinks, input.AccountAgeHours, input.DuplicateWindowMinutes, input.MinKarma)
+	if err := automod.Validate(rule); err != nil {
+		return nil, err
+	}
+
+	if err := r.AutomodRepo.CreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create rule: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionAutomodRuleCreate, audit.TargetAutomodRule, rule.ID, nil, rule)
+	return mapAutomodRuleToModel(rule), nil
+}
+
+// UpdateAutomodRule is the resolver for the updateAutomodRule field.
+func (r *mutationResolver) UpdateAutomodRule(ctx context.Context, id string, input model.UpdateAutomodRule) (*model.AutomodRule, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	rule, err := r.AutomodRepo.GetRule(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("rule not found")
+	}
+	if !r.canManageAutomod(ctx, user, rule.GroupID) {
+		return nil, fmt.Errorf("access denied")
+	}
+	before := *rule
+
+	if input.Action != nil {
+		rule.Action = automod.Action(*input.Action)
+	}
+	if input.Enabled != nil {
+		rule.Enabled = *input.Enabled
+	}
+	applyAutomodRuleInput(rule, input.Words, input.Pattern, input.MaxLinks, input.AccountAgeHours, input.DuplicateWindowMinutes, input.MinKarma)
+	if err := automod.Validate(rule); err != nil {
+		return nil, err
+	}
+
+	if err := r.AutomodRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update rule: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionAutomodRuleUpdate, audit.TargetAutomodRule, rule.ID, before, rule)
+	return mapAutomodRuleToModel(rule), nil
+}
+
+// DeleteAutomodRule is the resolver for the deleteAutomodRule field.
+func (r *mutationResolver) DeleteAutomodRule(ctx context.Context, id string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+
+	rule, err := r.AutomodRepo.GetRule(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("rule not found")
+	}
+	if !r.canManageAutomod(ctx, user, rule.GroupID) {
+		return false, fmt.Errorf("access denied")
+	}
+
+	if err := r.AutomodRepo.DeleteRule(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to delete rule: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionAutomodRuleDelete, audit.TargetAutomodRule, id, rule, nil)
+	return true, nil
+}
+
+// AutomodRules is the resolver for the automodRules field.
+func (r *queryResolver) AutomodRules(ctx context.Context, groupID *string) ([]*model.AutomodRule, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	groupIDValue := ""
+	if groupID != nil {
+		groupIDValue = *groupID
+	}
+	if !r.canManageAutomod(ctx, user, groupIDValue) {
+		return nil, fmt.Errorf("access denied")
+	}
+
+	rules, err := r.AutomodRepo.ListRules(ctx, groupIDValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rules: %w", err)
+	}
+	result := make([]*model.AutomodRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, mapAutomodRuleToModel(rule))
+	}
+	return result, nil
+}
+
+// AutomodLog is the resolver for the automodLog field.
+func (r *queryResolver) AutomodLog(ctx context.Context, groupID *string, limit *int32, offset *int32) ([]*model.AutomodTrigger, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	l := 50
+	o := 0
+	if limit != nil && *limit > 0 && *limit <= 200 {
+		l = int(*limit)
+	}
+	if offset != nil {
+		o = int(*offset)
+	}
+
+	var groupIDs []string
+	switch {
+	case groupID != nil:
+		if !r.canModerateGroup(ctx, user, *groupID) {
+			return nil, fmt.Errorf("access denied")
+		}
+		groupIDs = []string{*groupID}
+	case auth.RequirePermission(ctx, user, roles.CommunityModerate) != nil:
+		managed, err := r.managedGroupIDs(ctx, user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load groups: %w", err)
+		}
+		if len(managed) == 0 {
+			return []*model.AutomodTrigger{}, nil
+		}
+		groupIDs = managed
+	}
+
+	triggers, err := r.AutomodRepo.ListTriggers(ctx, groupIDs, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load automod log: %w", err)
+	}
+	result := make([]*model.AutomodTrigger, 0, len(triggers))
+	for _, t := range triggers {
+		result = append(result, r.automodTriggerToModel(ctx, t))
+	}
+	return result, nil
+}
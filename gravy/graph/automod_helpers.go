@@ -0,0 +1,195 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/automod"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/reports"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+// automodReporterID files the report cases automod opens.
+const automodReporterID = "automod"
+
+// checkAutomod runs the automod rules over new content and rejects it when a
+// REJECT rule fired.
+func (r *Resolver) checkAutomod(ctx context.Context, user *users.User, kind automod.ContentKind, groupID, text string) (*automod.Decision, error) {
+	authorKarma, err := r.KarmaRepo.Total(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check content: %w", err)
+	}
+	decision, err := r.Automod.Check(ctx, automod.Content{
+		Kind:            kind,
+		GroupID:         groupID,
+		AuthorID:        user.ID,
+		AuthorCreatedAt: user.CreatedAt,
+		AuthorKarma:     authorKarma,
+		Text:            text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check content: %w", err)
+	}
+	if decision.Action == automod.ActionReject {
+		return nil, fmt.Errorf("your %s was blocked by automod: %s", kind.Noun(), decision.Reason())
+	}
+	return decision, nil
+}
+
+// finishAutomod records the check of content that has been saved and puts
+// flagged or held content in the moderation queue.
+func (r *Resolver) finishAutomod(ctx context.Context, decision *automod.Decision, target reports.Target) {
+	if err := r.Automod.Record(ctx, decision, target.ID); err != nil {
+		log.Printf("Failed to record automod check of %s %s: %v", target.Type, target.ID, err)
+	}
+	if !decision.Triggered() {
+		return
+	}
+	_, err := r.ReportRepo.Add(ctx, target, reports.Entry{
+		ReporterID: automodReporterID,
+		Reason:     reports.ReasonAutomod,
+		Note:       decision.Reason(),
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		log.Printf("Failed to open automod report for %s %s: %v", target.Type, target.ID, err)
+	}
+}
+
+// releaseHeldContent publishes content automod held once a moderator
+// dismisses its report, and sends the notifications and webhooks that were
+// held back with it.
+func (r *Resolver) releaseHeldContent(ctx context.Context, c *reports.Case) error {
+	switch c.TargetType {
+	case reports.TargetPost:
+		if err := r.CommunityRepo.ReleasePost(ctx, c.TargetID); err != nil {
+			return err
+		}
+		r.announceReleasedPost(ctx, c.TargetID)
+	case reports.TargetComment:
+		if err := r.CommunityRepo.ReleaseComment(ctx, c.TargetID); err != nil {
+			return err
+		}
+		r.announceReleasedComment(ctx, c.TargetID)
+	case reports.TargetMessage:
+		if err := r.CommunityRepo.ReleaseMessage(ctx, c.TargetID); err != nil {
+			return err
+		}
+		r.announceReleasedMessage(ctx, c.TargetID)
+	}
+	return nil
+}
+
+// announceReleasedPost sends the mention notifications and POST_CREATED
+// webhook that were skipped while automod held the post.
+func (r *Resolver) announceReleasedPost(ctx context.Context, postID string) {
+	post, err := r.CommunityRepo.GetPost(ctx, postID)
+	if err != nil {
+		return
+	}
+	group, err := r.CommunityRepo.GetGroupByID(ctx, post.GroupID)
+	if err != nil {
+		return
+	}
+	if author, err := r.UserRepo.GetByID(ctx, post.AuthorID); err == nil {
+		r.notifyHeldMentions(ctx, author, postMentionSource(post, group))
+	}
+	r.publishPostWebhook(ctx, group, post)
+}
+
+// announceReleasedComment also sends the reply notification.
+func (r *Resolver) announceReleasedComment(ctx context.Context, commentID string) {
+	comment, err := r.CommunityRepo.GetComment(ctx, commentID)
+	if err != nil {
+		return
+	}
+	post, err := r.CommunityRepo.GetPost(ctx, comment.PostID)
+	if err != nil {
+		return
+	}
+	group, err := r.CommunityRepo.GetGroupByID(ctx, post.GroupID)
+	if err != nil {
+		return
+	}
+	if author, err := r.UserRepo.GetByID(ctx, comment.AuthorID); err == nil {
+		r.notifyReply(ctx, author, post, comment)
+		r.notifyHeldMentions(ctx, author, commentMentionSource(comment, post, group))
+	}
+	r.publishCommentWebhook(ctx, group, post, comment)
+}
+
+func (r *Resolver) announceReleasedMessage(ctx context.Context, messageID string) {
+	message, err := r.CommunityRepo.GetMessage(ctx, messageID)
+	if err != nil {
+		return
+	}
+	channel, err := r.CommunityRepo.GetChannel(ctx, message.ChannelID)
+	if err != nil || channel == nil {
+		return
+	}
+	group, err := r.messageGroup(ctx, message)
+	if err != nil {
+		return
+	}
+	if author, err := r.UserRepo.GetByID(ctx, message.SenderID); err == nil {
+		r.notifyHeldMentions(ctx, author, messageMentionSource(message, channel, group))
+	}
+}
+
+// canViewHeld lets only the author and the group's moderators see content
+// automod is holding.
+func (r *Resolver) canViewHeld(ctx context.Context, authorID, groupID string) bool {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false
+	}
+	return user.ID == authorID || r.canModerateGroup(ctx, user, groupID)
+}
+
+// canManageAutomod allows site moderators to manage global rules and group
+// moderators to manage the rules of their group.
+func (r *Resolver) canManageAutomod(ctx context.Context, user *users.User, groupID string) bool {
+	if groupID == "" {
+		return auth.RequirePermission(ctx, user, roles.CommunityModerate) == nil
+	}
+	return r.canModerateGroup(ctx, user, groupID)
+}
+
+func applyAutomodRuleInput(rule *automod.Rule, words []string, pattern *string, maxLinks, accountAgeHours, duplicateWindowMinutes, minKarma *int32) {
+	if words != nil {
+		rule.Words = make([]string, 0, len(words))
+		for _, w := range words {
+			if w != "" {
+				rule.Words = append(rule.Words, w)
+			}
+		}
+	}
+	if pattern != nil {
+		rule.Pattern = *pattern
+	}
+	if maxLinks != nil {
+		rule.MaxLinks = int(*maxLinks)
+	}
+	if accountAgeHours != nil {
+		rule.AccountAgeHours = int(*accountAgeHours)
+	}
+	if duplicateWindowMinutes != nil {
+		rule.DuplicateWindowMinutes = int(*duplicateWindowMinutes)
+	}
+	if minKarma != nil {
+		rule.MinKarma = int(*minKarma)
+	}
+}
+
+func (r *Resolver) automodTriggerToModel(ctx context.Context, t *automod.Trigger) *model.AutomodTrigger {
+	var author *users.PublicUser
+	if u, err := r.UserRepo.GetByID(ctx, t.AuthorID); err == nil {
+		author = mapUserToPublic(u)
+	}
+	return mapAutomodTriggerToModel(t, author)
+}
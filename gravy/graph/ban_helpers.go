@@ -0,0 +1,24 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/bans"
+)
+
+const maxBanAppealLength = 2000
+
+// banAppealToModel loads the ban and author of an appeal for the queue.
+func (r *Resolver) banAppealToModel(ctx context.Context, appeal *bans.Appeal) (*model.BanAppeal, error) {
+	ban, err := r.BanRepo.GetByID(ctx, appeal.BanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ban: %w", err)
+	}
+	user, err := r.UserRepo.GetByID(ctx, appeal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	return mapBanAppealToModel(appeal, ban, mapUserToPublic(user)), nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/articles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
 )
 
 // CreateCategory is the resolver for the createCategory field.
@@ -24,6 +25,7 @@ func (r *mutationResolver) CreateCategory(ctx context.Context, name string) (*mo
 	if err != nil {
 		return nil, err
 	}
+	r.recordAudit(ctx, audit.ActionCategoryCreate, audit.TargetCategory, category.ID, nil, category)
 
 	return &model.Category{
 		ID:        category.ID,
@@ -35,10 +37,16 @@ func (r *mutationResolver) CreateCategory(ctx context.Context, name string) (*mo
 
 // DeleteCategory is the resolver for the deleteCategory field.
 func (r *mutationResolver) DeleteCategory(ctx context.Context, id string) (bool, error) {
-	err := r.CategoryRepo.Delete(ctx, id)
+	existing, err := r.CategoryRepo.GetByID(ctx, id)
 	if err != nil {
 		return false, err
 	}
+
+	err = r.CategoryRepo.Delete(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	r.recordAudit(ctx, audit.ActionCategoryDelete, audit.TargetCategory, id, existing, nil)
 	return true, nil
 }
 
@@ -11,8 +11,15 @@ import (
 	"time"
 
 	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/attachments"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/automod"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mentions"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/polls"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/reports"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/sanitization"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
 )
@@ -109,6 +116,42 @@ func (r *commentResolver) UserVote(ctx context.Context, obj *model.Comment) (mod
 	}
 }
 
+// Moderators is the resolver for the moderators field.
+func (r *groupResolver) Moderators(ctx context.Context, obj *model.Group) ([]*model.PublicUser, error) {
+	g, err := r.CommunityRepo.GetGroupByID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	moderators := make([]*model.PublicUser, 0, len(g.ModeratorIDs))
+	for _, uid := range g.ModeratorIDs {
+		u, err := r.UserRepo.GetByID(ctx, uid)
+		if err == nil {
+			moderators = append(moderators, mapPublicUserToModel(mapUserToPublic(u)))
+		}
+	}
+	return moderators, nil
+}
+
+// ViewerRole is the resolver for the viewerRole field.
+func (r *groupResolver) ViewerRole(ctx context.Context, obj *model.Group) (*model.GroupRole, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, nil
+	}
+
+	g, err := r.CommunityRepo.GetGroupByID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	role := g.RoleOf(user.ID)
+	if role == "" {
+		return nil, nil
+	}
+	result := model.GroupRole(role)
+	return &result, nil
+}
+
 // IsMember is the resolver for the isMember field.
 func (r *groupResolver) IsMember(ctx context.Context, obj *model.Group) (bool, error) {
 	user := auth.ForContext(ctx)
@@ -119,7 +162,7 @@ func (r *groupResolver) IsMember(ctx context.Context, obj *model.Group) (bool, e
 }
 
 // Posts is the resolver for the posts field.
-func (r *groupResolver) Posts(ctx context.Context, obj *model.Group, limit *int32, offset *int32) ([]*model.Post, error) {
+func (r *groupResolver) Posts(ctx context.Context, obj *model.Group, limit *int32, offset *int32, sort *model.ContentSort, window *model.TimeWindow) ([]*model.Post, error) {
 	l := 10
 	o := 0
 	if limit != nil {
@@ -143,7 +186,7 @@ func (r *groupResolver) Posts(ctx context.Context, obj *model.Group, limit *int3
 		}
 	}
 
-	posts, err := r.CommunityRepo.ListPosts(ctx, obj.ID, l, o)
+	posts, err := r.CommunityRepo.ListPosts(ctx, obj.ID, sortFromModel(sort, window), l, o)
 	if err != nil {
 		return nil, err
 	}
@@ -183,13 +226,9 @@ func (r *groupResolver) InviteToken(ctx context.Context, obj *model.Group) (*str
 		return nil, nil
 	}
 
-	if user.ID != obj.Owner.ID {
-		return nil, nil // Only owner can see
-	}
-
 	g, err := r.CommunityRepo.GetGroupByID(ctx, obj.ID)
-	if err != nil {
-		return nil, nil
+	if err != nil || !g.Can(user.ID, community.GroupManageMembers) {
+		return nil, nil // Only owner and moderators can see
 	}
 	if g.InviteToken == "" {
 		return nil, nil
@@ -204,13 +243,9 @@ func (r *groupResolver) JoinRequests(ctx context.Context, obj *model.Group) ([]*
 		return nil, nil
 	}
 
-	if user.ID != obj.Owner.ID {
-		return nil, nil // Only owner can see
-	}
-
 	g, err := r.CommunityRepo.GetGroupByID(ctx, obj.ID)
-	if err != nil {
-		return nil, nil
+	if err != nil || !g.Can(user.ID, community.GroupManageMembers) {
+		return nil, nil // Only owner and moderators can see
 	}
 
 	var requests []*model.PublicUser
@@ -271,6 +306,21 @@ func (r *groupResolver) HasPendingRequest(ctx context.Context, obj *model.Group)
 	return false, nil
 }
 
+// ViewerMutedUntil is the resolver for the viewerMutedUntil field.
+func (r *groupResolver) ViewerMutedUntil(ctx context.Context, obj *model.Group) (*string, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, nil
+	}
+
+	mute, err := r.CommunityRepo.ActiveSanction(ctx, obj.ID, user.ID, community.SanctionMute)
+	if err != nil || mute == nil || mute.ExpiresAt == nil {
+		return nil, err
+	}
+	until := mute.ExpiresAt.Format("2006-01-02 15:04:05")
+	return &until, nil
+}
+
 // CreateGroup is the resolver for the createGroup field.
 func (r *mutationResolver) CreateGroup(ctx context.Context, input model.NewGroup) (*model.Group, error) {
 	user := auth.ForContext(ctx)
@@ -306,6 +356,7 @@ func (r *mutationResolver) CreateGroup(ctx context.Context, input model.NewGroup
 	if err != nil {
 		return nil, err
 	}
+	r.trackMedia(ctx, media.RefGroup, group.ID, group.Icon)
 
 	owner := &users.PublicUser{
 		ID:          user.ID,
@@ -335,11 +386,15 @@ func (r *mutationResolver) JoinGroup(ctx context.Context, groupID string) (bool,
 	if group.Type == community.GroupTypePrivate {
 		return false, fmt.Errorf("cannot join private group directly")
 	}
+	if err := r.checkGroupBan(ctx, user.ID, groupID); err != nil {
+		return false, err
+	}
 
 	err = r.CommunityRepo.JoinGroup(ctx, groupID, user.ID)
 	if err != nil {
 		return false, err
 	}
+	r.publishMemberWebhook(ctx, group, user.ID)
 	return true, nil
 }
 
@@ -350,7 +405,15 @@ func (r *mutationResolver) LeaveGroup(ctx context.Context, groupID string) (bool
 		return false, fmt.Errorf("not authenticated")
 	}
 
-	err := r.CommunityRepo.LeaveGroup(ctx, groupID, user.ID)
+	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return false, err
+	}
+	if group.OwnerID == user.ID {
+		return false, fmt.Errorf("transfer ownership before leaving the group")
+	}
+
+	err = r.CommunityRepo.LeaveGroup(ctx, groupID, user.ID)
 	if err != nil {
 		return false, err
 	}
@@ -371,6 +434,16 @@ func (r *mutationResolver) CreatePost(ctx context.Context, input model.NewPost)
 	if !isMember {
 		return nil, fmt.Errorf("must be a member of the group to create a post")
 	}
+	if err := r.checkCanParticipate(ctx, user, input.GroupID); err != nil {
+		return nil, err
+	}
+	group, err := r.CommunityRepo.GetGroupByID(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkKarmaRequirement(ctx, user, group, group.MinPostKarma, "post"); err != nil {
+		return nil, err
+	}
 
 	post := &community.Post{
 		Title:     input.Title,
@@ -379,16 +452,55 @@ func (r *mutationResolver) CreatePost(ctx context.Context, input model.NewPost)
 		GroupID:   input.GroupID,
 		CreatedAt: time.Now(),
 	}
+	if input.Announcement != nil && *input.Announcement {
+		if !r.canModerateGroup(ctx, user, input.GroupID) {
+			return nil, fmt.Errorf("access denied: only group owners and moderators can post announcements")
+		}
+		post.Announcement = true
+	}
+	attachmentIDs, err := r.checkAttachments(ctx, user, input.AttachmentIds)
+	if err != nil {
+		return nil, err
+	}
+	var poll *polls.Poll
+	if input.Poll != nil {
+		poll, err = newPollFromInput(input.Poll)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	err = r.CommunityRepo.CreatePost(ctx, post)
+	text := post.Title + "\n\n" + post.Content
+	if poll != nil {
+		for _, o := range poll.Options {
+			text += "\n" + o.Text
+		}
+	}
+	decision, err := r.checkAutomod(ctx, user, automod.KindPost, post.GroupID, text)
 	if err != nil {
 		return nil, err
 	}
+	post.Held = decision.Action == automod.ActionHold
 
-	group, err := r.CommunityRepo.GetGroupByID(ctx, input.GroupID)
+	err = r.CommunityRepo.CreatePost(ctx, post)
 	if err != nil {
 		return nil, err
 	}
+	if poll != nil {
+		poll.PostID = post.ID
+		if err := r.PollRepo.Create(ctx, poll); err != nil {
+			_ = r.CommunityRepo.DeletePost(ctx, post.ID)
+			return nil, fmt.Errorf("failed to create poll: %w", err)
+		}
+	}
+	r.attach(ctx, user, attachmentIDs, attachments.SourcePost, post.ID, post.GroupID)
+	r.trackMedia(ctx, media.RefPost, post.ID, post.Content)
+	r.finishAutomod(ctx, decision, reports.Target{Type: reports.TargetPost, ID: post.ID, AuthorID: user.ID, GroupID: post.GroupID})
+	r.updateMentions(ctx, user, postMentionSource(post, group), post.Title+"\n\n"+post.Content)
+	if !post.Held {
+		r.publishPostWebhook(ctx, group, post)
+	}
+
 	groupOwner, _ := r.UserRepo.GetByID(ctx, group.OwnerID)
 	groupOwnerPublic := &users.PublicUser{
 		ID:          groupOwner.ID,
@@ -418,6 +530,24 @@ func (r *mutationResolver) CreateComment(ctx context.Context, input model.NewCom
 		return nil, fmt.Errorf("not authenticated")
 	}
 
+	post, err := r.CommunityRepo.GetPost(ctx, input.PostID)
+	if err != nil {
+		return nil, fmt.Errorf("post not found")
+	}
+	if err := r.checkCanParticipate(ctx, user, post.GroupID); err != nil {
+		return nil, err
+	}
+	if post.Locked && !r.canModerateGroup(ctx, user, post.GroupID) {
+		return nil, fmt.Errorf("post is locked")
+	}
+	group, err := r.CommunityRepo.GetGroupByID(ctx, post.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("group not found")
+	}
+	if err := r.checkKarmaRequirement(ctx, user, group, group.MinCommentKarma, "comment"); err != nil {
+		return nil, err
+	}
+
 	comment := &community.Comment{
 		Content:   sanitization.SanitizeContent(input.Content),
 		AuthorID:  user.ID,
@@ -426,12 +556,24 @@ func (r *mutationResolver) CreateComment(ctx context.Context, input model.NewCom
 		CreatedAt: time.Now(),
 	}
 
-	err := r.CommunityRepo.CreateComment(ctx, comment)
+	decision, err := r.checkAutomod(ctx, user, automod.KindComment, post.GroupID, comment.Content)
 	if err != nil {
 		return nil, err
 	}
+	comment.Held = decision.Action == automod.ActionHold
+
+	err = r.CommunityRepo.CreateComment(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	r.finishAutomod(ctx, decision, reports.Target{Type: reports.TargetComment, ID: comment.ID, AuthorID: user.ID, GroupID: post.GroupID})
+	if !comment.Held {
+		r.notifyReply(ctx, user, post, comment)
+		r.publishCommentWebhook(ctx, group, post, comment)
+	}
+	r.updateMentions(ctx, user, commentMentionSource(comment, post, group), comment.Content)
+	r.trackMedia(ctx, media.RefComment, comment.ID, comment.Content)
 
-	post, _ := r.CommunityRepo.GetPost(ctx, input.PostID)
 	postAuthor, _ := r.UserRepo.GetByID(ctx, post.AuthorID)
 	postAuthorPublic := &users.PublicUser{
 		ID:          postAuthor.ID,
@@ -441,7 +583,6 @@ func (r *mutationResolver) CreateComment(ctx context.Context, input model.NewCom
 		Gender:      postAuthor.Gender,
 		Avatar:      postAuthor.Avatar,
 	}
-	group, _ := r.CommunityRepo.GetGroupByID(ctx, post.GroupID)
 	groupOwner, _ := r.UserRepo.GetByID(ctx, group.OwnerID)
 	groupOwnerPublic := &users.PublicUser{
 		ID:          groupOwner.ID,
@@ -471,7 +612,15 @@ func (r *mutationResolver) VotePost(ctx context.Context, postID string, typeArg
 		return nil, fmt.Errorf("not authenticated")
 	}
 
-	err := r.CommunityRepo.VotePost(ctx, user.ID, postID, typeArg.String())
+	post, err := r.CommunityRepo.GetPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("post not found")
+	}
+	if post.Locked {
+		return nil, fmt.Errorf("post is locked")
+	}
+
+	err = r.CommunityRepo.VotePost(ctx, user.ID, postID, typeArg.String())
 	if err != nil {
 		return nil, err
 	}
@@ -486,7 +635,15 @@ func (r *mutationResolver) VoteComment(ctx context.Context, commentID string, ty
 		return nil, fmt.Errorf("not authenticated")
 	}
 
-	err := r.CommunityRepo.VoteComment(ctx, user.ID, commentID, typeArg.String())
+	comment, err := r.CommunityRepo.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("comment not found")
+	}
+	if post, err := r.CommunityRepo.GetPost(ctx, comment.PostID); err == nil && post.Locked {
+		return nil, fmt.Errorf("post is locked")
+	}
+
+	err = r.CommunityRepo.VoteComment(ctx, user.ID, commentID, typeArg.String())
 	if err != nil {
 		return nil, err
 	}
@@ -544,25 +701,42 @@ func (r *mutationResolver) VoteComment(ctx context.Context, commentID string, ty
 }
 
 // UpdateGroup is the resolver for the updateGroup field.
-func (r *mutationResolver) UpdateGroup(ctx context.Context, groupID string, name *string, description *string, icon *string) (*model.Group, error) {
+func (r *mutationResolver) UpdateGroup(ctx context.Context, groupID string, name *string, description *string, icon *string, minPostKarma *int32, minCommentKarma *int32) (*model.Group, error) {
 	user := auth.ForContext(ctx)
 	if user == nil {
 		return nil, fmt.Errorf("not authenticated")
 	}
 
-	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	group, err := r.authorizeGroup(ctx, user, groupID, community.GroupEdit)
 	if err != nil {
 		return nil, err
 	}
 
-	if group.OwnerID != user.ID {
-		return nil, fmt.Errorf("access denied: only group owner can edit")
+	var minPost, minComment *int
+	if minPostKarma != nil {
+		if *minPostKarma < 0 {
+			return nil, fmt.Errorf("minimum karma can't be negative")
+		}
+		v := int(*minPostKarma)
+		minPost = &v
+	}
+	if minCommentKarma != nil {
+		if *minCommentKarma < 0 {
+			return nil, fmt.Errorf("minimum karma can't be negative")
+		}
+		v := int(*minCommentKarma)
+		minComment = &v
+	}
+	if err := r.CommunityRepo.SetKarmaRequirements(ctx, groupID, minPost, minComment); err != nil {
+		return nil, err
 	}
 
 	updatedGroup, err := r.CommunityRepo.UpdateGroup(ctx, groupID, name, description, icon)
 	if err != nil {
 		return nil, err
 	}
+	r.recordAudit(ctx, audit.ActionGroupUpdate, audit.TargetGroup, groupID, groupSnapshot(group), groupSnapshot(updatedGroup))
+	r.trackMedia(ctx, media.RefGroup, groupID, updatedGroup.Icon)
 
 	owner, _ := r.UserRepo.GetByID(ctx, updatedGroup.OwnerID)
 	return mapGroupToModel(updatedGroup, mapUserToPublic(owner)), nil
@@ -575,16 +749,16 @@ func (r *mutationResolver) GenerateGroupInvite(ctx context.Context, groupID stri
 		return "", fmt.Errorf("not authenticated")
 	}
 
-	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
-	if err != nil {
+	if _, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageMembers); err != nil {
 		return "", err
 	}
 
-	if group.OwnerID != user.ID {
-		return "", fmt.Errorf("access denied: only owner can generate invite")
+	token, err := r.CommunityRepo.GenerateInviteToken(ctx, groupID)
+	if err != nil {
+		return "", err
 	}
-
-	return r.CommunityRepo.GenerateInviteToken(ctx, groupID)
+	r.recordAudit(ctx, audit.ActionGroupInvite, audit.TargetGroup, groupID, nil, nil)
+	return token, nil
 }
 
 // RequestJoinGroup is the resolver for the requestJoinGroup field.
@@ -602,6 +776,9 @@ func (r *mutationResolver) RequestJoinGroup(ctx context.Context, groupID string,
 	if group.ID != groupID {
 		return false, fmt.Errorf("invalid token for this group")
 	}
+	if err := r.checkGroupBan(ctx, user.ID, groupID); err != nil {
+		return false, err
+	}
 
 	// Check if already member
 	isMember, _ := r.CommunityRepo.IsMember(ctx, groupID, user.ID)
@@ -613,6 +790,7 @@ func (r *mutationResolver) RequestJoinGroup(ctx context.Context, groupID string,
 	if err != nil {
 		return false, err
 	}
+	r.notifyJoinRequest(ctx, user, group)
 
 	return true, nil
 }
@@ -624,13 +802,16 @@ func (r *mutationResolver) AcceptJoinRequest(ctx context.Context, groupID string
 		return false, fmt.Errorf("not authenticated")
 	}
 
-	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	group, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageMembers)
 	if err != nil {
 		return false, err
 	}
-
-	if group.OwnerID != user.ID {
-		return false, fmt.Errorf("access denied")
+	ban, err := r.CommunityRepo.ActiveSanction(ctx, groupID, userID, community.SanctionBan)
+	if err != nil {
+		return false, err
+	}
+	if ban != nil {
+		return false, fmt.Errorf("user is banned from this group")
 	}
 
 	err = r.CommunityRepo.JoinGroup(ctx, groupID, userID)
@@ -639,6 +820,9 @@ func (r *mutationResolver) AcceptJoinRequest(ctx context.Context, groupID string
 	}
 
 	_ = r.CommunityRepo.RemoveJoinRequest(ctx, groupID, userID)
+	r.recordAudit(ctx, audit.ActionGroupAcceptRequest, audit.TargetGroup, groupID, nil, memberSnapshot(groupID, userID))
+	r.notifyRequestAccepted(ctx, user, group, userID)
+	r.publishMemberWebhook(ctx, group, userID)
 	return true, nil
 }
 
@@ -649,19 +833,16 @@ func (r *mutationResolver) RejectJoinRequest(ctx context.Context, groupID string
 		return false, fmt.Errorf("not authenticated")
 	}
 
-	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	_, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageMembers)
 	if err != nil {
 		return false, err
 	}
 
-	if group.OwnerID != user.ID {
-		return false, fmt.Errorf("access denied")
-	}
-
 	err = r.CommunityRepo.RemoveJoinRequest(ctx, groupID, userID)
 	if err != nil {
 		return false, err
 	}
+	r.recordAudit(ctx, audit.ActionGroupRejectRequest, audit.TargetGroup, groupID, memberSnapshot(groupID, userID), nil)
 	return true, nil
 }
 
@@ -672,23 +853,199 @@ func (r *mutationResolver) RemoveMember(ctx context.Context, groupID string, use
 		return false, fmt.Errorf("not authenticated")
 	}
 
-	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	group, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageMembers)
 	if err != nil {
 		return false, err
 	}
 
-	if group.OwnerID != user.ID {
-		return false, fmt.Errorf("access denied")
-	}
-
 	if group.OwnerID == userID {
 		return false, fmt.Errorf("cannot remove owner")
 	}
+	if !group.Outranks(user.ID, userID) {
+		return false, fmt.Errorf("access denied: only the owner can remove moderators")
+	}
 
 	err = r.CommunityRepo.RemoveMember(ctx, groupID, userID)
 	if err != nil {
 		return false, err
 	}
+	r.recordAudit(ctx, audit.ActionGroupRemoveMember, audit.TargetGroup, groupID, memberSnapshot(groupID, userID), nil)
+	return true, nil
+}
+
+// AddGroupModerator is the resolver for the addGroupModerator field.
+func (r *mutationResolver) AddGroupModerator(ctx context.Context, groupID string, userID string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+
+	group, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageModerators)
+	if err != nil {
+		return false, err
+	}
+	switch group.RoleOf(userID) {
+	case community.GroupRoleModerator:
+		return true, nil
+	case community.GroupRoleMember:
+	default:
+		return false, fmt.Errorf("user is not a member of the group")
+	}
+
+	if err := r.CommunityRepo.AddModerator(ctx, groupID, userID); err != nil {
+		return false, err
+	}
+	r.recordAudit(ctx, audit.ActionGroupAddModerator, audit.TargetGroup, groupID, nil, memberSnapshot(groupID, userID))
+	return true, nil
+}
+
+// RemoveGroupModerator is the resolver for the removeGroupModerator field.
+func (r *mutationResolver) RemoveGroupModerator(ctx context.Context, groupID string, userID string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+
+	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return false, fmt.Errorf("group not found")
+	}
+	if userID != user.ID && !group.Can(user.ID, community.GroupManageModerators) {
+		return false, fmt.Errorf("access denied")
+	}
+	if group.RoleOf(userID) != community.GroupRoleModerator {
+		return false, fmt.Errorf("user is not a moderator of the group")
+	}
+
+	if err := r.CommunityRepo.RemoveModerator(ctx, groupID, userID); err != nil {
+		return false, err
+	}
+	r.recordAudit(ctx, audit.ActionGroupRemoveModerator, audit.TargetGroup, groupID, memberSnapshot(groupID, userID), nil)
+	return true, nil
+}
+
+// TransferGroupOwnership is the resolver for the transferGroupOwnership field.
+func (r *mutationResolver) TransferGroupOwnership(ctx context.Context, groupID string, userID string) (*model.Group, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	group, err := r.authorizeGroup(ctx, user, groupID, community.GroupTransfer)
+	if err != nil {
+		return nil, err
+	}
+	if userID == user.ID {
+		return nil, fmt.Errorf("you already own this group")
+	}
+	if group.RoleOf(userID) == "" {
+		return nil, fmt.Errorf("new owner must be a member of the group")
+	}
+
+	updatedGroup, err := r.CommunityRepo.TransferOwnership(ctx, groupID, user.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	r.recordAudit(ctx, audit.ActionGroupTransfer, audit.TargetGroup, groupID, groupSnapshot(group), groupSnapshot(updatedGroup))
+
+	owner, _ := r.UserRepo.GetByID(ctx, updatedGroup.OwnerID)
+	return mapGroupToModel(updatedGroup, mapUserToPublic(owner)), nil
+}
+
+// BanGroupMember is the resolver for the banGroupMember field.
+func (r *mutationResolver) BanGroupMember(ctx context.Context, groupID string, userID string, reason *string, durationHours *int32) (*model.GroupSanction, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	ban, err := r.sanctionGroupMember(ctx, user, groupID, userID, community.SanctionBan, reason, durationHours)
+	if err != nil {
+		return nil, err
+	}
+
+	// Banned users lose their membership and any pending request.
+	isMember, _ := r.CommunityRepo.IsMember(ctx, groupID, userID)
+	if isMember {
+		if err := r.CommunityRepo.RemoveMember(ctx, groupID, userID); err != nil {
+			return nil, err
+		}
+	}
+	_ = r.CommunityRepo.RemoveJoinRequest(ctx, groupID, userID)
+	r.recordAudit(ctx, audit.ActionGroupBan, audit.TargetGroup, groupID, nil, ban)
+	return r.groupSanctionToModel(ctx, ban), nil
+}
+
+// UnbanGroupMember is the resolver for the unbanGroupMember field.
+func (r *mutationResolver) UnbanGroupMember(ctx context.Context, groupID string, userID string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+
+	if _, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageMembers); err != nil {
+		return false, err
+	}
+	ban, err := r.CommunityRepo.ActiveSanction(ctx, groupID, userID, community.SanctionBan)
+	if err != nil {
+		return false, err
+	}
+	if ban == nil {
+		return false, fmt.Errorf("user is not banned from this group")
+	}
+
+	if err := r.CommunityRepo.LiftSanction(ctx, groupID, userID, community.SanctionBan, user.ID); err != nil {
+		return false, fmt.Errorf("failed to lift ban: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionGroupUnban, audit.TargetGroup, groupID, ban, nil)
+	return true, nil
+}
+
+// MuteGroupMember is the resolver for the muteGroupMember field.
+func (r *mutationResolver) MuteGroupMember(ctx context.Context, groupID string, userID string, reason *string, durationHours int32) (*model.GroupSanction, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	isMember, err := r.CommunityRepo.IsMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of the group")
+	}
+
+	mute, err := r.sanctionGroupMember(ctx, user, groupID, userID, community.SanctionMute, reason, &durationHours)
+	if err != nil {
+		return nil, err
+	}
+	r.recordAudit(ctx, audit.ActionGroupMute, audit.TargetGroup, groupID, nil, mute)
+	return r.groupSanctionToModel(ctx, mute), nil
+}
+
+// UnmuteGroupMember is the resolver for the unmuteGroupMember field.
+func (r *mutationResolver) UnmuteGroupMember(ctx context.Context, groupID string, userID string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+
+	if _, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageMembers); err != nil {
+		return false, err
+	}
+	mute, err := r.CommunityRepo.ActiveSanction(ctx, groupID, userID, community.SanctionMute)
+	if err != nil {
+		return false, err
+	}
+	if mute == nil {
+		return false, fmt.Errorf("user is not muted in this group")
+	}
+
+	if err := r.CommunityRepo.LiftSanction(ctx, groupID, userID, community.SanctionMute, user.ID); err != nil {
+		return false, fmt.Errorf("failed to lift mute: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionGroupUnmute, audit.TargetGroup, groupID, mute, nil)
 	return true, nil
 }
 
@@ -705,9 +1062,14 @@ func (r *mutationResolver) UpdatePost(ctx context.Context, postID string, title
 	}
 
 	// Check if user is author or admin
-	if post.AuthorID != user.ID && !user.IsAdmin {
+	if post.AuthorID != user.ID && !auth.IsAdmin(user) {
 		return nil, fmt.Errorf("access denied: only author or admin can edit")
 	}
+	if !auth.IsAdmin(user) && !r.canModerateGroup(ctx, user, post.GroupID) {
+		if err := r.checkCanParticipate(ctx, user, post.GroupID); err != nil {
+			return nil, err
+		}
+	}
 
 	var sanitizedContent *string
 	if content != nil {
@@ -715,10 +1077,24 @@ func (r *mutationResolver) UpdatePost(ctx context.Context, postID string, title
 		sanitizedContent = &c
 	}
 
-	updatedPost, err := r.CommunityRepo.UpdatePost(ctx, postID, title, sanitizedContent)
+	newTitle, newContent := post.Title, post.Content
+	if title != nil {
+		newTitle = *title
+	}
+	if sanitizedContent != nil {
+		newContent = *sanitizedContent
+	}
+	decision, err := r.checkAutomod(ctx, user, automod.KindPost, post.GroupID, newTitle+"\n\n"+newContent)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedPost, err := r.CommunityRepo.UpdatePost(ctx, postID, title, sanitizedContent, decision.Action == automod.ActionHold)
 	if err != nil {
 		return nil, err
 	}
+	r.trackMedia(ctx, media.RefPost, postID, updatedPost.Content)
+	r.finishAutomod(ctx, decision, reports.Target{Type: reports.TargetPost, ID: postID, AuthorID: post.AuthorID, GroupID: post.GroupID})
 
 	author, _ := r.UserRepo.GetByID(ctx, updatedPost.AuthorID)
 	authorPublic := &users.PublicUser{
@@ -731,6 +1107,9 @@ func (r *mutationResolver) UpdatePost(ctx context.Context, postID string, title
 	}
 
 	group, _ := r.CommunityRepo.GetGroupByID(ctx, updatedPost.GroupID)
+	if group != nil && (title != nil || content != nil) {
+		r.updateMentions(ctx, user, postMentionSource(updatedPost, group), updatedPost.Title+"\n\n"+updatedPost.Content)
+	}
 	groupOwner, _ := r.UserRepo.GetByID(ctx, group.OwnerID)
 	groupOwnerPublic := &users.PublicUser{
 		ID:          groupOwner.ID,
@@ -756,19 +1135,140 @@ func (r *mutationResolver) DeletePost(ctx context.Context, postID string) (bool,
 		return false, err
 	}
 
-	// Check if user is author or admin
-	if post.AuthorID != user.ID && !user.IsAdmin {
-		return false, fmt.Errorf("access denied: only author or admin can delete")
+	// Check if user is author, moderator or group owner
+	if post.AuthorID != user.ID && !r.canModerateGroup(ctx, user, post.GroupID) {
+		return false, fmt.Errorf("access denied: only author or moderator can delete")
 	}
 
 	err = r.CommunityRepo.DeletePost(ctx, postID)
 	if err != nil {
 		return false, err
 	}
+	r.deleteMentions(ctx, mentions.SourcePost, postID)
+	r.deletePoll(ctx, postID)
+	r.detachAttachments(ctx, attachments.SourcePost, postID)
+	r.trackMedia(ctx, media.RefPost, postID)
+	if post.AuthorID != user.ID {
+		r.recordAudit(ctx, audit.ActionPostDelete, audit.TargetPost, postID, post, nil)
+		r.notifyPostRemoved(ctx, user, post)
+	}
 
 	return true, nil
 }
 
+// PinPost is the resolver for the pinPost field.
+func (r *mutationResolver) PinPost(ctx context.Context, postID string) (*model.Post, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	post, err := r.moderatedPost(ctx, user, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Pinned {
+		return r.Query().Post(ctx, postID)
+	}
+	if post.Held {
+		return nil, fmt.Errorf("held posts can't be pinned")
+	}
+	pinned, err := r.CommunityRepo.CountPinnedPosts(ctx, post.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if pinned >= maxPinnedPosts {
+		return nil, fmt.Errorf("a group can have at most %d pinned posts", maxPinnedPosts)
+	}
+
+	if err := r.CommunityRepo.PinPost(ctx, postID, true); err != nil {
+		return nil, err
+	}
+	r.recordAudit(ctx, audit.ActionPostPin, audit.TargetPost, postID, nil, nil)
+	return r.Query().Post(ctx, postID)
+}
+
+// UnpinPost is the resolver for the unpinPost field.
+func (r *mutationResolver) UnpinPost(ctx context.Context, postID string) (*model.Post, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	post, err := r.moderatedPost(ctx, user, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.CommunityRepo.PinPost(ctx, postID, false); err != nil {
+		return nil, err
+	}
+	if post.Pinned {
+		r.recordAudit(ctx, audit.ActionPostUnpin, audit.TargetPost, postID, nil, nil)
+	}
+	return r.Query().Post(ctx, postID)
+}
+
+// LockPost is the resolver for the lockPost field.
+func (r *mutationResolver) LockPost(ctx context.Context, postID string) (*model.Post, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	post, err := r.moderatedPost(ctx, user, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.CommunityRepo.LockPost(ctx, postID, true); err != nil {
+		return nil, err
+	}
+	if !post.Locked {
+		r.recordAudit(ctx, audit.ActionPostLock, audit.TargetPost, postID, nil, nil)
+	}
+	return r.Query().Post(ctx, postID)
+}
+
+// UnlockPost is the resolver for the unlockPost field.
+func (r *mutationResolver) UnlockPost(ctx context.Context, postID string) (*model.Post, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	post, err := r.moderatedPost(ctx, user, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.CommunityRepo.LockPost(ctx, postID, false); err != nil {
+		return nil, err
+	}
+	if post.Locked {
+		r.recordAudit(ctx, audit.ActionPostUnlock, audit.TargetPost, postID, nil, nil)
+	}
+	return r.Query().Post(ctx, postID)
+}
+
+// SetPostAnnouncement is the resolver for the setPostAnnouncement field.
+func (r *mutationResolver) SetPostAnnouncement(ctx context.Context, postID string, announcement bool) (*model.Post, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	post, err := r.moderatedPost(ctx, user, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.CommunityRepo.SetPostAnnouncement(ctx, postID, announcement); err != nil {
+		return nil, err
+	}
+	if post.Announcement != announcement {
+		r.recordAudit(ctx, audit.ActionPostAnnouncement, audit.TargetPost, postID,
+			map[string]bool{"announcement": post.Announcement}, map[string]bool{"announcement": announcement})
+	}
+	return r.Query().Post(ctx, postID)
+}
+
 // UpdateComment is the resolver for the updateComment field.
 func (r *mutationResolver) UpdateComment(ctx context.Context, commentID string, content string) (*model.Comment, error) {
 	user := auth.ForContext(ctx)
@@ -782,16 +1282,32 @@ func (r *mutationResolver) UpdateComment(ctx context.Context, commentID string,
 	}
 
 	// Check if user is author or admin
-	if comment.AuthorID != user.ID && !user.IsAdmin {
+	if comment.AuthorID != user.ID && !auth.IsAdmin(user) {
 		return nil, fmt.Errorf("access denied: only author or admin can edit")
 	}
 
 	sanitizedContent := sanitization.SanitizeContent(content)
-	updatedComment, err := r.CommunityRepo.UpdateComment(ctx, commentID, sanitizedContent)
+	post, err := r.CommunityRepo.GetPost(ctx, comment.PostID)
+	if err != nil {
+		return nil, fmt.Errorf("post not found")
+	}
+	if !auth.IsAdmin(user) && !r.canModerateGroup(ctx, user, post.GroupID) {
+		if err := r.checkCanParticipate(ctx, user, post.GroupID); err != nil {
+			return nil, err
+		}
+	}
+	decision, err := r.checkAutomod(ctx, user, automod.KindComment, post.GroupID, sanitizedContent)
 	if err != nil {
 		return nil, err
 	}
 
+	updatedComment, err := r.CommunityRepo.UpdateComment(ctx, commentID, sanitizedContent, decision.Action == automod.ActionHold)
+	if err != nil {
+		return nil, err
+	}
+	r.trackMedia(ctx, media.RefComment, commentID, updatedComment.Content)
+	r.finishAutomod(ctx, decision, reports.Target{Type: reports.TargetComment, ID: commentID, AuthorID: comment.AuthorID, GroupID: post.GroupID})
+
 	author, _ := r.UserRepo.GetByID(ctx, updatedComment.AuthorID)
 	authorPublic := &users.PublicUser{
 		ID:          author.ID,
@@ -802,7 +1318,6 @@ func (r *mutationResolver) UpdateComment(ctx context.Context, commentID string,
 		Avatar:      author.Avatar,
 	}
 
-	post, _ := r.CommunityRepo.GetPost(ctx, updatedComment.PostID)
 	postAuthor, _ := r.UserRepo.GetByID(ctx, post.AuthorID)
 	postAuthorPublic := &users.PublicUser{
 		ID:          postAuthor.ID,
@@ -814,6 +1329,9 @@ func (r *mutationResolver) UpdateComment(ctx context.Context, commentID string,
 	}
 
 	group, _ := r.CommunityRepo.GetGroupByID(ctx, post.GroupID)
+	if group != nil {
+		r.updateMentions(ctx, user, commentMentionSource(updatedComment, post, group), updatedComment.Content)
+	}
 	groupOwner, _ := r.UserRepo.GetByID(ctx, group.OwnerID)
 	groupOwnerPublic := &users.PublicUser{
 		ID:          groupOwner.ID,
@@ -839,15 +1357,23 @@ func (r *mutationResolver) DeleteComment(ctx context.Context, commentID string)
 		return false, err
 	}
 
-	// Check if user is author or admin
-	if comment.AuthorID != user.ID && !user.IsAdmin {
-		return false, fmt.Errorf("access denied: only author or admin can delete")
+	// Check if user is author, moderator or group owner
+	if comment.AuthorID != user.ID {
+		post, err := r.CommunityRepo.GetPost(ctx, comment.PostID)
+		if err != nil || !r.canModerateGroup(ctx, user, post.GroupID) {
+			return false, fmt.Errorf("access denied: only author or moderator can delete")
+		}
 	}
 
 	err = r.CommunityRepo.DeleteComment(ctx, commentID)
 	if err != nil {
 		return false, err
 	}
+	r.deleteMentions(ctx, mentions.SourceComment, commentID)
+	r.trackMedia(ctx, media.RefComment, commentID)
+	if comment.AuthorID != user.ID {
+		r.recordAudit(ctx, audit.ActionCommentDelete, audit.TargetComment, commentID, comment, nil)
+	}
 
 	return true, nil
 }
@@ -873,7 +1399,7 @@ func (r *postResolver) UserVote(ctx context.Context, obj *model.Post) (model.Vot
 }
 
 // Comments is the resolver for the comments field.
-func (r *postResolver) Comments(ctx context.Context, obj *model.Post, limit *int32, offset *int32) ([]*model.Comment, error) {
+func (r *postResolver) Comments(ctx context.Context, obj *model.Post, limit *int32, offset *int32, sort *model.ContentSort, window *model.TimeWindow) ([]*model.Comment, error) {
 	l := 10
 	o := 0
 	if limit != nil {
@@ -883,7 +1409,7 @@ func (r *postResolver) Comments(ctx context.Context, obj *model.Post, limit *int
 		o = int(*offset)
 	}
 
-	comments, err := r.CommunityRepo.ListComments(ctx, obj.ID, nil, l, o)
+	comments, err := r.CommunityRepo.ListComments(ctx, obj.ID, nil, sortFromModel(sort, window), l, o)
 	if err != nil {
 		return nil, err
 	}
@@ -1081,12 +1607,54 @@ func (r *queryResolver) GroupByInviteToken(ctx context.Context, token string) (*
 	return mapGroupToModel(g, mapUserToPublic(owner)), nil
 }
 
+// GroupSanctions is the resolver for the groupSanctions field.
+func (r *queryResolver) GroupSanctions(ctx context.Context, groupID string, typeArg *model.GroupSanctionType, limit *int32, offset *int32) ([]*model.GroupSanction, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	if _, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageMembers); err != nil {
+		return nil, err
+	}
+
+	l := 20
+	o := 0
+	if limit != nil {
+		l = int(*limit)
+	}
+	if l <= 0 || l > maxSanctionPageSize {
+		l = maxSanctionPageSize
+	}
+	if offset != nil {
+		o = int(*offset)
+	}
+	var sanctionType *community.SanctionType
+	if typeArg != nil {
+		t := community.SanctionType(*typeArg)
+		sanctionType = &t
+	}
+
+	sanctions, err := r.CommunityRepo.ListSanctions(ctx, groupID, sanctionType, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sanctions: %w", err)
+	}
+	result := make([]*model.GroupSanction, 0, len(sanctions))
+	for _, s := range sanctions {
+		result = append(result, r.groupSanctionToModel(ctx, s))
+	}
+	return result, nil
+}
+
 // Post is the resolver for the post field.
 func (r *queryResolver) Post(ctx context.Context, id string) (*model.Post, error) {
 	p, err := r.CommunityRepo.GetPost(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if p.Held && !r.canViewHeld(ctx, p.AuthorID, p.GroupID) {
+		return nil, fmt.Errorf("post not found")
+	}
 	author, _ := r.UserRepo.GetByID(ctx, p.AuthorID)
 	authorPublic := &users.PublicUser{
 		ID:          author.ID,
@@ -1141,7 +1709,13 @@ func (r *queryResolver) Comment(ctx context.Context, id string) (*model.Comment,
 		Avatar:      author.Avatar,
 	}
 
-	post, _ := r.CommunityRepo.GetPost(ctx, c.PostID)
+	post, err := r.CommunityRepo.GetPost(ctx, c.PostID)
+	if err != nil {
+		return nil, fmt.Errorf("post not found")
+	}
+	if c.Held && !r.canViewHeld(ctx, c.AuthorID, post.GroupID) {
+		return nil, fmt.Errorf("comment not found")
+	}
 	postAuthor, _ := r.UserRepo.GetByID(ctx, post.AuthorID)
 	postAuthorPublic := &users.PublicUser{
 		ID:          postAuthor.ID,
@@ -1182,7 +1756,7 @@ func (r *queryResolver) Comment(ctx context.Context, id string) (*model.Comment,
 }
 
 // PublicPosts is the resolver for the publicPosts field.
-func (r *queryResolver) PublicPosts(ctx context.Context, limit *int32, offset *int32) ([]*model.Post, error) {
+func (r *queryResolver) PublicPosts(ctx context.Context, limit *int32, offset *int32, sort *model.ContentSort, window *model.TimeWindow) ([]*model.Post, error) {
 	l := 10
 	o := 0
 	if limit != nil {
@@ -1192,7 +1766,7 @@ func (r *queryResolver) PublicPosts(ctx context.Context, limit *int32, offset *i
 		o = int(*offset)
 	}
 
-	posts, err := r.CommunityRepo.ListPublicPosts(ctx, l, o)
+	posts, err := r.CommunityRepo.ListPublicPosts(ctx, sortFromModel(sort, window), l, o)
 	if err != nil {
 		return nil, err
 	}
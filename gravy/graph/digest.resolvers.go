@@ -0,0 +1,45 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/digest"
+)
+
+// SetDigestFrequency is the resolver for the setDigestFrequency field.
+func (r *mutationResolver) SetDigestFrequency(ctx context.Context, frequency model.DigestFrequency) (model.DigestFrequency, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return "", fmt.Errorf("not authenticated")
+	}
+	f := digest.Frequency(frequency)
+	if !f.Valid() {
+		return "", fmt.Errorf("invalid digest frequency %s", frequency)
+	}
+	settings, err := r.DigestRepo.SetFrequency(ctx, user.ID, f)
+	if err != nil {
+		return "", err
+	}
+	return model.DigestFrequency(settings.Frequency), nil
+}
+
+// DigestFrequency is the resolver for the digestFrequency field.
+func (r *queryResolver) DigestFrequency(ctx context.Context) (model.DigestFrequency, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return "", fmt.Errorf("not authenticated")
+	}
+	settings, err := r.DigestRepo.Get(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	return model.DigestFrequency(settings.Frequency), nil
+}
@@ -11,8 +11,13 @@ import (
 	"time"
 
 	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/attachments"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/automod"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/reports"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/sanitization"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
 )
@@ -78,13 +83,9 @@ func (r *mutationResolver) CreateChannel(ctx context.Context, input model.NewCha
 		return nil, fmt.Errorf("discussion not found")
 	}
 
-	group, err := r.CommunityRepo.GetGroupByID(ctx, discussion.GroupID)
-	if err != nil {
+	if _, err := r.authorizeGroup(ctx, user, discussion.GroupID, community.GroupManageChannels); err != nil {
 		return nil, err
 	}
-	if group.OwnerID != user.ID {
-		return nil, fmt.Errorf("access denied: only group owner can create channels")
-	}
 
 	channel := &community.Channel{
 		DiscussionID: input.DiscussionID,
@@ -135,6 +136,14 @@ func (r *mutationResolver) SendMessage(ctx context.Context, input model.NewMessa
 	if !isMember {
 		return nil, fmt.Errorf("access denied: only group members can send messages")
 	}
+	if err := r.checkCanParticipate(ctx, user, discussion.GroupID); err != nil {
+		return nil, err
+	}
+
+	attachmentIDs, err := r.checkAttachments(ctx, user, input.AttachmentIds)
+	if err != nil {
+		return nil, err
+	}
 
 	message := &community.Message{
 		ChannelID: input.ChannelID,
@@ -143,10 +152,22 @@ func (r *mutationResolver) SendMessage(ctx context.Context, input model.NewMessa
 		CreatedAt: time.Now(),
 	}
 
+	decision, err := r.checkAutomod(ctx, user, automod.KindMessage, discussion.GroupID, message.Content)
+	if err != nil {
+		return nil, err
+	}
+	message.Held = decision.Action == automod.ActionHold
+
 	err = r.CommunityRepo.CreateMessage(ctx, message)
 	if err != nil {
 		return nil, err
 	}
+	r.attach(ctx, user, attachmentIDs, attachments.SourceMessage, message.ID, discussion.GroupID)
+	r.trackMedia(ctx, media.RefMessage, message.ID, message.Content)
+	r.finishAutomod(ctx, decision, reports.Target{Type: reports.TargetMessage, ID: message.ID, AuthorID: user.ID, GroupID: discussion.GroupID})
+	if group, err := r.CommunityRepo.GetGroupByID(ctx, discussion.GroupID); err == nil {
+		r.updateMentions(ctx, user, messageMentionSource(message, channel, group), message.Content)
+	}
 
 	sender := &users.PublicUser{
 		ID:          user.ID,
@@ -161,6 +182,7 @@ func (r *mutationResolver) SendMessage(ctx context.Context, input model.NewMessa
 		ID:        message.ID,
 		Content:   message.Content,
 		Sender:    mapPublicUserToModel(sender),
+		Held:      message.Held,
 		CreatedAt: message.CreatedAt.Format("2006-01-02 15:04:05"),
 	}, nil
 }
@@ -172,18 +194,18 @@ func (r *mutationResolver) DeleteGroup(ctx context.Context, groupID string) (boo
 		return false, fmt.Errorf("not authenticated")
 	}
 
-	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	group, err := r.authorizeGroup(ctx, user, groupID, community.GroupDelete)
 	if err != nil {
 		return false, err
 	}
-	if group.OwnerID != user.ID {
-		return false, fmt.Errorf("access denied: only group owner can delete group")
-	}
 
 	err = r.CommunityRepo.DeleteGroup(ctx, groupID)
 	if err != nil {
 		return false, err
 	}
+	r.recordAudit(ctx, audit.ActionGroupDelete, audit.TargetGroup, groupID, groupSnapshot(group), nil)
+	r.detachGroupAttachments(ctx, groupID)
+	r.trackMedia(ctx, media.RefGroup, groupID)
 
 	return true, nil
 }
@@ -233,9 +255,13 @@ func (r *Resolver) Channel() ChannelResolver { return &channelResolver{r} }
 // Discussion returns DiscussionResolver implementation.
 func (r *Resolver) Discussion() DiscussionResolver { return &discussionResolver{r} }
 
+// Message returns MessageResolver implementation.
+func (r *Resolver) Message() MessageResolver { return &messageResolver{r} }
+
 // Subscription returns SubscriptionResolver implementation.
 func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
 
 type channelResolver struct{ *Resolver }
 type discussionResolver struct{ *Resolver }
+type messageResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
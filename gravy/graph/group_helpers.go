@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+const maxSanctionPageSize = 100
+
+// authorizeGroup loads a group and checks that the user's role in it grants
+// perm.
+func (r *Resolver) authorizeGroup(ctx context.Context, user *users.User, groupID string, perm community.GroupPermission) (*community.Group, error) {
+	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("group not found")
+	}
+	if !group.Can(user.ID, perm) {
+		return nil, fmt.Errorf("access denied")
+	}
+	return group, nil
+}
+
+// managedGroupIDs lists the groups a user owns or moderates.
+func (r *Resolver) managedGroupIDs(ctx context.Context, user *users.User) ([]string, error) {
+	managerID := user.ID
+	groups, err := r.CommunityRepo.ListGroups(ctx, community.GroupFilter{ManagerID: &managerID}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(groups))
+	for _, g := range groups {
+		ids = append(ids, g.ID)
+	}
+	return ids, nil
+}
+
+const (
+	maxGroupMuteHours         = 30 * 24
+	maxGroupSanctionReasonLen = 500
+	maxPinnedPosts            = 3
+)
+
+// moderatedPost loads a post whose group the user moderates.
+func (r *Resolver) moderatedPost(ctx context.Context, user *users.User, postID string) (*community.Post, error) {
+	post, err := r.CommunityRepo.GetPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("post not found")
+	}
+	if !r.canModerateGroup(ctx, user, post.GroupID) {
+		return nil, fmt.Errorf("access denied: only group owners and moderators can do this")
+	}
+	return post, nil
+}
+
+// checkGroupBan rejects users banned from the group.
+func (r *Resolver) checkGroupBan(ctx context.Context, userID, groupID string) error {
+	ban, err := r.CommunityRepo.ActiveSanction(ctx, groupID, userID, community.SanctionBan)
+	if err != nil {
+		return fmt.Errorf("failed to check group ban: %w", err)
+	}
+	if ban != nil {
+		return sanctionError("banned from", ban)
+	}
+	return nil
+}
+
+// checkCanParticipate rejects posts, comments and messages from users who are
+// banned from or muted in the group.
+func (r *Resolver) checkCanParticipate(ctx context.Context, user *users.User, groupID string) error {
+	if err := r.checkGroupBan(ctx, user.ID, groupID); err != nil {
+		return err
+	}
+	mute, err := r.CommunityRepo.ActiveSanction(ctx, groupID, user.ID, community.SanctionMute)
+	if err != nil {
+		return fmt.Errorf("failed to check group mute: %w", err)
+	}
+	if mute != nil {
+		return sanctionError("muted in", mute)
+	}
+	return nil
+}
+
+func sanctionError(verb string, s *community.GroupSanction) error {
+	msg := "you are " + verb + " this group"
+	if s.ExpiresAt != nil {
+		msg += " until " + s.ExpiresAt.Format("2006-01-02 15:04:05")
+	}
+	if s.Reason != "" {
+		msg += ": " + s.Reason
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// sanctionGroupMember bans or mutes a member on behalf of a group owner or
+// moderator.
+func (r *Resolver) sanctionGroupMember(ctx context.Context, moderator *users.User, groupID, userID string, sanctionType community.SanctionType, reason *string, durationHours *int32) (*community.GroupSanction, error) {
+	group, err := r.authorizeGroup(ctx, moderator, groupID, community.GroupManageMembers)
+	if err != nil {
+		return nil, err
+	}
+	if userID == moderator.ID {
+		return nil, fmt.Errorf("you can't sanction yourself")
+	}
+	if !group.Outranks(moderator.ID, userID) {
+		return nil, fmt.Errorf("access denied: only the owner can sanction moderators")
+	}
+	target, err := r.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	sanction := &community.GroupSanction{
+		GroupID:     groupID,
+		UserID:      target.ID,
+		Type:        sanctionType,
+		ModeratorID: moderator.ID,
+	}
+	if reason != nil {
+		sanction.Reason = strings.TrimSpace(*reason)
+		if len(sanction.Reason) > maxGroupSanctionReasonLen {
+			return nil, fmt.Errorf("reason must be at most %d characters", maxGroupSanctionReasonLen)
+		}
+	}
+	if durationHours != nil {
+		if *durationHours <= 0 {
+			return nil, fmt.Errorf("duration must be positive")
+		}
+		if sanctionType == community.SanctionMute && *durationHours > maxGroupMuteHours {
+			return nil, fmt.Errorf("mutes can last at most %d hours", maxGroupMuteHours)
+		}
+		expiresAt := time.Now().Add(time.Duration(*durationHours) * time.Hour)
+		sanction.ExpiresAt = &expiresAt
+	}
+
+	if err := r.CommunityRepo.AddSanction(ctx, sanction); err != nil {
+		return nil, fmt.Errorf("failed to record sanction: %w", err)
+	}
+	return sanction, nil
+}
+
+func (r *Resolver) groupSanctionToModel(ctx context.Context, s *community.GroupSanction) *model.GroupSanction {
+	var user *users.PublicUser
+	if u, err := r.UserRepo.GetByID(ctx, s.UserID); err == nil {
+		user = mapUserToPublic(u)
+	}
+	return mapGroupSanctionToModel(s, user)
+}
+
+func sortFromModel(sort *model.ContentSort, window *model.TimeWindow) community.Sort {
+	s := community.SortByNew
+	if sort != nil {
+		s.Order = community.SortOrder(*sort)
+	}
+	if window != nil {
+		s.Window = community.TimeWindow(*window)
+	}
+	return s
+}
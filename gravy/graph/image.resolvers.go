@@ -0,0 +1,40 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+)
+
+// UploadProcessedImage is the resolver for the uploadProcessedImage field.
+func (r *mutationResolver) UploadProcessedImage(ctx context.Context, file graphql.Upload, purpose model.ImagePurpose) (*model.Image, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	p := imaging.Purpose(purpose)
+	if !p.Valid() {
+		return nil, fmt.Errorf("invalid image purpose %s", purpose)
+	}
+	if p == imaging.PurposeArticle {
+		if err := auth.RequirePermission(ctx, user, roles.ArticlesWrite); err != nil {
+			return nil, err
+		}
+	}
+
+	m, err := r.uploadMedia(ctx, user, file, p)
+	if err != nil {
+		return nil, err
+	}
+	return mapImageToModel(m.Variants), nil
+}
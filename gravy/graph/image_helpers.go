@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+var imageFolders = map[imaging.Purpose]string{
+	imaging.PurposeAvatar:  "wikinitt/avatars",
+	imaging.PurposeArticle: "wikinitt/articles",
+	imaging.PurposePost:    "wikinitt/user-uploads",
+}
+
+func checkImageSize(file graphql.Upload, purpose imaging.Purpose) error {
+	if file.Size > purpose.MaxBytes() {
+		return fmt.Errorf("image is too large; the limit is %d MB", purpose.MaxBytes()>>20)
+	}
+	return nil
+}
+
+// storeImage validates and re-encodes an image and stores all of its
+// variants, smallest first.
+func (r *Resolver) storeImage(ctx context.Context, file io.Reader, purpose imaging.Purpose) (*imaging.Result, []imaging.Stored, error) {
+	res, err := imaging.Process(file, purpose)
+	if err != nil {
+		return nil, nil, err
+	}
+	stored, err := imaging.Store(ctx, r.Uploader, imageFolders[purpose], res)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, stored, nil
+}
+
+// uploadMedia stores an image in the media library and counts it against
+// the user's upload quota. A file that was already uploaded for the same
+// purpose isn't stored again; the existing media is returned instead.
+func (r *Resolver) uploadMedia(ctx context.Context, user *users.User, file graphql.Upload, purpose imaging.Purpose) (*media.Media, error) {
+	if err := checkImageSize(file, purpose); err != nil {
+		return nil, err
+	}
+	if err := r.reserveUpload(ctx, user, file.Size); err != nil {
+		return nil, err
+	}
+	m, stored, err := r.saveMedia(ctx, user, file, purpose)
+	r.settleUpload(ctx, user, file.Size, stored, err)
+	return m, err
+}
+
+// saveMedia returns the media for an upload and how many bytes it added to
+// storage.
+func (r *Resolver) saveMedia(ctx context.Context, user *users.User, file graphql.Upload, purpose imaging.Purpose) (*media.Media, int64, error) {
+	data, err := io.ReadAll(io.LimitReader(file.File, purpose.MaxBytes()+1))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read image: %w", err)
+	}
+	hash := media.Hash(data)
+
+	existing, err := r.MediaRepo.GetByHash(ctx, hash, purpose)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to look up media: %w", err)
+	}
+	if existing != nil {
+		touched, err := r.MediaRepo.Touch(ctx, existing.ID, time.Now())
+		if err != nil {
+			log.Printf("Failed to touch media %s: %v", existing.ID, err)
+		}
+		// Media the collector claimed in the meantime is about to lose its
+		// files, so the upload is stored again.
+		if err != nil || touched {
+			return existing, 0, nil
+		}
+	}
+
+	res, stored, err := r.storeImage(ctx, bytes.NewReader(data), purpose)
+	if err != nil {
+		return nil, 0, err
+	}
+	m := media.New(user.ID, purpose, imageFolders[purpose], file.Filename, hash, res.ContentType, int64(len(res.Full().Data)), stored)
+	m.StoredSize = res.TotalSize()
+	err = r.MediaRepo.Create(ctx, m)
+	if errors.Is(err, media.ErrDuplicate) {
+		// An identical upload finished first.
+		imaging.Discard(ctx, r.Uploader, stored)
+		existing, err := r.MediaRepo.GetByHash(ctx, hash, purpose)
+		return existing, 0, err
+	}
+	if err != nil {
+		imaging.Discard(ctx, r.Uploader, stored)
+		return nil, 0, fmt.Errorf("failed to save media: %w", err)
+	}
+	return m, m.StoredSize, nil
+}
+
+func mapImageVariantsToModel(stored []imaging.Stored) []*model.ImageVariant {
+	variants := make([]*model.ImageVariant, 0, len(stored))
+	for _, s := range stored {
+		variants = append(variants, &model.ImageVariant{
+			Name:   s.Name,
+			URL:    s.URL,
+			Width:  int32(s.Width),
+			Height: int32(s.Height),
+		})
+	}
+	return variants
+}
+
+func mapImageToModel(stored []imaging.Stored) *model.Image {
+	full := stored[len(stored)-1]
+	return &model.Image{
+		URL:      full.URL,
+		Width:    int32(full.Width),
+		Height:   int32(full.Height),
+		Variants: mapImageVariantsToModel(stored),
+	}
+}
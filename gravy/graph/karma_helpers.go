@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/karma"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+// checkKarmaRequirement rejects members below the group's karma threshold
+// for an action. Owners and moderators are exempt.
+func (r *Resolver) checkKarmaRequirement(ctx context.Context, user *users.User, group *community.Group, minKarma int, action string) error {
+	if minKarma <= 0 || group.Can(user.ID, community.GroupModerateContent) {
+		return nil
+	}
+	total, err := r.KarmaRepo.Total(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check karma: %w", err)
+	}
+	if total < minKarma {
+		return fmt.Errorf("you need at least %d karma to %s in this group", minKarma, action)
+	}
+	return nil
+}
+
+// awardArticleKarma credits an article's author. Failures are only logged
+// since the rebuild command can recover them.
+func (r *Resolver) awardArticleKarma(ctx context.Context, authorID string, delta int) {
+	if authorID == "" {
+		return
+	}
+	if err := r.KarmaRepo.Add(ctx, authorID, "", karma.SourceArticle, delta); err != nil {
+		log.Printf("Failed to update article karma of %s: %v", authorID, err)
+	}
+}
+
+// groupKarma lists a user's karma per group, skipping private groups the
+// viewer isn't a member of.
+func (r *Resolver) groupKarma(ctx context.Context, userID string) ([]*model.GroupKarma, error) {
+	entries, err := r.KarmaRepo.Entries(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	viewerID := ""
+	if viewer := auth.ForContext(ctx); viewer != nil {
+		viewerID = viewer.ID
+	}
+
+	res := []*model.GroupKarma{}
+	for _, e := range entries {
+		if e.GroupID == "" {
+			continue
+		}
+		group, err := r.CommunityRepo.GetGroupByID(ctx, e.GroupID)
+		if err != nil {
+			continue
+		}
+		if group.Type == community.GroupTypePrivate && group.RoleOf(viewerID) == "" {
+			continue
+		}
+		owner, _ := r.UserRepo.GetByID(ctx, group.OwnerID)
+		res = append(res, &model.GroupKarma{
+			Group:        mapGroupToModel(group, mapUserToPublic(owner)),
+			PostKarma:    int32(e.Posts),
+			CommentKarma: int32(e.Comments),
+			Karma:        int32(e.Total()),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"os"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mailer"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+func frontendURL() string {
+	u := os.Getenv("FRONTEND_URL")
+	if u == "" {
+		u = "https://wikinitt.netlify.app"
+	}
+	return u
+}
+
+func accountLink(path, token string) string {
+	return fmt.Sprintf("%s%s?token=%s", frontendURL(), path, url.QueryEscape(token))
+}
+
+func accountEmail(to, subject, intro, linkText, link string) mailer.Message {
+	text := fmt.Sprintf("%s\n\n%s: %s\n\nIf you didn't request this, you can ignore this email.\n", intro, linkText, link)
+	body := fmt.Sprintf(
+		`<p>%s</p><p><a href="%s">%s</a></p><p>If you didn't request this, you can ignore this email.</p>`,
+		html.EscapeString(intro), html.EscapeString(link), html.EscapeString(linkText),
+	)
+	return mailer.Message{To: to, Subject: subject, Text: text, HTML: body}
+}
+
+func (r *Resolver) sendPasswordResetEmail(ctx context.Context, user *users.User, token string) {
+	msg := accountEmail(
+		user.Email,
+		"Reset your WikiNITT password",
+		fmt.Sprintf("Hi %s, we received a request to reset your WikiNITT password. The link expires in %d minutes.", user.DisplayName, int(passwordResetTTL.Minutes())),
+		"Reset password",
+		accountLink("/reset-password", token),
+	)
+	if err := r.Mailer.Send(ctx, msg); err != nil {
+		log.Printf("Failed to send password reset email to user %s: %v", user.ID, err)
+	}
+}
+
+func (r *Resolver) sendVerificationEmail(ctx context.Context, user *users.User, token string) error {
+	msg := accountEmail(
+		user.Email,
+		"Verify your WikiNITT email",
+		fmt.Sprintf("Hi %s, please confirm this email address for your WikiNITT account.", user.DisplayName),
+		"Verify email",
+		accountLink("/verify-email", token),
+	)
+	return r.Mailer.Send(ctx, msg)
+}
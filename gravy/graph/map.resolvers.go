@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/maplocation"
 )
 
@@ -42,6 +43,7 @@ func (r *mutationResolver) AddMapLocation(ctx context.Context, input model.MapLo
 	if err := r.MapLocationRepo.Create(ctx, loc); err != nil {
 		return nil, err
 	}
+	r.recordAudit(ctx, audit.ActionMapLocationCreate, audit.TargetMapLocation, loc.ID.Hex(), nil, loc)
 
 	// Convert back to model
 	var modelMenu []*model.MenuItem
@@ -66,7 +68,15 @@ func (r *mutationResolver) AddMapLocation(ctx context.Context, input model.MapLo
 
 // DeleteMapLocation is the resolver for the deleteMapLocation field.
 func (r *mutationResolver) DeleteMapLocation(ctx context.Context, id string) (bool, error) {
-	err := r.MapLocationRepo.Delete(ctx, id)
+	existing, err := r.MapLocationRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.MapLocationRepo.Delete(ctx, id)
+	if err == nil {
+		r.recordAudit(ctx, audit.ActionMapLocationDelete, audit.TargetMapLocation, id, existing, nil)
+	}
 	return err == nil, err
 }
 
@@ -1,9 +1,20 @@
 package graph
 
 import (
+	"time"
+
 	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/apitokens"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/articles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/attachments"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/automod"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/bans"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/notifications"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/reports"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
 )
 
@@ -51,35 +62,181 @@ func mapUserToModel(u *users.User) *model.User {
 		return nil
 	}
 	return &model.User{
-		ID:            u.ID,
-		Name:          u.Name,
-		Username:      u.Username,
-		DisplayName:   u.DisplayName,
-		Email:         u.Email,
-		Gender:        u.Gender,
-		Avatar:        u.Avatar,
-		PhoneNumber:   u.PhoneNumber,
-		SetupComplete: u.SetupComplete,
-		IsAdmin:       u.IsAdmin,
-		IsBanned:      u.IsBanned,
-		CreatedAt:     u.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:               u.ID,
+		Name:             u.Name,
+		Username:         u.Username,
+		DisplayName:      u.DisplayName,
+		Email:            u.Email,
+		Gender:           u.Gender,
+		Avatar:           u.Avatar,
+		PhoneNumber:      u.PhoneNumber,
+		SetupComplete:    u.SetupComplete,
+		EmailVerified:    u.EmailVerified,
+		TwoFactorEnabled: u.TwoFactorEnabled,
+		IsAdmin:          auth.IsAdmin(u),
+		Roles:            mapRolesToModel(auth.UserRoles(u)),
+		IsBanned:         u.IsBanned,
+		CreatedAt:        u.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
+func mapRolesToModel(rs []roles.Role) []model.Role {
+	result := make([]model.Role, 0, len(rs))
+	for _, r := range rs {
+		result = append(result, model.Role(r))
+	}
+	return result
+}
+
+func mapAPITokenToModel(t *apitokens.Token) *model.APIToken {
+	if t == nil {
+		return nil
+	}
+	scopes := make([]model.TokenScope, 0, len(t.Scopes))
+	for _, sc := range t.Scopes {
+		scopes = append(scopes, model.TokenScope(sc))
+	}
+	res := &model.APIToken{
+		ID:        t.ID,
+		Name:      t.Name,
+		Hint:      t.Hint,
+		Scopes:    scopes,
+		CreatedAt: t.CreatedAt.Format("2006-01-02 15:04:05"),
+		Revoked:   t.RevokedAt != nil,
+	}
+	if t.ExpiresAt != nil {
+		expiresAt := t.ExpiresAt.Format("2006-01-02 15:04:05")
+		res.ExpiresAt = &expiresAt
+	}
+	if t.LastUsedAt != nil {
+		lastUsedAt := t.LastUsedAt.Format("2006-01-02 15:04:05")
+		res.LastUsedAt = &lastUsedAt
+	}
+	return res
+}
+
+func mapBanToModel(b *bans.Ban) *model.Ban {
+	if b == nil {
+		return nil
+	}
+	res := &model.Ban{
+		ID:          b.ID,
+		UserID:      b.UserID,
+		Reason:      b.Reason,
+		ModeratorID: b.ModeratorID,
+		StartsAt:    b.StartsAt.Format("2006-01-02 15:04:05"),
+		Active:      b.Active(time.Now()),
+	}
+	if b.EndsAt != nil {
+		endsAt := b.EndsAt.Format("2006-01-02 15:04:05")
+		res.EndsAt = &endsAt
+	}
+	if b.LiftedAt != nil {
+		liftedAt := b.LiftedAt.Format("2006-01-02 15:04:05")
+		res.LiftedAt = &liftedAt
+	}
+	return res
+}
+
+func mapBanAppealToModel(a *bans.Appeal, ban *bans.Ban, user *users.PublicUser) *model.BanAppeal {
+	if a == nil {
+		return nil
+	}
+	res := &model.BanAppeal{
+		ID:        a.ID,
+		Ban:       mapBanToModel(ban),
+		User:      mapPublicUserToModel(user),
+		Message:   a.Message,
+		Status:    model.AppealStatus(a.Status),
+		CreatedAt: a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if a.Response != "" {
+		response := a.Response
+		res.Response = &response
+	}
+	if a.ReviewedAt != nil {
+		reviewedAt := a.ReviewedAt.Format("2006-01-02 15:04:05")
+		res.ReviewedAt = &reviewedAt
+	}
+	return res
+}
+
+func mapAuditEntryToModel(e *audit.Entry) *model.AuditEntry {
+	optional := func(v string) *string {
+		if v == "" {
+			return nil
+		}
+		return &v
+	}
+	return &model.AuditEntry{
+		ID:         e.ID,
+		ActorID:    e.ActorID,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		Before:     optional(e.Before),
+		After:      optional(e.After),
+		IP:         optional(e.IP),
+		UserAgent:  optional(e.UserAgent),
+		APITokenID: optional(e.APITokenID),
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapReportCaseToModel(c *reports.Case, author *users.PublicUser, content *string) *model.ReportCase {
+	optional := func(v string) *string {
+		if v == "" {
+			return nil
+		}
+		return &v
+	}
+	res := &model.ReportCase{
+		ID:           c.ID,
+		TargetType:   model.ReportTargetType(c.TargetType),
+		TargetID:     c.TargetID,
+		TargetAuthor: mapPublicUserToModel(author),
+		GroupID:      c.GroupID,
+		Content:      content,
+		Status:       model.ReportStatus(c.Status),
+		ReportCount:  int32(c.ReportCount),
+		Reports:      make([]*model.ReportEntry, 0, len(c.Reports)),
+		CreatedAt:    c.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for _, e := range c.Reports {
+		res.Reports = append(res.Reports, &model.ReportEntry{
+			ReporterID: e.ReporterID,
+			Reason:     model.ReportReason(e.Reason),
+			Note:       optional(e.Note),
+			CreatedAt:  e.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	if c.Resolution != nil {
+		res.Resolution = &model.ReportResolution{
+			Action:      model.ModerationAction(c.Resolution.Action),
+			ModeratorID: c.Resolution.ModeratorID,
+			Note:        optional(c.Resolution.Note),
+			ResolvedAt:  c.Resolution.ResolvedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return res
+}
+
 func mapGroupToModel(g *community.Group, owner *users.PublicUser) *model.Group {
 	if g == nil {
 		return nil
 	}
 	return &model.Group{
-		ID:           g.ID,
-		Name:         g.Name,
-		Description:  g.Description,
-		Icon:         &g.Icon,
-		Slug:         g.Slug,
-		Type:         model.GroupType(g.Type),
-		Owner:        mapPublicUserToModel(owner),
-		MembersCount: int32(g.MembersCount),
-		CreatedAt:    g.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:              g.ID,
+		Name:            g.Name,
+		Description:     g.Description,
+		Icon:            &g.Icon,
+		Slug:            g.Slug,
+		Type:            model.GroupType(g.Type),
+		Owner:           mapPublicUserToModel(owner),
+		MembersCount:    int32(g.MembersCount),
+		MinPostKarma:    int32(g.MinPostKarma),
+		MinCommentKarma: int32(g.MinCommentKarma),
+		CreatedAt:       g.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
@@ -88,16 +245,20 @@ func mapPostToModel(p *community.Post, author *users.PublicUser, group *communit
 		return nil
 	}
 	return &model.Post{
-		ID:            p.ID,
-		Title:         p.Title,
-		Content:       p.Content,
-		Author:        mapPublicUserToModel(author),
-		Group:         mapGroupToModel(group, groupOwner),
-		CommentsCount: int32(p.CommentsCount),
-		Upvotes:       int32(p.UpvotesCount),
-		Downvotes:     int32(p.DownvotesCount),
-		IsEdited:      p.IsEdited,
-		CreatedAt:     p.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:             p.ID,
+		Title:          p.Title,
+		Content:        p.Content,
+		Author:         mapPublicUserToModel(author),
+		Group:          mapGroupToModel(group, groupOwner),
+		CommentsCount:  int32(p.CommentsCount),
+		Upvotes:        int32(p.UpvotesCount),
+		Downvotes:      int32(p.DownvotesCount),
+		IsEdited:       p.IsEdited,
+		IsPinned:       p.Pinned,
+		IsLocked:       p.Locked,
+		IsAnnouncement: p.Announcement,
+		Held:           p.Held,
+		CreatedAt:      p.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
@@ -115,6 +276,7 @@ func mapCommentToModel(c *community.Comment, author *users.PublicUser, post *com
 		Downvotes:    int32(c.DownvotesCount),
 		RepliesCount: int32(c.RepliesCount),
 		IsEdited:     c.IsEdited,
+		Held:         c.Held,
 		CreatedAt:    c.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
@@ -132,3 +294,127 @@ func mapUserToPublic(u *users.User) *users.PublicUser {
 		Avatar:      u.Avatar,
 	}
 }
+
+func mapAutomodRuleToModel(rule *automod.Rule) *model.AutomodRule {
+	optional := func(v int) *int32 {
+		if v == 0 {
+			return nil
+		}
+		n := int32(v)
+		return &n
+	}
+	res := &model.AutomodRule{
+		ID:                     rule.ID,
+		Type:                   model.AutomodRuleType(rule.Type),
+		Action:                 model.AutomodAction(rule.Action),
+		Enabled:                rule.Enabled,
+		Words:                  rule.Words,
+		MaxLinks:               optional(rule.MaxLinks),
+		AccountAgeHours:        optional(rule.AccountAgeHours),
+		DuplicateWindowMinutes: optional(rule.DuplicateWindowMinutes),
+		MinKarma:               optional(rule.MinKarma),
+		CreatedBy:              rule.CreatedBy,
+		CreatedAt:              rule.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:              rule.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if res.Words == nil {
+		res.Words = []string{}
+	}
+	if rule.GroupID != "" {
+		groupID := rule.GroupID
+		res.GroupID = &groupID
+	}
+	if rule.Pattern != "" {
+		pattern := rule.Pattern
+		res.Pattern = &pattern
+	}
+	// A link limit of zero is meaningful.
+	if rule.Type == automod.RuleLinkLimit {
+		maxLinks := int32(rule.MaxLinks)
+		res.MaxLinks = &maxLinks
+	}
+	return res
+}
+
+func mapAutomodTriggerToModel(t *automod.Trigger, author *users.PublicUser) *model.AutomodTrigger {
+	res := &model.AutomodTrigger{
+		ID:        t.ID,
+		RuleID:    t.RuleID,
+		RuleType:  model.AutomodRuleType(t.RuleType),
+		Action:    model.AutomodAction(t.Action),
+		Kind:      model.AutomodContentKind(t.Kind),
+		Author:    mapPublicUserToModel(author),
+		GroupID:   t.GroupID,
+		Reason:    t.Reason,
+		Excerpt:   t.Excerpt,
+		CreatedAt: t.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if t.ContentID != "" {
+		contentID := t.ContentID
+		res.ContentID = &contentID
+	}
+	return res
+}
+
+func mapGroupSanctionToModel(s *community.GroupSanction, user *users.PublicUser) *model.GroupSanction {
+	res := &model.GroupSanction{
+		ID:          s.ID,
+		GroupID:     s.GroupID,
+		User:        mapPublicUserToModel(user),
+		Type:        model.GroupSanctionType(s.Type),
+		Reason:      s.Reason,
+		ModeratorID: s.ModeratorID,
+		CreatedAt:   s.CreatedAt.Format("2006-01-02 15:04:05"),
+		Active:      s.Active(time.Now()),
+	}
+	if s.ExpiresAt != nil {
+		expiresAt := s.ExpiresAt.Format("2006-01-02 15:04:05")
+		res.ExpiresAt = &expiresAt
+	}
+	if s.LiftedAt != nil {
+		liftedAt := s.LiftedAt.Format("2006-01-02 15:04:05")
+		res.LiftedAt = &liftedAt
+	}
+	return res
+}
+
+func mapNotificationToModel(n *notifications.Notification, actor *users.PublicUser) *model.Notification {
+	optional := func(id string) *string {
+		if id == "" {
+			return nil
+		}
+		return &id
+	}
+	return &model.Notification{
+		ID:        n.ID,
+		Type:      model.NotificationType(n.Type),
+		Actor:     mapPublicUserToModel(actor),
+		GroupID:   optional(n.GroupID),
+		PostID:    optional(n.PostID),
+		CommentID: optional(n.CommentID),
+		ChannelID: optional(n.ChannelID),
+		ArticleID: optional(n.ArticleID),
+		Message:   n.Message,
+		Read:      n.Read,
+		CreatedAt: n.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func mapAttachmentToModel(a *attachments.Attachment) *model.Attachment {
+	res := &model.Attachment{
+		ID:          a.ID,
+		Kind:        model.AttachmentKind(a.Kind),
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        int32(a.Size),
+		URL:         a.URL,
+		Variants:    mapImageVariantsToModel(a.Variants),
+		CreatedAt:   a.CreatedAt.Format(time.RFC3339),
+	}
+	if a.Kind == attachments.KindImage {
+		width, height := int32(a.Width), int32(a.Height)
+		res.Width = &width
+		res.Height = &height
+	}
+	return res
+}
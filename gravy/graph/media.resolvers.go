@@ -0,0 +1,84 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+)
+
+// DeleteMedia is the resolver for the deleteMedia field.
+func (r *mutationResolver) DeleteMedia(ctx context.Context, id string, force *bool) (bool, error) {
+	m, err := r.MediaRepo.Get(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("media not found")
+	}
+	if force == nil || !*force {
+		if len(m.References) > 0 {
+			return false, fmt.Errorf("media is used by %d items", len(m.References))
+		}
+		// References are recorded on save; content written before the
+		// library existed is only found by searching for it.
+		found, err := r.MediaRepo.FindReferences(ctx, m.URLs)
+		if err != nil {
+			return false, fmt.Errorf("failed to check media references: %w", err)
+		}
+		if len(found) > 0 {
+			return false, fmt.Errorf("media is still in use")
+		}
+	}
+
+	if err := media.Remove(ctx, r.MediaRepo, r.Uploader, r.QuotaRepo, m); err != nil {
+		return false, fmt.Errorf("failed to delete media: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionMediaDelete, audit.TargetMedia, m.ID, m, nil)
+	return true, nil
+}
+
+// MediaLibrary is the resolver for the mediaLibrary field.
+func (r *queryResolver) MediaLibrary(ctx context.Context, filter *model.MediaFilter, cursor *string, limit *int32) (*model.MediaPage, error) {
+	l := 50
+	if limit != nil {
+		l = int(*limit)
+	}
+	if l <= 0 || l > maxMediaPageSize {
+		l = maxMediaPageSize
+	}
+
+	var f media.Filter
+	after := ""
+	if cursor != nil {
+		after = *cursor
+	}
+	if filter != nil {
+		f.OwnerID = filter.OwnerID
+		if filter.Purpose != nil {
+			p := imaging.Purpose(*filter.Purpose)
+			f.Purpose = &p
+		}
+		f.Unused = filter.Unused != nil && *filter.Unused
+	}
+
+	list, err := r.MediaRepo.List(ctx, f, after, l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load media: %w", err)
+	}
+
+	page := &model.MediaPage{Items: make([]*model.Media, 0, len(list))}
+	for _, m := range list {
+		page.Items = append(page.Items, mapMediaToModel(m))
+	}
+	if len(list) == l {
+		next := list[len(list)-1].ID
+		page.NextCursor = &next
+	}
+	return page, nil
+}
@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+)
+
+const maxMediaPageSize = 200
+
+// trackMedia records which media the content of a source shows. Call it
+// without texts when the source is deleted. Failures are only logged: the
+// media GC checks content itself before deleting anything.
+func (r *Resolver) trackMedia(ctx context.Context, refType media.RefType, id string, texts ...string) {
+	ref := media.Reference{Type: refType, ID: id}
+	if err := r.MediaRepo.SetReferences(ctx, ref, media.ExtractURLs(texts...)); err != nil {
+		log.Printf("Failed to track media of %s %s: %v", refType, id, err)
+	}
+}
+
+func mapMediaToModel(m *media.Media) *model.Media {
+	refs := make([]*model.MediaReference, 0, len(m.References))
+	for _, ref := range m.References {
+		refs = append(refs, &model.MediaReference{Type: model.MediaReferenceType(ref.Type), ID: ref.ID})
+	}
+	return &model.Media{
+		ID:          m.ID,
+		OwnerID:     m.OwnerID,
+		Purpose:     model.ImagePurpose(m.Purpose),
+		Folder:      m.Folder,
+		Filename:    m.Filename,
+		ContentType: m.ContentType,
+		Size:        int32(m.Size),
+		Hash:        m.Hash,
+		URL:         m.URL,
+		Width:       int32(m.Width),
+		Height:      int32(m.Height),
+		Variants:    mapImageVariantsToModel(m.Variants),
+		References:  refs,
+		CreatedAt:   m.CreatedAt.Format(time.RFC3339),
+		UploadedAt:  m.UploadedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mentions"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/notifications"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+// mentionSource is the post, comment or message whose mentions are being
+// recorded.
+type mentionSource struct {
+	Type      mentions.SourceType
+	ID        string
+	Group     *community.Group
+	PostID    string
+	CommentID string
+	ChannelID string
+	// Held content is recorded but nobody is notified until it is released.
+	Held bool
+	// Where names the content in notifications, e.g. `a comment on "Title"`.
+	Where string
+}
+
+func postMentionSource(post *community.Post, group *community.Group) mentionSource {
+	return mentionSource{
+		Type:   mentions.SourcePost,
+		ID:     post.ID,
+		Group:  group,
+		PostID: post.ID,
+		Held:   post.Held,
+		Where:  fmt.Sprintf("the post %q", post.Title),
+	}
+}
+
+func commentMentionSource(comment *community.Comment, post *community.Post, group *community.Group) mentionSource {
+	return mentionSource{
+		Type:      mentions.SourceComment,
+		ID:        comment.ID,
+		Group:     group,
+		PostID:    post.ID,
+		CommentID: comment.ID,
+		Held:      comment.Held,
+		Where:     fmt.Sprintf("a comment on %q", post.Title),
+	}
+}
+
+func messageMentionSource(message *community.Message, channel *community.Channel, group *community.Group) mentionSource {
+	return mentionSource{
+		Type:      mentions.SourceMessage,
+		ID:        message.ID,
+		Group:     group,
+		ChannelID: channel.ID,
+		Held:      message.Held,
+		Where:     fmt.Sprintf("#%s in %s", channel.Name, group.Name),
+	}
+}
+
+// canSeeMention reports whether a mentioned user can see content in group.
+// Private groups and discussions are members only.
+func (r *Resolver) canSeeMention(ctx context.Context, userID string, src mentionSource) bool {
+	if src.Group.Type == community.GroupTypePublic && src.Type != mentions.SourceMessage {
+		return true
+	}
+	isMember, err := r.CommunityRepo.IsMember(ctx, src.Group.ID, userID)
+	return err == nil && isMember
+}
+
+// resolveMentions looks up the users and articles mentioned in content.
+// Unknown names and titles, and users who can't see the content, are skipped.
+func (r *Resolver) resolveMentions(ctx context.Context, actor *users.User, src mentionSource, content string) []*mentions.Mention {
+	parsed := mentions.Parse(content)
+	if parsed.Empty() {
+		return nil
+	}
+
+	var list []*mentions.Mention
+	for _, name := range parsed.Usernames {
+		u, err := r.UserRepo.GetByUsername(ctx, name)
+		if err != nil || !r.canSeeMention(ctx, u.ID, src) {
+			continue
+		}
+		list = append(list, &mentions.Mention{
+			GroupID:  src.Group.ID,
+			AuthorID: actor.ID,
+			Kind:     mentions.KindUser,
+			Text:     name,
+			UserID:   u.ID,
+		})
+	}
+	for _, title := range parsed.Titles {
+		a, err := r.ArticleRepo.GetByTitle(ctx, title)
+		if err != nil {
+			continue
+		}
+		list = append(list, &mentions.Mention{
+			GroupID:   src.Group.ID,
+			AuthorID:  actor.ID,
+			Kind:      mentions.KindArticle,
+			Text:      title,
+			ArticleID: a.ID,
+		})
+	}
+	return list
+}
+
+// updateMentions stores the mentions in content and notifies users who
+// weren't already mentioned there. Failures are only logged.
+func (r *Resolver) updateMentions(ctx context.Context, actor *users.User, src mentionSource, content string) {
+	list := r.resolveMentions(ctx, actor, src, content)
+	previous, err := r.MentionRepo.Replace(ctx, src.Type, src.ID, list)
+	if err != nil {
+		log.Printf("Failed to save mentions for %s %s: %v", src.Type, src.ID, err)
+		return
+	}
+	if src.Held {
+		return
+	}
+	r.notifyMentions(ctx, actor, src, list, previous)
+}
+
+// notifyMentions notifies the users mentioned in list, except those already
+// mentioned in previous.
+func (r *Resolver) notifyMentions(ctx context.Context, actor *users.User, src mentionSource, list, previous []*mentions.Mention) {
+	already := make(map[string]bool)
+	for _, m := range previous {
+		already[m.UserID] = true
+	}
+	for _, m := range list {
+		if m.Kind != mentions.KindUser || already[m.UserID] {
+			continue
+		}
+		r.notify(ctx, &notifications.Notification{
+			UserID:    m.UserID,
+			Type:      notifications.TypeMention,
+			ActorID:   actor.ID,
+			GroupID:   src.Group.ID,
+			PostID:    src.PostID,
+			CommentID: src.CommentID,
+			ChannelID: src.ChannelID,
+			Message:   fmt.Sprintf("%s mentioned you in %s", displayName(actor), src.Where),
+		})
+	}
+}
+
+// notifyHeldMentions sends the mention notifications held back while src
+// waited for review.
+func (r *Resolver) notifyHeldMentions(ctx context.Context, actor *users.User, src mentionSource) {
+	list, err := r.MentionRepo.List(ctx, src.Type, src.ID)
+	if err != nil {
+		log.Printf("Failed to load mentions for %s %s: %v", src.Type, src.ID, err)
+		return
+	}
+	r.notifyMentions(ctx, actor, src, list, nil)
+}
+
+func (r *Resolver) deleteMentions(ctx context.Context, sourceType mentions.SourceType, sourceID string) {
+	if err := r.MentionRepo.DeleteForSource(ctx, sourceType, sourceID); err != nil {
+		log.Printf("Failed to delete mentions for %s %s: %v", sourceType, sourceID, err)
+	}
+}
+
+func (r *Resolver) mentionsToModel(ctx context.Context, sourceType mentions.SourceType, sourceID string) ([]*model.Mention, error) {
+	list, err := r.MentionRepo.List(ctx, sourceType, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mentions: %w", err)
+	}
+	res := make([]*model.Mention, 0, len(list))
+	for _, m := range list {
+		mention := &model.Mention{Kind: model.MentionKind(m.Kind), Text: m.Text}
+		switch m.Kind {
+		case mentions.KindUser:
+			if u, err := r.UserRepo.GetByID(ctx, m.UserID); err == nil {
+				mention.User = mapPublicUserToModel(mapUserToPublic(u))
+			}
+		case mentions.KindArticle:
+			if a, err := r.ArticleRepo.GetByID(ctx, m.ArticleID); err == nil {
+				if author, err := r.UserRepo.GetByID(ctx, a.AuthorID); err == nil {
+					a.Author = mapUserToPublic(author)
+				}
+				mention.Article = mapArticleToModel(a)
+			}
+		}
+		res = append(res, mention)
+	}
+	return res, nil
+}
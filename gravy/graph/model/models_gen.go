@@ -13,6 +13,17 @@ type CommunityResult interface {
 	IsCommunityResult()
 }
 
+type APIToken struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Hint       string       `json:"hint"`
+	Scopes     []TokenScope `json:"scopes"`
+	CreatedAt  string       `json:"createdAt"`
+	ExpiresAt  *string      `json:"expiresAt,omitempty"`
+	LastUsedAt *string      `json:"lastUsedAt,omitempty"`
+	Revoked    bool         `json:"revoked"`
+}
+
 type Article struct {
 	ID          string      `json:"id"`
 	Title       string      `json:"title"`
@@ -27,6 +38,126 @@ type Article struct {
 	UpdatedAt   string      `json:"updatedAt"`
 }
 
+type ArticleFacets struct {
+	Categories []*FacetCount `json:"categories"`
+	Authors    []*FacetCount `json:"authors"`
+	Featured   []*FacetCount `json:"featured"`
+}
+
+type ArticleSearchFilter struct {
+	Categories []string `json:"categories,omitempty"`
+	AuthorID   *string  `json:"authorId,omitempty"`
+	Since      *string  `json:"since,omitempty"`
+	Until      *string  `json:"until,omitempty"`
+	Featured   *bool    `json:"featured,omitempty"`
+}
+
+type ArticleSearchHit struct {
+	Article *Article `json:"article"`
+	Title   string   `json:"title"`
+	Snippet string   `json:"snippet"`
+}
+
+type Attachment struct {
+	ID          string          `json:"id"`
+	Kind        AttachmentKind  `json:"kind"`
+	Filename    string          `json:"filename"`
+	ContentType string          `json:"contentType"`
+	Size        int32           `json:"size"`
+	Width       *int32          `json:"width,omitempty"`
+	Height      *int32          `json:"height,omitempty"`
+	URL         string          `json:"url"`
+	Variants    []*ImageVariant `json:"variants"`
+	CreatedAt   string          `json:"createdAt"`
+}
+
+type AuditEntry struct {
+	ID         string  `json:"id"`
+	ActorID    string  `json:"actorId"`
+	Action     string  `json:"action"`
+	TargetType string  `json:"targetType"`
+	TargetID   string  `json:"targetId"`
+	Before     *string `json:"before,omitempty"`
+	After      *string `json:"after,omitempty"`
+	IP         *string `json:"ip,omitempty"`
+	UserAgent  *string `json:"userAgent,omitempty"`
+	APITokenID *string `json:"apiTokenId,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+type AuditLogFilter struct {
+	ActorID    *string `json:"actorId,omitempty"`
+	Action     *string `json:"action,omitempty"`
+	TargetType *string `json:"targetType,omitempty"`
+	TargetID   *string `json:"targetId,omitempty"`
+	Since      *string `json:"since,omitempty"`
+	Until      *string `json:"until,omitempty"`
+}
+
+type AuditLogPage struct {
+	Entries    []*AuditEntry `json:"entries"`
+	NextCursor *string       `json:"nextCursor,omitempty"`
+}
+
+type AutomodRule struct {
+	ID                     string          `json:"id"`
+	GroupID                *string         `json:"groupId,omitempty"`
+	Type                   AutomodRuleType `json:"type"`
+	Action                 AutomodAction   `json:"action"`
+	Enabled                bool            `json:"enabled"`
+	Words                  []string        `json:"words"`
+	Pattern                *string         `json:"pattern,omitempty"`
+	MaxLinks               *int32          `json:"maxLinks,omitempty"`
+	AccountAgeHours        *int32          `json:"accountAgeHours,omitempty"`
+	DuplicateWindowMinutes *int32          `json:"duplicateWindowMinutes,omitempty"`
+	MinKarma               *int32          `json:"minKarma,omitempty"`
+	CreatedBy              string          `json:"createdBy"`
+	CreatedAt              string          `json:"createdAt"`
+	UpdatedAt              string          `json:"updatedAt"`
+}
+
+type AutomodTrigger struct {
+	ID        string             `json:"id"`
+	RuleID    string             `json:"ruleId"`
+	RuleType  AutomodRuleType    `json:"ruleType"`
+	Action    AutomodAction      `json:"action"`
+	Kind      AutomodContentKind `json:"kind"`
+	ContentID *string            `json:"contentId,omitempty"`
+	Author    *PublicUser        `json:"author,omitempty"`
+	GroupID   string             `json:"groupId"`
+	Reason    string             `json:"reason"`
+	Excerpt   string             `json:"excerpt"`
+	CreatedAt string             `json:"createdAt"`
+}
+
+type Ban struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"userId"`
+	Reason      string  `json:"reason"`
+	ModeratorID string  `json:"moderatorId"`
+	StartsAt    string  `json:"startsAt"`
+	EndsAt      *string `json:"endsAt,omitempty"`
+	LiftedAt    *string `json:"liftedAt,omitempty"`
+	Active      bool    `json:"active"`
+}
+
+type BanAppeal struct {
+	ID         string       `json:"id"`
+	Ban        *Ban         `json:"ban"`
+	User       *PublicUser  `json:"user"`
+	Message    string       `json:"message"`
+	Status     AppealStatus `json:"status"`
+	Response   *string      `json:"response,omitempty"`
+	CreatedAt  string       `json:"createdAt"`
+	ReviewedAt *string      `json:"reviewedAt,omitempty"`
+}
+
+type BanStatus struct {
+	Banned bool       `json:"banned"`
+	Ban    *Ban       `json:"ban,omitempty"`
+	Appeal *BanAppeal `json:"appeal,omitempty"`
+}
+
 type Category struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
@@ -34,6 +165,11 @@ type Category struct {
 	CreatedAt string `json:"createdAt"`
 }
 
+type ChangePasswordInput struct {
+	CurrentPassword *string `json:"currentPassword,omitempty"`
+	NewPassword     string  `json:"newPassword"`
+}
+
 type Channel struct {
 	ID         string      `json:"id"`
 	Name       string      `json:"name"`
@@ -54,7 +190,9 @@ type Comment struct {
 	Downvotes    int32       `json:"downvotes"`
 	UserVote     VoteType    `json:"userVote"`
 	IsEdited     bool        `json:"isEdited"`
+	Held         bool        `json:"held"`
 	CreatedAt    string      `json:"createdAt"`
+	Mentions     []*Mention  `json:"mentions"`
 }
 
 func (Comment) IsCommunityResult() {}
@@ -64,12 +202,27 @@ type CompleteSetupInput struct {
 	DisplayName string `json:"displayName"`
 }
 
+type CreatedAPIToken struct {
+	Token    string    `json:"token"`
+	APIToken *APIToken `json:"apiToken"`
+}
+
+type CreatedWebhook struct {
+	Webhook *Webhook `json:"webhook"`
+	Secret  string   `json:"secret"`
+}
+
 type Discussion struct {
 	ID       string     `json:"id"`
 	Group    *Group     `json:"group"`
 	Channels []*Channel `json:"channels"`
 }
 
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int32  `json:"count"`
+}
+
 type Group struct {
 	ID                string        `json:"id"`
 	Name              string        `json:"name"`
@@ -78,6 +231,8 @@ type Group struct {
 	Slug              string        `json:"slug"`
 	Type              GroupType     `json:"type"`
 	Owner             *PublicUser   `json:"owner"`
+	Moderators        []*PublicUser `json:"moderators"`
+	ViewerRole        *GroupRole    `json:"viewerRole,omitempty"`
 	MembersCount      int32         `json:"membersCount"`
 	IsMember          bool          `json:"isMember"`
 	Posts             []*Post       `json:"posts"`
@@ -86,13 +241,51 @@ type Group struct {
 	JoinRequests      []*PublicUser `json:"joinRequests,omitempty"`
 	Members           []*PublicUser `json:"members,omitempty"`
 	HasPendingRequest bool          `json:"hasPendingRequest"`
+	ViewerMutedUntil  *string       `json:"viewerMutedUntil,omitempty"`
+	MinPostKarma      int32         `json:"minPostKarma"`
+	MinCommentKarma   int32         `json:"minCommentKarma"`
 }
 
 func (Group) IsCommunityResult() {}
 
+type GroupKarma struct {
+	Group        *Group `json:"group"`
+	PostKarma    int32  `json:"postKarma"`
+	CommentKarma int32  `json:"commentKarma"`
+	Karma        int32  `json:"karma"`
+}
+
+type GroupSanction struct {
+	ID          string            `json:"id"`
+	GroupID     string            `json:"groupId"`
+	User        *PublicUser       `json:"user,omitempty"`
+	Type        GroupSanctionType `json:"type"`
+	Reason      string            `json:"reason"`
+	ModeratorID string            `json:"moderatorId"`
+	CreatedAt   string            `json:"createdAt"`
+	ExpiresAt   *string           `json:"expiresAt,omitempty"`
+	LiftedAt    *string           `json:"liftedAt,omitempty"`
+	Active      bool              `json:"active"`
+}
+
+type Image struct {
+	URL      string          `json:"url"`
+	Width    int32           `json:"width"`
+	Height   int32           `json:"height"`
+	Variants []*ImageVariant `json:"variants"`
+}
+
+type ImageVariant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Width  int32  `json:"width"`
+	Height int32  `json:"height"`
+}
+
 type LoginInput struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email         string  `json:"email"`
+	Password      string  `json:"password"`
+	TwoFactorCode *string `json:"twoFactorCode,omitempty"`
 }
 
 type MapLocation struct {
@@ -112,6 +305,47 @@ type MapLocationInput struct {
 	Menu        []*MenuItemInput `json:"menu,omitempty"`
 }
 
+type Media struct {
+	ID          string            `json:"id"`
+	OwnerID     string            `json:"ownerId"`
+	Purpose     ImagePurpose      `json:"purpose"`
+	Folder      string            `json:"folder"`
+	Filename    string            `json:"filename"`
+	ContentType string            `json:"contentType"`
+	Size        int32             `json:"size"`
+	Hash        string            `json:"hash"`
+	URL         string            `json:"url"`
+	Width       int32             `json:"width"`
+	Height      int32             `json:"height"`
+	Variants    []*ImageVariant   `json:"variants"`
+	References  []*MediaReference `json:"references"`
+	CreatedAt   string            `json:"createdAt"`
+	UploadedAt  string            `json:"uploadedAt"`
+}
+
+type MediaFilter struct {
+	OwnerID *string       `json:"ownerId,omitempty"`
+	Purpose *ImagePurpose `json:"purpose,omitempty"`
+	Unused  *bool         `json:"unused,omitempty"`
+}
+
+type MediaPage struct {
+	Items      []*Media `json:"items"`
+	NextCursor *string  `json:"nextCursor,omitempty"`
+}
+
+type MediaReference struct {
+	Type MediaReferenceType `json:"type"`
+	ID   string             `json:"id"`
+}
+
+type Mention struct {
+	Kind    MentionKind `json:"kind"`
+	Text    string      `json:"text"`
+	User    *PublicUser `json:"user,omitempty"`
+	Article *Article    `json:"article,omitempty"`
+}
+
 type MenuItem struct {
 	Item  string `json:"item"`
 	Price string `json:"price"`
@@ -123,16 +357,24 @@ type MenuItemInput struct {
 }
 
 type Message struct {
-	ID        string      `json:"id"`
-	Content   string      `json:"content"`
-	Sender    *PublicUser `json:"sender"`
-	Channel   *Channel    `json:"channel"`
-	CreatedAt string      `json:"createdAt"`
+	ID          string        `json:"id"`
+	Content     string        `json:"content"`
+	Sender      *PublicUser   `json:"sender"`
+	Channel     *Channel      `json:"channel"`
+	Held        bool          `json:"held"`
+	CreatedAt   string        `json:"createdAt"`
+	Attachments []*Attachment `json:"attachments"`
 }
 
 type Mutation struct {
 }
 
+type NewAPIToken struct {
+	Name          string       `json:"name"`
+	Scopes        []TokenScope `json:"scopes"`
+	ExpiresInDays *int32       `json:"expiresInDays,omitempty"`
+}
+
 type NewArticle struct {
 	Title     string `json:"title"`
 	Content   string `json:"content"`
@@ -141,6 +383,19 @@ type NewArticle struct {
 	Featured  bool   `json:"featured"`
 }
 
+type NewAutomodRule struct {
+	GroupID                *string         `json:"groupId,omitempty"`
+	Type                   AutomodRuleType `json:"type"`
+	Action                 AutomodAction   `json:"action"`
+	Enabled                *bool           `json:"enabled,omitempty"`
+	Words                  []string        `json:"words,omitempty"`
+	Pattern                *string         `json:"pattern,omitempty"`
+	MaxLinks               *int32          `json:"maxLinks,omitempty"`
+	AccountAgeHours        *int32          `json:"accountAgeHours,omitempty"`
+	DuplicateWindowMinutes *int32          `json:"duplicateWindowMinutes,omitempty"`
+	MinKarma               *int32          `json:"minKarma,omitempty"`
+}
+
 type NewChannel struct {
 	DiscussionID string      `json:"discussionId"`
 	Name         string      `json:"name"`
@@ -160,59 +415,176 @@ type NewGroup struct {
 }
 
 type NewMessage struct {
-	ChannelID string `json:"channelId"`
-	Content   string `json:"content"`
+	ChannelID     string   `json:"channelId"`
+	Content       string   `json:"content"`
+	AttachmentIds []string `json:"attachmentIds,omitempty"`
+}
+
+type NewPoll struct {
+	Options        []string `json:"options"`
+	MultipleChoice *bool    `json:"multipleChoice,omitempty"`
+	Anonymous      *bool    `json:"anonymous,omitempty"`
+	ClosesAt       *string  `json:"closesAt,omitempty"`
 }
 
 type NewPost struct {
-	GroupID string `json:"groupId"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	GroupID       string   `json:"groupId"`
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	Announcement  *bool    `json:"announcement,omitempty"`
+	AttachmentIds []string `json:"attachmentIds,omitempty"`
+	Poll          *NewPoll `json:"poll,omitempty"`
 }
 
 type NewUser struct {
-	ID           string `json:"id"`
-	Name         string `json:"name"`
-	Email        string `json:"email"`
-	Gender       string `json:"gender"`
-	PhoneNumber  string `json:"phoneNumber"`
-	MachineToken string `json:"machineToken"`
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Email         string  `json:"email"`
+	Gender        string  `json:"gender"`
+	PhoneNumber   string  `json:"phoneNumber"`
+	MachineToken  string  `json:"machineToken"`
+	TwoFactorCode *string `json:"twoFactorCode,omitempty"`
+}
+
+type NewWebhook struct {
+	GroupID *string        `json:"groupId,omitempty"`
+	URL     string         `json:"url"`
+	Events  []WebhookEvent `json:"events"`
+}
+
+type Notification struct {
+	ID        string           `json:"id"`
+	Type      NotificationType `json:"type"`
+	Actor     *PublicUser      `json:"actor,omitempty"`
+	GroupID   *string          `json:"groupId,omitempty"`
+	PostID    *string          `json:"postId,omitempty"`
+	CommentID *string          `json:"commentId,omitempty"`
+	ChannelID *string          `json:"channelId,omitempty"`
+	ArticleID *string          `json:"articleId,omitempty"`
+	Message   string           `json:"message"`
+	Read      bool             `json:"read"`
+	CreatedAt string           `json:"createdAt"`
+}
+
+type NotificationPage struct {
+	Notifications []*Notification `json:"notifications"`
+	NextCursor    *string         `json:"nextCursor,omitempty"`
+}
+
+type NotificationPreference struct {
+	Type    NotificationType `json:"type"`
+	Enabled bool             `json:"enabled"`
+}
+
+type Poll struct {
+	ID             string        `json:"id"`
+	Options        []*PollOption `json:"options"`
+	MultipleChoice bool          `json:"multipleChoice"`
+	Anonymous      bool          `json:"anonymous"`
+	ClosesAt       *string       `json:"closesAt,omitempty"`
+	Closed         bool          `json:"closed"`
+	TotalVoters    *int32        `json:"totalVoters,omitempty"`
+	MyChoice       []int32       `json:"myChoice,omitempty"`
+	ResultsVisible bool          `json:"resultsVisible"`
+}
+
+type PollOption struct {
+	Index  int32         `json:"index"`
+	Text   string        `json:"text"`
+	Count  *int32        `json:"count,omitempty"`
+	Voters []*PublicUser `json:"voters,omitempty"`
 }
 
 type Post struct {
-	ID            string      `json:"id"`
-	Title         string      `json:"title"`
-	Content       string      `json:"content"`
-	Author        *PublicUser `json:"author"`
-	Group         *Group      `json:"group"`
-	CommentsCount int32       `json:"commentsCount"`
-	Upvotes       int32       `json:"upvotes"`
-	Downvotes     int32       `json:"downvotes"`
-	UserVote      VoteType    `json:"userVote"`
-	Comments      []*Comment  `json:"comments"`
-	IsEdited      bool        `json:"isEdited"`
-	CreatedAt     string      `json:"createdAt"`
+	ID             string        `json:"id"`
+	Title          string        `json:"title"`
+	Content        string        `json:"content"`
+	Author         *PublicUser   `json:"author"`
+	Group          *Group        `json:"group"`
+	CommentsCount  int32         `json:"commentsCount"`
+	Upvotes        int32         `json:"upvotes"`
+	Downvotes      int32         `json:"downvotes"`
+	UserVote       VoteType      `json:"userVote"`
+	Comments       []*Comment    `json:"comments"`
+	IsEdited       bool          `json:"isEdited"`
+	IsPinned       bool          `json:"isPinned"`
+	IsLocked       bool          `json:"isLocked"`
+	IsAnnouncement bool          `json:"isAnnouncement"`
+	Held           bool          `json:"held"`
+	CreatedAt      string        `json:"createdAt"`
+	Attachments    []*Attachment `json:"attachments"`
+	Mentions       []*Mention    `json:"mentions"`
+	Poll           *Poll         `json:"poll,omitempty"`
 }
 
 func (Post) IsCommunityResult() {}
 
 type PublicUser struct {
-	ID          string     `json:"id"`
-	Name        string     `json:"name"`
-	Username    string     `json:"username"`
-	DisplayName string     `json:"displayName"`
-	Gender      string     `json:"gender"`
-	Avatar      string     `json:"avatar"`
-	Posts       []*Post    `json:"posts"`
-	Comments    []*Comment `json:"comments"`
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Username    string        `json:"username"`
+	DisplayName string        `json:"displayName"`
+	Gender      string        `json:"gender"`
+	Avatar      string        `json:"avatar"`
+	Posts       []*Post       `json:"posts"`
+	Comments    []*Comment    `json:"comments"`
+	Karma       int32         `json:"karma"`
+	GroupKarma  []*GroupKarma `json:"groupKarma"`
 }
 
 type Query struct {
 }
 
+type ReportCase struct {
+	ID           string            `json:"id"`
+	TargetType   ReportTargetType  `json:"targetType"`
+	TargetID     string            `json:"targetId"`
+	TargetAuthor *PublicUser       `json:"targetAuthor,omitempty"`
+	GroupID      string            `json:"groupId"`
+	Content      *string           `json:"content,omitempty"`
+	Status       ReportStatus      `json:"status"`
+	ReportCount  int32             `json:"reportCount"`
+	Reports      []*ReportEntry    `json:"reports"`
+	Resolution   *ReportResolution `json:"resolution,omitempty"`
+	CreatedAt    string            `json:"createdAt"`
+}
+
+type ReportEntry struct {
+	ReporterID string       `json:"reporterId"`
+	Reason     ReportReason `json:"reason"`
+	Note       *string      `json:"note,omitempty"`
+	CreatedAt  string       `json:"createdAt"`
+}
+
+type ReportResolution struct {
+	Action      ModerationAction `json:"action"`
+	ModeratorID string           `json:"moderatorId"`
+	Note        *string          `json:"note,omitempty"`
+	ResolvedAt  string           `json:"resolvedAt"`
+}
+
+type SearchResult struct {
+	Hits   []*ArticleSearchHit `json:"hits"`
+	Total  int32               `json:"total"`
+	Facets *ArticleFacets      `json:"facets"`
+}
+
+type StorageUsage struct {
+	UsedBytes        int    `json:"usedBytes"`
+	LimitBytes       int    `json:"limitBytes"`
+	UploadsToday     int32  `json:"uploadsToday"`
+	DailyUploadLimit int32  `json:"dailyUploadLimit"`
+	ResetsAt         string `json:"resetsAt"`
+}
+
 type Subscription struct {
 }
 
+type TwoFactorConfirmation struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+	Token         string   `json:"token"`
+}
+
 type UpdateArticle struct {
 	ID        string  `json:"id"`
 	Title     *string `json:"title,omitempty"`
@@ -222,67 +594,117 @@ type UpdateArticle struct {
 	Featured  *bool   `json:"featured,omitempty"`
 }
 
+type UpdateAutomodRule struct {
+	Action                 *AutomodAction `json:"action,omitempty"`
+	Enabled                *bool          `json:"enabled,omitempty"`
+	Words                  []string       `json:"words,omitempty"`
+	Pattern                *string        `json:"pattern,omitempty"`
+	MaxLinks               *int32         `json:"maxLinks,omitempty"`
+	AccountAgeHours        *int32         `json:"accountAgeHours,omitempty"`
+	DuplicateWindowMinutes *int32         `json:"duplicateWindowMinutes,omitempty"`
+	MinKarma               *int32         `json:"minKarma,omitempty"`
+}
+
 type UpdateUserInput struct {
 	Username    *string `json:"username,omitempty"`
 	DisplayName *string `json:"displayName,omitempty"`
 	Avatar      *string `json:"avatar,omitempty"`
 }
 
+type UpdateWebhook struct {
+	URL    *string        `json:"url,omitempty"`
+	Events []WebhookEvent `json:"events,omitempty"`
+	Active *bool          `json:"active,omitempty"`
+}
+
 type User struct {
-	ID            string `json:"id"`
-	Name          string `json:"name"`
-	Username      string `json:"username"`
-	DisplayName   string `json:"displayName"`
-	Email         string `json:"email"`
-	Gender        string `json:"gender"`
-	Avatar        string `json:"avatar"`
-	PhoneNumber   string `json:"phoneNumber"`
-	SetupComplete bool   `json:"setupComplete"`
-	IsAdmin       bool   `json:"isAdmin"`
-	IsBanned      bool   `json:"isBanned"`
-	CreatedAt     string `json:"createdAt"`
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	Username         string        `json:"username"`
+	DisplayName      string        `json:"displayName"`
+	Email            string        `json:"email"`
+	Gender           string        `json:"gender"`
+	Avatar           string        `json:"avatar"`
+	PhoneNumber      string        `json:"phoneNumber"`
+	SetupComplete    bool          `json:"setupComplete"`
+	EmailVerified    bool          `json:"emailVerified"`
+	TwoFactorEnabled bool          `json:"twoFactorEnabled"`
+	IsAdmin          bool          `json:"isAdmin"`
+	Roles            []Role        `json:"roles"`
+	IsBanned         bool          `json:"isBanned"`
+	CreatedAt        string        `json:"createdAt"`
+	StorageUsage     *StorageUsage `json:"storageUsage,omitempty"`
 }
 
-type ChannelType string
+type Webhook struct {
+	ID        string         `json:"id"`
+	GroupID   *string        `json:"groupId,omitempty"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	Active    bool           `json:"active"`
+	CreatedBy string         `json:"createdBy"`
+	CreatedAt string         `json:"createdAt"`
+	UpdatedAt string         `json:"updatedAt"`
+}
+
+type WebhookDelivery struct {
+	ID            string                `json:"id"`
+	WebhookID     string                `json:"webhookId"`
+	Event         WebhookEvent          `json:"event"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int32                 `json:"attempts"`
+	ResponseCode  *int32                `json:"responseCode,omitempty"`
+	Error         *string               `json:"error,omitempty"`
+	Payload       string                `json:"payload"`
+	CreatedAt     string                `json:"createdAt"`
+	NextAttemptAt *string               `json:"nextAttemptAt,omitempty"`
+	DeliveredAt   *string               `json:"deliveredAt,omitempty"`
+}
+
+type AppealStatus string
 
 const (
-	ChannelTypeText ChannelType = "TEXT"
+	AppealStatusPending  AppealStatus = "PENDING"
+	AppealStatusAccepted AppealStatus = "ACCEPTED"
+	AppealStatusRejected AppealStatus = "REJECTED"
 )
 
-var AllChannelType = []ChannelType{
-	ChannelTypeText,
+var AllAppealStatus = []AppealStatus{
+	AppealStatusPending,
+	AppealStatusAccepted,
+	AppealStatusRejected,
 }
 
-func (e ChannelType) IsValid() bool {
+func (e AppealStatus) IsValid() bool {
 	switch e {
-	case ChannelTypeText:
+	case AppealStatusPending, AppealStatusAccepted, AppealStatusRejected:
 		return true
 	}
 	return false
 }
 
-func (e ChannelType) String() string {
+func (e AppealStatus) String() string {
 	return string(e)
 }
 
-func (e *ChannelType) UnmarshalGQL(v any) error {
+func (e *AppealStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = ChannelType(str)
+	*e = AppealStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid ChannelType", str)
+		return fmt.Errorf("%s is not a valid AppealStatus", str)
 	}
 	return nil
 }
 
-func (e ChannelType) MarshalGQL(w io.Writer) {
+func (e AppealStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *ChannelType) UnmarshalJSON(b []byte) error {
+func (e *AppealStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -290,54 +712,58 @@ func (e *ChannelType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e ChannelType) MarshalJSON() ([]byte, error) {
+func (e AppealStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type GroupType string
+type ArticleSort string
 
 const (
-	GroupTypePublic  GroupType = "PUBLIC"
-	GroupTypePrivate GroupType = "PRIVATE"
+	ArticleSortRelevance ArticleSort = "RELEVANCE"
+	ArticleSortNewest    ArticleSort = "NEWEST"
+	ArticleSortOldest    ArticleSort = "OLDEST"
+	ArticleSortTitle     ArticleSort = "TITLE"
 )
 
-var AllGroupType = []GroupType{
-	GroupTypePublic,
-	GroupTypePrivate,
+var AllArticleSort = []ArticleSort{
+	ArticleSortRelevance,
+	ArticleSortNewest,
+	ArticleSortOldest,
+	ArticleSortTitle,
 }
 
-func (e GroupType) IsValid() bool {
+func (e ArticleSort) IsValid() bool {
 	switch e {
-	case GroupTypePublic, GroupTypePrivate:
+	case ArticleSortRelevance, ArticleSortNewest, ArticleSortOldest, ArticleSortTitle:
 		return true
 	}
 	return false
 }
 
-func (e GroupType) String() string {
+func (e ArticleSort) String() string {
 	return string(e)
 }
 
-func (e *GroupType) UnmarshalGQL(v any) error {
+func (e *ArticleSort) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = GroupType(str)
+	*e = ArticleSort(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid GroupType", str)
+		return fmt.Errorf("%s is not a valid ArticleSort", str)
 	}
 	return nil
 }
 
-func (e GroupType) MarshalGQL(w io.Writer) {
+func (e ArticleSort) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *GroupType) UnmarshalJSON(b []byte) error {
+func (e *ArticleSort) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -345,54 +771,54 @@ func (e *GroupType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e GroupType) MarshalJSON() ([]byte, error) {
+func (e ArticleSort) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type Role string
+type AttachmentKind string
 
 const (
-	RoleAdmin Role = "ADMIN"
-	RoleUser  Role = "USER"
+	AttachmentKindImage AttachmentKind = "IMAGE"
+	AttachmentKindFile  AttachmentKind = "FILE"
 )
 
-var AllRole = []Role{
-	RoleAdmin,
-	RoleUser,
+var AllAttachmentKind = []AttachmentKind{
+	AttachmentKindImage,
+	AttachmentKindFile,
 }
 
-func (e Role) IsValid() bool {
+func (e AttachmentKind) IsValid() bool {
 	switch e {
-	case RoleAdmin, RoleUser:
+	case AttachmentKindImage, AttachmentKindFile:
 		return true
 	}
 	return false
 }
 
-func (e Role) String() string {
+func (e AttachmentKind) String() string {
 	return string(e)
 }
 
-func (e *Role) UnmarshalGQL(v any) error {
+func (e *AttachmentKind) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = Role(str)
+	*e = AttachmentKind(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid Role", str)
+		return fmt.Errorf("%s is not a valid AttachmentKind", str)
 	}
 	return nil
 }
 
-func (e Role) MarshalGQL(w io.Writer) {
+func (e AttachmentKind) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *Role) UnmarshalJSON(b []byte) error {
+func (e *AttachmentKind) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -400,56 +826,56 @@ func (e *Role) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e Role) MarshalJSON() ([]byte, error) {
+func (e AttachmentKind) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type VoteType string
+type AutomodAction string
 
 const (
-	VoteTypeUp   VoteType = "UP"
-	VoteTypeDown VoteType = "DOWN"
-	VoteTypeNone VoteType = "NONE"
+	AutomodActionFlag   AutomodAction = "FLAG"
+	AutomodActionHold   AutomodAction = "HOLD"
+	AutomodActionReject AutomodAction = "REJECT"
 )
 
-var AllVoteType = []VoteType{
-	VoteTypeUp,
-	VoteTypeDown,
-	VoteTypeNone,
+var AllAutomodAction = []AutomodAction{
+	AutomodActionFlag,
+	AutomodActionHold,
+	AutomodActionReject,
 }
 
-func (e VoteType) IsValid() bool {
+func (e AutomodAction) IsValid() bool {
 	switch e {
-	case VoteTypeUp, VoteTypeDown, VoteTypeNone:
+	case AutomodActionFlag, AutomodActionHold, AutomodActionReject:
 		return true
 	}
 	return false
 }
 
-func (e VoteType) String() string {
+func (e AutomodAction) String() string {
 	return string(e)
 }
 
-func (e *VoteType) UnmarshalGQL(v any) error {
+func (e *AutomodAction) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = VoteType(str)
+	*e = AutomodAction(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid VoteType", str)
+		return fmt.Errorf("%s is not a valid AutomodAction", str)
 	}
 	return nil
 }
 
-func (e VoteType) MarshalGQL(w io.Writer) {
+func (e AutomodAction) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *VoteType) UnmarshalJSON(b []byte) error {
+func (e *AutomodAction) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -457,7 +883,1368 @@ func (e *VoteType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e VoteType) MarshalJSON() ([]byte, error) {
+func (e AutomodAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type AutomodContentKind string
+
+const (
+	AutomodContentKindPost    AutomodContentKind = "POST"
+	AutomodContentKindComment AutomodContentKind = "COMMENT"
+	AutomodContentKindMessage AutomodContentKind = "MESSAGE"
+)
+
+var AllAutomodContentKind = []AutomodContentKind{
+	AutomodContentKindPost,
+	AutomodContentKindComment,
+	AutomodContentKindMessage,
+}
+
+func (e AutomodContentKind) IsValid() bool {
+	switch e {
+	case AutomodContentKindPost, AutomodContentKindComment, AutomodContentKindMessage:
+		return true
+	}
+	return false
+}
+
+func (e AutomodContentKind) String() string {
+	return string(e)
+}
+
+func (e *AutomodContentKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AutomodContentKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AutomodContentKind", str)
+	}
+	return nil
+}
+
+func (e AutomodContentKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AutomodContentKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AutomodContentKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type AutomodRuleType string
+
+const (
+	AutomodRuleTypeBannedWords   AutomodRuleType = "BANNED_WORDS"
+	AutomodRuleTypeRegex         AutomodRuleType = "REGEX"
+	AutomodRuleTypeLinkLimit     AutomodRuleType = "LINK_LIMIT"
+	AutomodRuleTypeDuplicate     AutomodRuleType = "DUPLICATE"
+	AutomodRuleTypeMinAccountAge AutomodRuleType = "MIN_ACCOUNT_AGE"
+	AutomodRuleTypeMinKarma      AutomodRuleType = "MIN_KARMA"
+)
+
+var AllAutomodRuleType = []AutomodRuleType{
+	AutomodRuleTypeBannedWords,
+	AutomodRuleTypeRegex,
+	AutomodRuleTypeLinkLimit,
+	AutomodRuleTypeDuplicate,
+	AutomodRuleTypeMinAccountAge,
+	AutomodRuleTypeMinKarma,
+}
+
+func (e AutomodRuleType) IsValid() bool {
+	switch e {
+	case AutomodRuleTypeBannedWords, AutomodRuleTypeRegex, AutomodRuleTypeLinkLimit, AutomodRuleTypeDuplicate, AutomodRuleTypeMinAccountAge, AutomodRuleTypeMinKarma:
+		return true
+	}
+	return false
+}
+
+func (e AutomodRuleType) String() string {
+	return string(e)
+}
+
+func (e *AutomodRuleType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AutomodRuleType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AutomodRuleType", str)
+	}
+	return nil
+}
+
+func (e AutomodRuleType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AutomodRuleType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AutomodRuleType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ChannelType string
+
+const (
+	ChannelTypeText ChannelType = "TEXT"
+)
+
+var AllChannelType = []ChannelType{
+	ChannelTypeText,
+}
+
+func (e ChannelType) IsValid() bool {
+	switch e {
+	case ChannelTypeText:
+		return true
+	}
+	return false
+}
+
+func (e ChannelType) String() string {
+	return string(e)
+}
+
+func (e *ChannelType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChannelType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChannelType", str)
+	}
+	return nil
+}
+
+func (e ChannelType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ChannelType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ChannelType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ContentSort string
+
+const (
+	ContentSortNew           ContentSort = "NEW"
+	ContentSortHot           ContentSort = "HOT"
+	ContentSortTop           ContentSort = "TOP"
+	ContentSortControversial ContentSort = "CONTROVERSIAL"
+)
+
+var AllContentSort = []ContentSort{
+	ContentSortNew,
+	ContentSortHot,
+	ContentSortTop,
+	ContentSortControversial,
+}
+
+func (e ContentSort) IsValid() bool {
+	switch e {
+	case ContentSortNew, ContentSortHot, ContentSortTop, ContentSortControversial:
+		return true
+	}
+	return false
+}
+
+func (e ContentSort) String() string {
+	return string(e)
+}
+
+func (e *ContentSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ContentSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ContentSort", str)
+	}
+	return nil
+}
+
+func (e ContentSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ContentSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ContentSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DigestFrequency string
+
+const (
+	DigestFrequencyDaily  DigestFrequency = "DAILY"
+	DigestFrequencyWeekly DigestFrequency = "WEEKLY"
+	DigestFrequencyOff    DigestFrequency = "OFF"
+)
+
+var AllDigestFrequency = []DigestFrequency{
+	DigestFrequencyDaily,
+	DigestFrequencyWeekly,
+	DigestFrequencyOff,
+}
+
+func (e DigestFrequency) IsValid() bool {
+	switch e {
+	case DigestFrequencyDaily, DigestFrequencyWeekly, DigestFrequencyOff:
+		return true
+	}
+	return false
+}
+
+func (e DigestFrequency) String() string {
+	return string(e)
+}
+
+func (e *DigestFrequency) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DigestFrequency(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DigestFrequency", str)
+	}
+	return nil
+}
+
+func (e DigestFrequency) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DigestFrequency) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DigestFrequency) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GroupRole string
+
+const (
+	GroupRoleOwner     GroupRole = "OWNER"
+	GroupRoleModerator GroupRole = "MODERATOR"
+	GroupRoleMember    GroupRole = "MEMBER"
+)
+
+var AllGroupRole = []GroupRole{
+	GroupRoleOwner,
+	GroupRoleModerator,
+	GroupRoleMember,
+}
+
+func (e GroupRole) IsValid() bool {
+	switch e {
+	case GroupRoleOwner, GroupRoleModerator, GroupRoleMember:
+		return true
+	}
+	return false
+}
+
+func (e GroupRole) String() string {
+	return string(e)
+}
+
+func (e *GroupRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GroupRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GroupRole", str)
+	}
+	return nil
+}
+
+func (e GroupRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GroupRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GroupRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GroupSanctionType string
+
+const (
+	GroupSanctionTypeBan  GroupSanctionType = "BAN"
+	GroupSanctionTypeMute GroupSanctionType = "MUTE"
+)
+
+var AllGroupSanctionType = []GroupSanctionType{
+	GroupSanctionTypeBan,
+	GroupSanctionTypeMute,
+}
+
+func (e GroupSanctionType) IsValid() bool {
+	switch e {
+	case GroupSanctionTypeBan, GroupSanctionTypeMute:
+		return true
+	}
+	return false
+}
+
+func (e GroupSanctionType) String() string {
+	return string(e)
+}
+
+func (e *GroupSanctionType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GroupSanctionType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GroupSanctionType", str)
+	}
+	return nil
+}
+
+func (e GroupSanctionType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GroupSanctionType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GroupSanctionType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GroupType string
+
+const (
+	GroupTypePublic  GroupType = "PUBLIC"
+	GroupTypePrivate GroupType = "PRIVATE"
+)
+
+var AllGroupType = []GroupType{
+	GroupTypePublic,
+	GroupTypePrivate,
+}
+
+func (e GroupType) IsValid() bool {
+	switch e {
+	case GroupTypePublic, GroupTypePrivate:
+		return true
+	}
+	return false
+}
+
+func (e GroupType) String() string {
+	return string(e)
+}
+
+func (e *GroupType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GroupType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GroupType", str)
+	}
+	return nil
+}
+
+func (e GroupType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GroupType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GroupType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImagePurpose string
+
+const (
+	ImagePurposeAvatar  ImagePurpose = "AVATAR"
+	ImagePurposeArticle ImagePurpose = "ARTICLE"
+	ImagePurposePost    ImagePurpose = "POST"
+)
+
+var AllImagePurpose = []ImagePurpose{
+	ImagePurposeAvatar,
+	ImagePurposeArticle,
+	ImagePurposePost,
+}
+
+func (e ImagePurpose) IsValid() bool {
+	switch e {
+	case ImagePurposeAvatar, ImagePurposeArticle, ImagePurposePost:
+		return true
+	}
+	return false
+}
+
+func (e ImagePurpose) String() string {
+	return string(e)
+}
+
+func (e *ImagePurpose) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImagePurpose(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImagePurpose", str)
+	}
+	return nil
+}
+
+func (e ImagePurpose) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImagePurpose) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImagePurpose) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type MediaReferenceType string
+
+const (
+	MediaReferenceTypeArticle MediaReferenceType = "ARTICLE"
+	MediaReferenceTypePost    MediaReferenceType = "POST"
+	MediaReferenceTypeComment MediaReferenceType = "COMMENT"
+	MediaReferenceTypeMessage MediaReferenceType = "MESSAGE"
+	MediaReferenceTypeUser    MediaReferenceType = "USER"
+	MediaReferenceTypeGroup   MediaReferenceType = "GROUP"
+)
+
+var AllMediaReferenceType = []MediaReferenceType{
+	MediaReferenceTypeArticle,
+	MediaReferenceTypePost,
+	MediaReferenceTypeComment,
+	MediaReferenceTypeMessage,
+	MediaReferenceTypeUser,
+	MediaReferenceTypeGroup,
+}
+
+func (e MediaReferenceType) IsValid() bool {
+	switch e {
+	case MediaReferenceTypeArticle, MediaReferenceTypePost, MediaReferenceTypeComment, MediaReferenceTypeMessage, MediaReferenceTypeUser, MediaReferenceTypeGroup:
+		return true
+	}
+	return false
+}
+
+func (e MediaReferenceType) String() string {
+	return string(e)
+}
+
+func (e *MediaReferenceType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MediaReferenceType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MediaReferenceType", str)
+	}
+	return nil
+}
+
+func (e MediaReferenceType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MediaReferenceType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MediaReferenceType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type MentionKind string
+
+const (
+	MentionKindUser    MentionKind = "USER"
+	MentionKindArticle MentionKind = "ARTICLE"
+)
+
+var AllMentionKind = []MentionKind{
+	MentionKindUser,
+	MentionKindArticle,
+}
+
+func (e MentionKind) IsValid() bool {
+	switch e {
+	case MentionKindUser, MentionKindArticle:
+		return true
+	}
+	return false
+}
+
+func (e MentionKind) String() string {
+	return string(e)
+}
+
+func (e *MentionKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MentionKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MentionKind", str)
+	}
+	return nil
+}
+
+func (e MentionKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MentionKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MentionKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ModerationAction string
+
+const (
+	ModerationActionDismiss       ModerationAction = "DISMISS"
+	ModerationActionRemoveContent ModerationAction = "REMOVE_CONTENT"
+	ModerationActionWarnUser      ModerationAction = "WARN_USER"
+	ModerationActionBanUser       ModerationAction = "BAN_USER"
+)
+
+var AllModerationAction = []ModerationAction{
+	ModerationActionDismiss,
+	ModerationActionRemoveContent,
+	ModerationActionWarnUser,
+	ModerationActionBanUser,
+}
+
+func (e ModerationAction) IsValid() bool {
+	switch e {
+	case ModerationActionDismiss, ModerationActionRemoveContent, ModerationActionWarnUser, ModerationActionBanUser:
+		return true
+	}
+	return false
+}
+
+func (e ModerationAction) String() string {
+	return string(e)
+}
+
+func (e *ModerationAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ModerationAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ModerationAction", str)
+	}
+	return nil
+}
+
+func (e ModerationAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ModerationAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ModerationAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type NotificationType string
+
+const (
+	NotificationTypeReply           NotificationType = "REPLY"
+	NotificationTypeMention         NotificationType = "MENTION"
+	NotificationTypeJoinRequest     NotificationType = "JOIN_REQUEST"
+	NotificationTypeRequestAccepted NotificationType = "REQUEST_ACCEPTED"
+	NotificationTypePostRemoved     NotificationType = "POST_REMOVED"
+	NotificationTypeArticleUpdated  NotificationType = "ARTICLE_UPDATED"
+)
+
+var AllNotificationType = []NotificationType{
+	NotificationTypeReply,
+	NotificationTypeMention,
+	NotificationTypeJoinRequest,
+	NotificationTypeRequestAccepted,
+	NotificationTypePostRemoved,
+	NotificationTypeArticleUpdated,
+}
+
+func (e NotificationType) IsValid() bool {
+	switch e {
+	case NotificationTypeReply, NotificationTypeMention, NotificationTypeJoinRequest, NotificationTypeRequestAccepted, NotificationTypePostRemoved, NotificationTypeArticleUpdated:
+		return true
+	}
+	return false
+}
+
+func (e NotificationType) String() string {
+	return string(e)
+}
+
+func (e *NotificationType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationType", str)
+	}
+	return nil
+}
+
+func (e NotificationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *NotificationType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e NotificationType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type Permission string
+
+const (
+	PermissionArticlesWrite     Permission = "ARTICLES_WRITE"
+	PermissionCategoriesWrite   Permission = "CATEGORIES_WRITE"
+	PermissionMapWrite          Permission = "MAP_WRITE"
+	PermissionCommunityModerate Permission = "COMMUNITY_MODERATE"
+	PermissionUsersView         Permission = "USERS_VIEW"
+	PermissionUsersBan          Permission = "USERS_BAN"
+	PermissionRolesManage       Permission = "ROLES_MANAGE"
+	PermissionAuditView         Permission = "AUDIT_VIEW"
+	PermissionWebhooksManage    Permission = "WEBHOOKS_MANAGE"
+	PermissionMediaManage       Permission = "MEDIA_MANAGE"
+)
+
+var AllPermission = []Permission{
+	PermissionArticlesWrite,
+	PermissionCategoriesWrite,
+	PermissionMapWrite,
+	PermissionCommunityModerate,
+	PermissionUsersView,
+	PermissionUsersBan,
+	PermissionRolesManage,
+	PermissionAuditView,
+	PermissionWebhooksManage,
+	PermissionMediaManage,
+}
+
+func (e Permission) IsValid() bool {
+	switch e {
+	case PermissionArticlesWrite, PermissionCategoriesWrite, PermissionMapWrite, PermissionCommunityModerate, PermissionUsersView, PermissionUsersBan, PermissionRolesManage, PermissionAuditView, PermissionWebhooksManage, PermissionMediaManage:
+		return true
+	}
+	return false
+}
+
+func (e Permission) String() string {
+	return string(e)
+}
+
+func (e *Permission) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Permission(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Permission", str)
+	}
+	return nil
+}
+
+func (e Permission) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Permission) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Permission) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "SPAM"
+	ReportReasonHarassment     ReportReason = "HARASSMENT"
+	ReportReasonHateSpeech     ReportReason = "HATE_SPEECH"
+	ReportReasonNsfw           ReportReason = "NSFW"
+	ReportReasonMisinformation ReportReason = "MISINFORMATION"
+	ReportReasonOther          ReportReason = "OTHER"
+	ReportReasonAutomod        ReportReason = "AUTOMOD"
+)
+
+var AllReportReason = []ReportReason{
+	ReportReasonSpam,
+	ReportReasonHarassment,
+	ReportReasonHateSpeech,
+	ReportReasonNsfw,
+	ReportReasonMisinformation,
+	ReportReasonOther,
+	ReportReasonAutomod,
+}
+
+func (e ReportReason) IsValid() bool {
+	switch e {
+	case ReportReasonSpam, ReportReasonHarassment, ReportReasonHateSpeech, ReportReasonNsfw, ReportReasonMisinformation, ReportReasonOther, ReportReasonAutomod:
+		return true
+	}
+	return false
+}
+
+func (e ReportReason) String() string {
+	return string(e)
+}
+
+func (e *ReportReason) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportReason", str)
+	}
+	return nil
+}
+
+func (e ReportReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportReason) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportReason) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "OPEN"
+	ReportStatusResolved  ReportStatus = "RESOLVED"
+	ReportStatusDismissed ReportStatus = "DISMISSED"
+)
+
+var AllReportStatus = []ReportStatus{
+	ReportStatusOpen,
+	ReportStatusResolved,
+	ReportStatusDismissed,
+}
+
+func (e ReportStatus) IsValid() bool {
+	switch e {
+	case ReportStatusOpen, ReportStatusResolved, ReportStatusDismissed:
+		return true
+	}
+	return false
+}
+
+func (e ReportStatus) String() string {
+	return string(e)
+}
+
+func (e *ReportStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportStatus", str)
+	}
+	return nil
+}
+
+func (e ReportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportTargetType string
+
+const (
+	ReportTargetTypePost    ReportTargetType = "POST"
+	ReportTargetTypeComment ReportTargetType = "COMMENT"
+	ReportTargetTypeMessage ReportTargetType = "MESSAGE"
+)
+
+var AllReportTargetType = []ReportTargetType{
+	ReportTargetTypePost,
+	ReportTargetTypeComment,
+	ReportTargetTypeMessage,
+}
+
+func (e ReportTargetType) IsValid() bool {
+	switch e {
+	case ReportTargetTypePost, ReportTargetTypeComment, ReportTargetTypeMessage:
+		return true
+	}
+	return false
+}
+
+func (e ReportTargetType) String() string {
+	return string(e)
+}
+
+func (e *ReportTargetType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportTargetType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportTargetType", str)
+	}
+	return nil
+}
+
+func (e ReportTargetType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportTargetType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportTargetType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type Role string
+
+const (
+	RoleAdmin      Role = "ADMIN"
+	RoleUser       Role = "USER"
+	RoleEditor     Role = "EDITOR"
+	RoleMapManager Role = "MAP_MANAGER"
+	RoleModerator  Role = "MODERATOR"
+	RoleSuperadmin Role = "SUPERADMIN"
+)
+
+var AllRole = []Role{
+	RoleAdmin,
+	RoleUser,
+	RoleEditor,
+	RoleMapManager,
+	RoleModerator,
+	RoleSuperadmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdmin, RoleUser, RoleEditor, RoleMapManager, RoleModerator, RoleSuperadmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Role) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Role) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TimeWindow string
+
+const (
+	TimeWindowDay   TimeWindow = "DAY"
+	TimeWindowWeek  TimeWindow = "WEEK"
+	TimeWindowMonth TimeWindow = "MONTH"
+	TimeWindowAll   TimeWindow = "ALL"
+)
+
+var AllTimeWindow = []TimeWindow{
+	TimeWindowDay,
+	TimeWindowWeek,
+	TimeWindowMonth,
+	TimeWindowAll,
+}
+
+func (e TimeWindow) IsValid() bool {
+	switch e {
+	case TimeWindowDay, TimeWindowWeek, TimeWindowMonth, TimeWindowAll:
+		return true
+	}
+	return false
+}
+
+func (e TimeWindow) String() string {
+	return string(e)
+}
+
+func (e *TimeWindow) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TimeWindow(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TimeWindow", str)
+	}
+	return nil
+}
+
+func (e TimeWindow) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TimeWindow) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TimeWindow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TokenScope string
+
+const (
+	TokenScopeReadOnly       TokenScope = "READ_ONLY"
+	TokenScopeArticlesWrite  TokenScope = "ARTICLES_WRITE"
+	TokenScopeCommunityWrite TokenScope = "COMMUNITY_WRITE"
+)
+
+var AllTokenScope = []TokenScope{
+	TokenScopeReadOnly,
+	TokenScopeArticlesWrite,
+	TokenScopeCommunityWrite,
+}
+
+func (e TokenScope) IsValid() bool {
+	switch e {
+	case TokenScopeReadOnly, TokenScopeArticlesWrite, TokenScopeCommunityWrite:
+		return true
+	}
+	return false
+}
+
+func (e TokenScope) String() string {
+	return string(e)
+}
+
+func (e *TokenScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TokenScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TokenScope", str)
+	}
+	return nil
+}
+
+func (e TokenScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TokenScope) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TokenScope) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type VoteType string
+
+const (
+	VoteTypeUp   VoteType = "UP"
+	VoteTypeDown VoteType = "DOWN"
+	VoteTypeNone VoteType = "NONE"
+)
+
+var AllVoteType = []VoteType{
+	VoteTypeUp,
+	VoteTypeDown,
+	VoteTypeNone,
+}
+
+func (e VoteType) IsValid() bool {
+	switch e {
+	case VoteTypeUp, VoteTypeDown, VoteTypeNone:
+		return true
+	}
+	return false
+}
+
+func (e VoteType) String() string {
+	return string(e)
+}
+
+func (e *VoteType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = VoteType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid VoteType", str)
+	}
+	return nil
+}
+
+func (e VoteType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *VoteType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e VoteType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusSucceeded,
+	WebhookDeliveryStatusFailed,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusSucceeded, WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookDeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventArticlePublished WebhookEvent = "ARTICLE_PUBLISHED"
+	WebhookEventArticleUpdated   WebhookEvent = "ARTICLE_UPDATED"
+	WebhookEventArticleDeleted   WebhookEvent = "ARTICLE_DELETED"
+	WebhookEventPostCreated      WebhookEvent = "POST_CREATED"
+	WebhookEventCommentCreated   WebhookEvent = "COMMENT_CREATED"
+	WebhookEventMemberJoined     WebhookEvent = "MEMBER_JOINED"
+	WebhookEventPing             WebhookEvent = "PING"
+)
+
+var AllWebhookEvent = []WebhookEvent{
+	WebhookEventArticlePublished,
+	WebhookEventArticleUpdated,
+	WebhookEventArticleDeleted,
+	WebhookEventPostCreated,
+	WebhookEventCommentCreated,
+	WebhookEventMemberJoined,
+	WebhookEventPing,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventArticlePublished, WebhookEventArticleUpdated, WebhookEventArticleDeleted, WebhookEventPostCreated, WebhookEventCommentCreated, WebhookEventMemberJoined, WebhookEventPing:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEvent) String() string {
+	return string(e)
+}
+
+func (e *WebhookEvent) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEvent(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEvent", str)
+	}
+	return nil
+}
+
+func (e WebhookEvent) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookEvent) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookEvent) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
@@ -0,0 +1,125 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/notifications"
+)
+
+// MarkNotificationsRead is the resolver for the markNotificationsRead field.
+func (r *mutationResolver) MarkNotificationsRead(ctx context.Context, ids []string) (int32, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return 0, fmt.Errorf("not authenticated")
+	}
+	n, err := r.NotificationRepo.MarkRead(ctx, user.ID, ids)
+	if err != nil {
+		return 0, err
+	}
+	return int32(n), nil
+}
+
+// SetNotificationPreference is the resolver for the setNotificationPreference field.
+func (r *mutationResolver) SetNotificationPreference(ctx context.Context, typeArg model.NotificationType, enabled bool) ([]*model.NotificationPreference, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	t := notifications.Type(typeArg)
+	if !notifications.Valid(t) {
+		return nil, fmt.Errorf("invalid notification type %s", typeArg)
+	}
+	prefs, err := r.NotificationRepo.SetEnabled(ctx, user.ID, t, enabled)
+	if err != nil {
+		return nil, err
+	}
+	return notificationPreferencesToModel(prefs), nil
+}
+
+// Notifications is the resolver for the notifications field.
+func (r *queryResolver) Notifications(ctx context.Context, cursor *string, limit *int32, unreadOnly *bool) (*model.NotificationPage, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	l := 20
+	if limit != nil {
+		l = int(*limit)
+	}
+	if l <= 0 || l > maxNotificationPageSize {
+		l = maxNotificationPageSize
+	}
+	after := ""
+	if cursor != nil {
+		after = *cursor
+	}
+
+	list, err := r.NotificationRepo.List(ctx, user.ID, after, unreadOnly != nil && *unreadOnly, l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notifications: %w", err)
+	}
+
+	page := &model.NotificationPage{Notifications: make([]*model.Notification, 0, len(list))}
+	for _, n := range list {
+		page.Notifications = append(page.Notifications, r.notificationToModel(ctx, n))
+	}
+	if len(list) == l {
+		next := list[len(list)-1].ID
+		page.NextCursor = &next
+	}
+	return page, nil
+}
+
+// UnreadNotificationCount is the resolver for the unreadNotificationCount field.
+func (r *queryResolver) UnreadNotificationCount(ctx context.Context) (int32, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return 0, fmt.Errorf("not authenticated")
+	}
+	n, err := r.NotificationRepo.UnreadCount(ctx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+	return int32(n), nil
+}
+
+// NotificationPreferences is the resolver for the notificationPreferences field.
+func (r *queryResolver) NotificationPreferences(ctx context.Context) ([]*model.NotificationPreference, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	prefs, err := r.NotificationRepo.GetPreferences(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return notificationPreferencesToModel(prefs), nil
+}
+
+// NotificationAdded is the resolver for the notificationAdded field.
+func (r *subscriptionResolver) NotificationAdded(ctx context.Context) (<-chan *model.Notification, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	out := make(chan *model.Notification)
+	go func() {
+		defer close(out)
+		for n := range r.Notifier.Subscribe(ctx, user.ID) {
+			select {
+			case out <- r.notificationToModel(ctx, n):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
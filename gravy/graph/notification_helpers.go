@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/articles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/notifications"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+const maxNotificationPageSize = 50
+
+// notify sends a notification. Failures are only logged so they never undo
+// the action that caused them.
+func (r *Resolver) notify(ctx context.Context, n *notifications.Notification) {
+	if _, err := r.Notifier.Notify(ctx, n); err != nil {
+		log.Printf("Failed to send %s notification to %s: %v", n.Type, n.UserID, err)
+	}
+}
+
+func displayName(u *users.User) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Username != "" {
+		return u.Username
+	}
+	return u.Name
+}
+
+// notifyReply tells the author of the parent comment, or of the post for top
+// level comments, about a new comment.
+func (r *Resolver) notifyReply(ctx context.Context, actor *users.User, post *community.Post, comment *community.Comment) {
+	n := &notifications.Notification{
+		Type:      notifications.TypeReply,
+		ActorID:   actor.ID,
+		GroupID:   post.GroupID,
+		PostID:    post.ID,
+		CommentID: comment.ID,
+		UserID:    post.AuthorID,
+		Message:   fmt.Sprintf("%s commented on your post %q", displayName(actor), post.Title),
+	}
+	if comment.ParentID != nil {
+		parent, err := r.CommunityRepo.GetComment(ctx, *comment.ParentID)
+		if err != nil {
+			return
+		}
+		n.UserID = parent.AuthorID
+		n.Message = fmt.Sprintf("%s replied to your comment on %q", displayName(actor), post.Title)
+	}
+	r.notify(ctx, n)
+}
+
+// notifyJoinRequest tells everyone who can accept it about a join request.
+func (r *Resolver) notifyJoinRequest(ctx context.Context, actor *users.User, group *community.Group) {
+	recipients := append([]string{group.OwnerID}, group.ModeratorIDs...)
+	for _, id := range recipients {
+		r.notify(ctx, &notifications.Notification{
+			UserID:  id,
+			Type:    notifications.TypeJoinRequest,
+			ActorID: actor.ID,
+			GroupID: group.ID,
+			Message: fmt.Sprintf("%s asked to join %s", displayName(actor), group.Name),
+		})
+	}
+}
+
+func (r *Resolver) notifyRequestAccepted(ctx context.Context, actor *users.User, group *community.Group, userID string) {
+	r.notify(ctx, &notifications.Notification{
+		UserID:  userID,
+		Type:    notifications.TypeRequestAccepted,
+		ActorID: actor.ID,
+		GroupID: group.ID,
+		Message: fmt.Sprintf("Your request to join %s was accepted", group.Name),
+	})
+}
+
+// notifyPostRemoved tells an author that a moderator removed their post.
+func (r *Resolver) notifyPostRemoved(ctx context.Context, actor *users.User, post *community.Post) {
+	r.notify(ctx, &notifications.Notification{
+		UserID:  post.AuthorID,
+		Type:    notifications.TypePostRemoved,
+		ActorID: actor.ID,
+		GroupID: post.GroupID,
+		Message: fmt.Sprintf("Your post %q was removed by a moderator", post.Title),
+	})
+}
+
+// notifyArticleUpdated tells an author that someone else edited their
+// article.
+func (r *Resolver) notifyArticleUpdated(ctx context.Context, actor *users.User, article *articles.Article) {
+	r.notify(ctx, &notifications.Notification{
+		UserID:    article.AuthorID,
+		Type:      notifications.TypeArticleUpdated,
+		ActorID:   actor.ID,
+		ArticleID: article.ID,
+		Message:   fmt.Sprintf("%s updated your article %q", displayName(actor), article.Title),
+	})
+}
+
+func (r *Resolver) notificationToModel(ctx context.Context, n *notifications.Notification) *model.Notification {
+	var actor *users.PublicUser
+	if n.ActorID != "" {
+		if u, err := r.UserRepo.GetByID(ctx, n.ActorID); err == nil {
+			actor = mapUserToPublic(u)
+		}
+	}
+	return mapNotificationToModel(n, actor)
+}
+
+func notificationPreferencesToModel(prefs *notifications.Preferences) []*model.NotificationPreference {
+	res := make([]*model.NotificationPreference, 0, len(notifications.Types))
+	for _, t := range notifications.Types {
+		res = append(res, &model.NotificationPreference{
+			Type:    model.NotificationType(t),
+			Enabled: prefs.Enabled(t),
+		})
+	}
+	return res
+}
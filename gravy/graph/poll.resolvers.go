@@ -0,0 +1,65 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+)
+
+// VotePoll is the resolver for the votePoll field.
+func (r *mutationResolver) VotePoll(ctx context.Context, postID string, choices []int32) (*model.Poll, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	poll, err := r.pollForVote(ctx, user, postID)
+	if err != nil {
+		return nil, err
+	}
+	picked := make([]int, 0, len(choices))
+	for _, c := range choices {
+		picked = append(picked, int(c))
+	}
+	picked, err = poll.CheckChoices(picked)
+	if err != nil {
+		return nil, err
+	}
+	poll, err = r.PollRepo.Vote(ctx, poll, user.ID, picked)
+	if err != nil {
+		return nil, err
+	}
+	return r.pollToModel(ctx, poll, user), nil
+}
+
+// RetractPollVote is the resolver for the retractPollVote field.
+func (r *mutationResolver) RetractPollVote(ctx context.Context, postID string) (*model.Poll, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	poll, err := r.pollForVote(ctx, user, postID)
+	if err != nil {
+		return nil, err
+	}
+	poll, err = r.PollRepo.Retract(ctx, poll, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.pollToModel(ctx, poll, user), nil
+}
+
+// Poll is the resolver for the poll field.
+func (r *postResolver) Poll(ctx context.Context, obj *model.Post) (*model.Poll, error) {
+	poll, err := r.PollRepo.GetByPost(ctx, obj.ID)
+	if err != nil || poll == nil {
+		return nil, err
+	}
+	return r.pollToModel(ctx, poll, auth.ForContext(ctx)), nil
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/polls"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+// maxPollVoters caps how many voters are listed per option.
+const maxPollVoters = 10
+
+func newPollFromInput(input *model.NewPoll) (*polls.Poll, error) {
+	var closesAt *time.Time
+	if input.ClosesAt != nil {
+		t, err := time.Parse(time.RFC3339, *input.ClosesAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll closing time: %w", err)
+		}
+		closesAt = &t
+	}
+	return polls.New(input.Options,
+		input.MultipleChoice != nil && *input.MultipleChoice,
+		input.Anonymous != nil && *input.Anonymous,
+		closesAt, time.Now())
+}
+
+// pollForVote loads the poll of a post the user wants to vote in, checking
+// that they can take part in the post's group and the poll is still open.
+func (r *Resolver) pollForVote(ctx context.Context, user *users.User, postID string) (*polls.Poll, error) {
+	post, err := r.CommunityRepo.GetPost(ctx, postID)
+	if err != nil || post.Held {
+		return nil, fmt.Errorf("post not found")
+	}
+	group, err := r.CommunityRepo.GetGroupByID(ctx, post.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("group not found")
+	}
+	if !r.canViewGroup(ctx, user, group) {
+		return nil, fmt.Errorf("access denied")
+	}
+	if err := r.checkCanParticipate(ctx, user, group.ID); err != nil {
+		return nil, err
+	}
+	if post.Locked {
+		return nil, fmt.Errorf("post is locked")
+	}
+	poll, err := r.PollRepo.GetByPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load poll: %w", err)
+	}
+	if poll == nil {
+		return nil, fmt.Errorf("post has no poll")
+	}
+	if poll.Closed(time.Now()) {
+		return nil, fmt.Errorf("poll is closed")
+	}
+	return poll, nil
+}
+
+// pollToModel shows results only to users who voted, or to everyone once the
+// poll closed, so early results don't sway the vote. Retracting doesn't hide
+// them again, so voters can't peek and then vote afresh.
+func (r *Resolver) pollToModel(ctx context.Context, poll *polls.Poll, user *users.User) *model.Poll {
+	closed := poll.Closed(time.Now())
+	m := &model.Poll{
+		ID:             poll.ID,
+		Options:        make([]*model.PollOption, 0, len(poll.Options)),
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		Closed:         closed,
+	}
+	if poll.ClosesAt != nil {
+		closesAt := poll.ClosesAt.Format(time.RFC3339)
+		m.ClosesAt = &closesAt
+	}
+	voted := false
+	if user != nil {
+		vote, err := r.PollRepo.GetVote(ctx, poll.ID, user.ID)
+		if err != nil {
+			log.Printf("Failed to load poll vote of user %s: %v", user.ID, err)
+		} else if vote != nil {
+			voted = true
+			if !vote.Retracted {
+				m.MyChoice = make([]int32, 0, len(vote.Choices))
+				for _, c := range vote.Choices {
+					m.MyChoice = append(m.MyChoice, int32(c))
+				}
+			}
+		}
+	}
+	m.ResultsVisible = closed || voted
+
+	if m.ResultsVisible {
+		total := int32(poll.Voters)
+		m.TotalVoters = &total
+	}
+	for i, o := range poll.Options {
+		option := &model.PollOption{Index: int32(i), Text: o.Text}
+		if m.ResultsVisible {
+			count := int32(o.Count)
+			option.Count = &count
+			if !poll.Anonymous {
+				option.Voters = r.pollVoters(ctx, poll.ID, i)
+			}
+		}
+		m.Options = append(m.Options, option)
+	}
+	return m
+}
+
+func (r *Resolver) pollVoters(ctx context.Context, pollID string, option int) []*model.PublicUser {
+	ids, err := r.PollRepo.ListVoters(ctx, pollID, option, maxPollVoters)
+	if err != nil {
+		log.Printf("Failed to load voters of poll %s: %v", pollID, err)
+		return nil
+	}
+	voters := make([]*model.PublicUser, 0, len(ids))
+	for _, id := range ids {
+		u, err := r.UserRepo.GetByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		voters = append(voters, mapPublicUserToModel(mapUserToPublic(u)))
+	}
+	return voters
+}
+
+// deletePoll removes a deleted post's poll and its votes.
+func (r *Resolver) deletePoll(ctx context.Context, postID string) {
+	if err := r.PollRepo.DeleteForPost(ctx, postID); err != nil {
+		log.Printf("Failed to delete poll of post %s: %v", postID, err)
+	}
+}
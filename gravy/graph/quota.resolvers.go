@@ -0,0 +1,50 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/quota"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+)
+
+// StorageUsage is the resolver for the storageUsage field.
+func (r *userResolver) StorageUsage(ctx context.Context, obj *model.User) (*model.StorageUsage, error) {
+	viewer := auth.ForContext(ctx)
+	if viewer == nil {
+		return nil, nil
+	}
+	user := viewer
+	if obj.ID != viewer.ID {
+		if auth.RequirePermission(ctx, viewer, roles.UsersView) != nil {
+			return nil, nil
+		}
+		u, err := r.UserRepo.GetByID(ctx, obj.ID)
+		if err != nil {
+			return nil, err
+		}
+		user = u
+	}
+
+	now := time.Now()
+	usage, err := r.QuotaRepo.Get(ctx, user.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load storage usage: %w", err)
+	}
+	limits := quota.For(auth.UserRoles(user))
+	return &model.StorageUsage{
+		UsedBytes:        int(usage.StorageBytes),
+		LimitBytes:       int(limits.StorageBytes),
+		UploadsToday:     int32(usage.DayUploads),
+		DailyUploadLimit: int32(limits.DailyUploads),
+		ResetsAt:         quota.ResetsAt(now).Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/quota"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+// quotaExceededCode is the error code clients can check for in extensions.
+const quotaExceededCode = "QUOTA_EXCEEDED"
+
+// reserveUpload counts an upload of size bytes against the user's quota. The
+// size is what was received; settleUpload corrects it once the stored size is
+// known.
+func (r *Resolver) reserveUpload(ctx context.Context, user *users.User, size int64) error {
+	err := r.QuotaRepo.Reserve(ctx, user.ID, quota.For(auth.UserRoles(user)), size, time.Now())
+	var exceeded *quota.ExceededError
+	if errors.As(err, &exceeded) {
+		gqlErr := gqlerror.WrapPath(graphql.GetPath(ctx), err)
+		gqlErr.Extensions = map[string]interface{}{
+			"code":  quotaExceededCode,
+			"quota": exceeded.Kind,
+			"limit": exceeded.Limit,
+		}
+		return gqlErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check upload quota: %w", err)
+	}
+	return nil
+}
+
+// settleUpload replaces the reserved size with the stored one. Pass a nil
+// uploadErr and stored 0 when nothing new was stored, e.g. for a duplicate.
+// When the upload failed the reservation is given back entirely.
+func (r *Resolver) settleUpload(ctx context.Context, user *users.User, reserved, stored int64, uploadErr error) {
+	var err error
+	if uploadErr != nil {
+		err = r.QuotaRepo.Cancel(ctx, user.ID, reserved, time.Now())
+	} else {
+		err = r.QuotaRepo.AddStorage(ctx, user.ID, stored-reserved)
+	}
+	if err != nil {
+		log.Printf("Failed to update storage usage of user %s: %v", user.ID, err)
+	}
+}
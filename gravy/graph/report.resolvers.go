@@ -0,0 +1,184 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/reports"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/sanitization"
+)
+
+// ReportContent is the resolver for the reportContent field.
+func (r *mutationResolver) ReportContent(ctx context.Context, targetType model.ReportTargetType, targetID string, reason model.ReportReason, note *string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+
+	entry := reports.Entry{
+		ReporterID: user.ID,
+		Reason:     reports.Reason(reason),
+		CreatedAt:  time.Now(),
+	}
+	if note != nil {
+		entry.Note = sanitization.SanitizeString(strings.TrimSpace(*note))
+		if len(entry.Note) > maxReportNoteLength {
+			return false, fmt.Errorf("note must be at most %d characters", maxReportNoteLength)
+		}
+	}
+	if entry.Reason == reports.ReasonAutomod {
+		return false, fmt.Errorf("invalid reason %s", reason)
+	}
+	if entry.Reason == reports.ReasonOther && entry.Note == "" {
+		return false, fmt.Errorf("please describe the problem")
+	}
+
+	target, err := r.reportTarget(ctx, user, reports.TargetType(targetType), targetID)
+	if err != nil {
+		return false, err
+	}
+	if target.AuthorID == user.ID {
+		return false, fmt.Errorf("you can't report your own content")
+	}
+
+	if _, err := r.ReportRepo.Add(ctx, target, entry); err != nil {
+		if err == reports.ErrAlreadyReported {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to submit report: %w", err)
+	}
+	return true, nil
+}
+
+// ResolveReport is the resolver for the resolveReport field.
+func (r *mutationResolver) ResolveReport(ctx context.Context, id string, action model.ModerationAction, note *string, banDurationHours *int32) (*model.ReportCase, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	c, err := r.ReportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("report not found")
+	}
+	if !r.canModerateGroup(ctx, user, c.GroupID) {
+		return nil, fmt.Errorf("access denied")
+	}
+	if c.Status != reports.StatusOpen {
+		return nil, reports.ErrNotOpen
+	}
+
+	text := ""
+	if note != nil {
+		text = strings.TrimSpace(*note)
+	}
+
+	status := reports.StatusResolved
+	switch reports.Action(action) {
+	case reports.ActionDismiss:
+		status = reports.StatusDismissed
+		if err := r.releaseHeldContent(ctx, c); err != nil {
+			return nil, fmt.Errorf("failed to release content: %w", err)
+		}
+	case reports.ActionRemoveContent:
+		if err := r.removeReportedContent(ctx, c); err != nil {
+			return nil, fmt.Errorf("failed to remove content: %w", err)
+		}
+	case reports.ActionWarnUser:
+		warning := &reports.Warning{
+			UserID:      c.TargetAuthorID,
+			ModeratorID: user.ID,
+			CaseID:      c.ID,
+			Note:        text,
+		}
+		if err := r.ReportRepo.AddWarning(ctx, warning); err != nil {
+			return nil, fmt.Errorf("failed to warn user: %w", err)
+		}
+		r.sendWarningEmail(ctx, c.TargetAuthorID, text)
+	case reports.ActionBanUser:
+		// Bans are site-wide, so group owners can't issue them.
+		if err := auth.RequirePermission(ctx, user, roles.UsersBan); err != nil {
+			return nil, err
+		}
+		reason := text
+		if reason == "" {
+			reason = fmt.Sprintf("Reported %s %s", strings.ToLower(string(c.TargetType)), c.TargetID)
+		}
+		if _, err := r.Mutation().BlockUser(ctx, c.TargetAuthorID, &reason, banDurationHours); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid action %s", action)
+	}
+
+	resolved, err := r.ReportRepo.Resolve(ctx, c.ID, status, reports.Resolution{
+		Action:      reports.Action(action),
+		ModeratorID: user.ID,
+		Note:        text,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.recordAudit(ctx, audit.ActionReportResolve, audit.TargetReport, c.ID, nil, resolved.Resolution)
+	return r.reportCaseToModel(ctx, resolved), nil
+}
+
+// ModerationQueue is the resolver for the moderationQueue field.
+func (r *queryResolver) ModerationQueue(ctx context.Context, status *model.ReportStatus, groupID *string, limit *int32, offset *int32) ([]*model.ReportCase, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	l := 20
+	o := 0
+	if limit != nil {
+		l = int(*limit)
+	}
+	if offset != nil {
+		o = int(*offset)
+	}
+
+	filter := reports.QueueFilter{Status: reports.StatusOpen}
+	if status != nil {
+		filter.Status = reports.Status(*status)
+	}
+
+	switch {
+	case groupID != nil:
+		if !r.canModerateGroup(ctx, user, *groupID) {
+			return nil, fmt.Errorf("access denied")
+		}
+		filter.GroupIDs = []string{*groupID}
+	case auth.RequirePermission(ctx, user, roles.CommunityModerate) != nil:
+		managed, err := r.managedGroupIDs(ctx, user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load groups: %w", err)
+		}
+		if len(managed) == 0 {
+			return []*model.ReportCase{}, nil
+		}
+		filter.GroupIDs = managed
+	}
+
+	cases, err := r.ReportRepo.ListQueue(ctx, filter, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load moderation queue: %w", err)
+	}
+	result := make([]*model.ReportCase, 0, len(cases))
+	for _, c := range cases {
+		result = append(result, r.reportCaseToModel(ctx, c))
+	}
+	return result, nil
+}
@@ -0,0 +1,191 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/attachments"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mailer"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mentions"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/reports"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+const maxReportNoteLength = 1000
+
+// canModerateGroup reports whether user may act on content in the group:
+// site moderators everywhere, group owners and moderators in their groups.
+// Admins must pass the same second-factor check as @hasPermission.
+func (r *Resolver) canModerateGroup(ctx context.Context, user *users.User, groupID string) bool {
+	if auth.RequirePermission(ctx, user, roles.CommunityModerate) == nil {
+		return true
+	}
+	group, err := r.CommunityRepo.GetGroupByID(ctx, groupID)
+	return err == nil && group.Can(user.ID, community.GroupModerateContent)
+}
+
+// canViewGroup mirrors the visibility rules of private groups.
+func (r *Resolver) canViewGroup(ctx context.Context, user *users.User, group *community.Group) bool {
+	if group.Type != community.GroupTypePrivate || group.OwnerID == user.ID {
+		return true
+	}
+	isMember, _ := r.CommunityRepo.IsMember(ctx, group.ID, user.ID)
+	return isMember
+}
+
+// messageGroup finds the group a chat message was posted in.
+func (r *Resolver) messageGroup(ctx context.Context, message *community.Message) (*community.Group, error) {
+	channel, err := r.CommunityRepo.GetChannel(ctx, message.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	discussion, err := r.CommunityRepo.GetDiscussion(ctx, channel.DiscussionID)
+	if err != nil {
+		return nil, err
+	}
+	return r.CommunityRepo.GetGroupByID(ctx, discussion.GroupID)
+}
+
+// reportTarget looks up reported content the user is allowed to see.
+func (r *Resolver) reportTarget(ctx context.Context, user *users.User, targetType reports.TargetType, id string) (reports.Target, error) {
+	target := reports.Target{Type: targetType, ID: id}
+	notFound := fmt.Errorf("content not found")
+
+	var group *community.Group
+	switch targetType {
+	case reports.TargetPost:
+		post, err := r.CommunityRepo.GetPost(ctx, id)
+		if err != nil {
+			return target, notFound
+		}
+		target.AuthorID = post.AuthorID
+		group, err = r.CommunityRepo.GetGroupByID(ctx, post.GroupID)
+		if err != nil {
+			return target, notFound
+		}
+	case reports.TargetComment:
+		comment, err := r.CommunityRepo.GetComment(ctx, id)
+		if err != nil {
+			return target, notFound
+		}
+		target.AuthorID = comment.AuthorID
+		post, err := r.CommunityRepo.GetPost(ctx, comment.PostID)
+		if err != nil {
+			return target, notFound
+		}
+		group, err = r.CommunityRepo.GetGroupByID(ctx, post.GroupID)
+		if err != nil {
+			return target, notFound
+		}
+	case reports.TargetMessage:
+		message, err := r.CommunityRepo.GetMessage(ctx, id)
+		if err != nil {
+			return target, notFound
+		}
+		target.AuthorID = message.SenderID
+		group, err = r.messageGroup(ctx, message)
+		if err != nil {
+			return target, notFound
+		}
+		// Chats are only visible to members, even in public groups.
+		isMember, _ := r.CommunityRepo.IsMember(ctx, group.ID, user.ID)
+		if !isMember && group.OwnerID != user.ID {
+			return target, notFound
+		}
+	default:
+		return target, fmt.Errorf("invalid target type %s", targetType)
+	}
+
+	if !r.canViewGroup(ctx, user, group) {
+		return target, notFound
+	}
+	target.GroupID = group.ID
+	return target, nil
+}
+
+// reportContent returns the current text of a case's target, or nil once it
+// has been removed.
+func (r *Resolver) reportContent(ctx context.Context, c *reports.Case) *string {
+	var content string
+	switch c.TargetType {
+	case reports.TargetPost:
+		post, err := r.CommunityRepo.GetPost(ctx, c.TargetID)
+		if err != nil {
+			return nil
+		}
+		content = post.Title + "\n\n" + post.Content
+	case reports.TargetComment:
+		comment, err := r.CommunityRepo.GetComment(ctx, c.TargetID)
+		if err != nil {
+			return nil
+		}
+		content = comment.Content
+	case reports.TargetMessage:
+		message, err := r.CommunityRepo.GetMessage(ctx, c.TargetID)
+		if err != nil {
+			return nil
+		}
+		content = message.Content
+	default:
+		return nil
+	}
+	return &content
+}
+
+// removeReportedContent deletes the target through the regular delete paths
+// so counters, search and the audit log stay consistent.
+func (r *Resolver) removeReportedContent(ctx context.Context, c *reports.Case) error {
+	switch c.TargetType {
+	case reports.TargetPost:
+		_, err := r.Mutation().DeletePost(ctx, c.TargetID)
+		return err
+	case reports.TargetComment:
+		_, err := r.Mutation().DeleteComment(ctx, c.TargetID)
+		return err
+	case reports.TargetMessage:
+		message, err := r.CommunityRepo.GetMessage(ctx, c.TargetID)
+		if err != nil {
+			return err
+		}
+		if err := r.CommunityRepo.DeleteMessage(ctx, c.TargetID); err != nil {
+			return err
+		}
+		r.deleteMentions(ctx, mentions.SourceMessage, c.TargetID)
+		r.detachAttachments(ctx, attachments.SourceMessage, c.TargetID)
+		r.trackMedia(ctx, media.RefMessage, c.TargetID)
+		r.recordAudit(ctx, audit.ActionMessageDelete, audit.TargetMessage, c.TargetID, message, nil)
+		return nil
+	}
+	return fmt.Errorf("invalid target type %s", c.TargetType)
+}
+
+func (r *Resolver) sendWarningEmail(ctx context.Context, userID, note string) {
+	user, err := r.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return
+	}
+	text := fmt.Sprintf("Hi %s,\n\nA moderator reviewed a report about something you posted on WikiNITT and issued a warning.", user.DisplayName)
+	if note != "" {
+		text += "\n\nModerator note: " + note
+	}
+	text += "\n\nRepeated violations can lead to a ban.\n"
+	msg := mailer.Message{To: user.Email, Subject: "A warning about your WikiNITT content", Text: text}
+	if err := r.Mailer.Send(ctx, msg); err != nil {
+		log.Printf("Failed to send warning email to user %s: %v", userID, err)
+	}
+}
+
+func (r *Resolver) reportCaseToModel(ctx context.Context, c *reports.Case) *model.ReportCase {
+	var author *users.PublicUser
+	if u, err := r.UserRepo.GetByID(ctx, c.TargetAuthorID); err == nil {
+		author = mapUserToPublic(u)
+	}
+	return mapReportCaseToModel(c, author, r.reportContent(ctx, c))
+}
@@ -1,23 +1,67 @@
 package graph
 
 import (
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/apitokens"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/articles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/attachments"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/automod"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/bans"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/categories"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/digest"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/karma"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mailer"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/maplocation"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mentions"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/notifications"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/polls"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/quota"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/rag"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/reports"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/search"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/uploader"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/webhooks"
 )
 
 type Resolver struct {
-	UserRepo        users.Repository
-	ArticleRepo     articles.Repository
-	CategoryRepo    categories.Repository
-	CommunityRepo   community.Repository
-	Uploader        uploader.Uploader
-	SearchClient    *search.Client
-	MapLocationRepo maplocation.Repository
-	RagClient       rag.Client
+	UserRepo         users.Repository
+	ArticleRepo      articles.Repository
+	CategoryRepo     categories.Repository
+	CommunityRepo    community.Repository
+	Uploader         uploader.Uploader
+	SearchClient     *search.Client
+	MapLocationRepo  maplocation.Repository
+	RagClient        rag.Client
+	Mailer           mailer.Mailer
+	APITokenRepo     apitokens.Repository
+	BanRepo          bans.Repository
+	AuditRepo        audit.Repository
+	ReportRepo       reports.Repository
+	AutomodRepo      automod.Repository
+	Automod          *automod.Engine
+	KarmaRepo        karma.Repository
+	NotificationRepo notifications.Repository
+	Notifier         *notifications.Notifier
+	MentionRepo      mentions.Repository
+	DigestRepo       digest.Repository
+	WebhookRepo      webhooks.Repository
+	Webhooks         *webhooks.Dispatcher
+	PollRepo         polls.Repository
+	AttachmentRepo   attachments.Repository
+	MediaRepo        media.Repository
+	QuotaRepo        quota.Repository
 }
+
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+
+	defaultAPITokenDays = 90
+	maxAPITokenDays     = 365
+	maxAPITokensPerUser = 20
+)
@@ -16,38 +16,43 @@ import (
 )
 
 // SearchArticles is the resolver for the searchArticles field.
-func (r *queryResolver) SearchArticles(ctx context.Context, query string, limit *int32, offset *int32) ([]*model.Article, error) {
-	l := 10
-	if limit != nil {
-		l = int(*limit)
+func (r *queryResolver) SearchArticles(ctx context.Context, query string, limit *int32, offset *int32, filter *model.ArticleSearchFilter, sort *model.ArticleSort) ([]*model.Article, error) {
+	res, byID, err := r.searchArticles(ctx, query, filter, sort, limit, offset)
+	if err != nil {
+		return nil, err
 	}
-	o := 0
-	if offset != nil {
-		o = int(*offset)
+
+	result := []*model.Article{}
+	for _, h := range res.Hits {
+		if a, ok := byID[h.ID]; ok {
+			result = append(result, a)
+		}
 	}
+	return result, nil
+}
 
-	ids, err := r.SearchClient.SearchArticles(ctx, query, l, o)
+// ArticleSearch is the resolver for the articleSearch field.
+func (r *queryResolver) ArticleSearch(ctx context.Context, query string, filter *model.ArticleSearchFilter, sort *model.ArticleSort, limit *int32, offset *int32) (*model.SearchResult, error) {
+	res, byID, err := r.searchArticles(ctx, query, filter, sort, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+		return nil, err
 	}
 
-	articles, err := r.ArticleRepo.GetByIDs(ctx, ids)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch articles: %w", err)
-	}
-
-	var result []*model.Article
-	for _, a := range articles {
-		author, err := r.UserRepo.GetByID(ctx, a.AuthorID)
-		if err == nil {
-			a.Author = &users.PublicUser{
-				ID:     author.ID,
-				Name:   author.Name,
-				Gender: author.Gender,
-				Avatar: author.Avatar,
-			}
+	result := &model.SearchResult{
+		Hits:  []*model.ArticleSearchHit{},
+		Total: int32(res.Total),
+		Facets: &model.ArticleFacets{
+			Categories: mapFacetCounts(res.Categories),
+			Authors:    mapFacetCounts(res.Authors),
+			Featured:   mapFacetCounts(res.Featured),
+		},
+	}
+	for _, h := range res.Hits {
+		a, ok := byID[h.ID]
+		if !ok {
+			continue
 		}
-		result = append(result, mapArticleToModel(a))
+		result.Hits = append(result.Hits, &model.ArticleSearchHit{Article: a, Title: h.Title, Snippet: h.Snippet})
 	}
 	return result, nil
 }
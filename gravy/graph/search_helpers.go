@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/search"
+)
+
+const maxSearchPageSize = 50
+
+// searchArticles runs an article search and loads the matching articles,
+// keyed by ID. Hits whose article has since been deleted have no entry.
+func (r *Resolver) searchArticles(ctx context.Context, query string, filter *model.ArticleSearchFilter, sort *model.ArticleSort, limit, offset *int32) (*search.ArticleResult, map[string]*model.Article, error) {
+	q := search.ArticleQuery{Query: query, Limit: 10, Sort: search.ArticleSortRelevance}
+	if limit != nil {
+		q.Limit = int(*limit)
+	}
+	if q.Limit <= 0 || q.Limit > maxSearchPageSize {
+		q.Limit = maxSearchPageSize
+	}
+	if offset != nil && *offset > 0 {
+		q.Offset = int(*offset)
+	}
+	if sort != nil {
+		q.Sort = search.ArticleSort(*sort)
+	}
+	if filter != nil {
+		q.Filter.Categories = filter.Categories
+		q.Filter.Featured = filter.Featured
+		if filter.AuthorID != nil {
+			q.Filter.AuthorID = *filter.AuthorID
+		}
+		for _, d := range []struct {
+			in  *string
+			out **time.Time
+		}{{filter.Since, &q.Filter.Since}, {filter.Until, &q.Filter.Until}} {
+			if d.in == nil {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, *d.in)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid date %q, expected RFC3339", *d.in)
+			}
+			*d.out = &t
+		}
+	}
+
+	res, err := r.SearchClient.SearchArticles(ctx, q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	ids := make([]string, len(res.Hits))
+	for i, h := range res.Hits {
+		ids[i] = h.ID
+	}
+	found, err := r.ArticleRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch articles: %w", err)
+	}
+
+	byID := make(map[string]*model.Article, len(found))
+	for _, a := range found {
+		author, err := r.UserRepo.GetByID(ctx, a.AuthorID)
+		if err == nil {
+			a.Author = mapUserToPublic(author)
+		}
+		byID[a.ID] = mapArticleToModel(a)
+	}
+	return res, byID, nil
+}
+
+func mapFacetCounts(counts []search.FacetCount) []*model.FacetCount {
+	out := make([]*model.FacetCount, len(counts))
+	for i, c := range counts {
+		out[i] = &model.FacetCount{Value: c.Value, Count: int32(c.Count)}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+const twoFactorIssuer = "WikiNITT"
+
+// verifySecondFactor accepts either a current TOTP code or one of the user's
+// unused recovery codes. Both are single use.
+func (r *Resolver) verifySecondFactor(ctx context.Context, user *users.User, code string) (bool, error) {
+	if step, ok := auth.ValidateTOTP(user.TwoFactorSecret, code, time.Now()); ok {
+		return r.UserRepo.UseTOTPStep(ctx, user.ID, step)
+	}
+	return r.UserRepo.ConsumeRecoveryCode(ctx, user.ID, auth.HashRecoveryCode(code))
+}
+
+// sessionToken issues a token that keeps the second-factor status of the
+// current session.
+func sessionToken(ctx context.Context, userID string) (string, error) {
+	if auth.TwoFactorVerified(ctx) {
+		return auth.GenerateTwoFactorToken(userID)
+	}
+	return auth.GenerateToken(userID)
+}
+
+// signInToken issues the token for a user who has proven their identity by
+// password or OAuth. Users with 2FA enabled must also pass a valid code, and
+// then get a token that counts as second-factor verified.
+func (r *Resolver) signInToken(ctx context.Context, user *users.User, code *string) (string, error) {
+	if !user.TwoFactorEnabled {
+		return auth.GenerateToken(user.ID)
+	}
+
+	if code == nil || *code == "" {
+		return "", fmt.Errorf("two-factor code required")
+	}
+	ok, err := r.verifySecondFactor(ctx, user, *code)
+	if err != nil {
+		return "", fmt.Errorf("failed to verify two-factor code: %w", err)
+	}
+	if !ok {
+		return "", fmt.Errorf("invalid two-factor code")
+	}
+	return auth.GenerateTwoFactorToken(user.ID)
+}
@@ -10,14 +10,20 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/apitokens"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/bans"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/sanitization"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // SignIn is the resolver for the signIn field.
@@ -29,7 +35,7 @@ func (r *mutationResolver) SignIn(ctx context.Context, input model.NewUser) (str
 	// 1. Try to find by OAuth ID (Unified)
 	existingUser, err := r.UserRepo.GetByOAuthID(ctx, input.ID)
 	if err == nil && existingUser != nil {
-		return auth.GenerateToken(existingUser.ID)
+		return r.signInToken(ctx, existingUser, input.TwoFactorCode)
 	}
 
 	// 2. Try to find by Email
@@ -39,7 +45,7 @@ func (r *mutationResolver) SignIn(ctx context.Context, input model.NewUser) (str
 		if existingUser.OAuthID == "" {
 			_, _ = r.UserRepo.Update(ctx, existingUser.ID, map[string]interface{}{"oauthId": input.ID})
 		}
-		return auth.GenerateToken(existingUser.ID)
+		return r.signInToken(ctx, existingUser, input.TwoFactorCode)
 	}
 
 	// 3. Create New User
@@ -67,6 +73,7 @@ func (r *mutationResolver) SignIn(ctx context.Context, input model.NewUser) (str
 		IsAdmin:       false,
 		IsBanned:      false,
 		SetupComplete: false,
+		EmailVerified: true, // Verified by the OAuth provider
 		Avatar:        avatarURL,
 	}
 
@@ -89,12 +96,11 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (s
 		return "", fmt.Errorf("invalid credentials")
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password))
-	if err != nil {
+	if !auth.CheckPassword(user.PasswordHash, input.Password) {
 		return "", fmt.Errorf("invalid credentials")
 	}
 
-	return auth.GenerateToken(user.ID)
+	return r.signInToken(ctx, user, input.TwoFactorCode)
 }
 
 // CompleteSetup is the resolver for the completeSetup field.
@@ -128,23 +134,206 @@ func (r *mutationResolver) CompleteSetup(ctx context.Context, input model.Comple
 }
 
 // BlockUser is the resolver for the blockUser field.
-func (r *mutationResolver) BlockUser(ctx context.Context, id string) (bool, error) {
-	err := r.UserRepo.Block(ctx, id)
+func (r *mutationResolver) BlockUser(ctx context.Context, id string, reason *string, durationHours *int32) (bool, error) {
+	moderator := auth.ForContext(ctx)
+	if moderator == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+	if moderator.ID == id {
+		return false, fmt.Errorf("you can't ban yourself")
+	}
+
+	target, err := r.UserRepo.GetByID(ctx, id)
 	if err != nil {
+		return false, fmt.Errorf("user not found")
+	}
+	if auth.IsAdmin(target) && !auth.HasRole(moderator, roles.SuperAdmin) {
+		return false, fmt.Errorf("access denied: only superadmins can ban admins")
+	}
+
+	now := time.Now()
+	ban := &bans.Ban{
+		UserID:      id,
+		ModeratorID: moderator.ID,
+		StartsAt:    now,
+	}
+	if reason != nil {
+		ban.Reason = strings.TrimSpace(*reason)
+	}
+	if durationHours != nil {
+		if *durationHours <= 0 {
+			return false, fmt.Errorf("ban duration must be positive")
+		}
+		endsAt := now.Add(time.Duration(*durationHours) * time.Hour)
+		ban.EndsAt = &endsAt
+	}
+
+	// A new ban replaces any outstanding one.
+	if err := r.BanRepo.Lift(ctx, id, moderator.ID); err != nil {
+		return false, fmt.Errorf("failed to replace existing ban: %w", err)
+	}
+	if err := r.BanRepo.Create(ctx, ban); err != nil {
+		return false, fmt.Errorf("failed to record ban: %w", err)
+	}
+	if err := r.UserRepo.Block(ctx, id); err != nil {
 		return false, err
 	}
+	r.recordAudit(ctx, audit.ActionUserBan, audit.TargetUser, id, userSnapshot(target), ban)
 	return true, nil
 }
 
 // UnblockUser is the resolver for the unblockUser field.
 func (r *mutationResolver) UnblockUser(ctx context.Context, id string) (bool, error) {
+	moderator := auth.ForContext(ctx)
+	if moderator == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+
+	current, _ := r.BanRepo.Current(ctx, id)
+	if err := r.BanRepo.Lift(ctx, id, moderator.ID); err != nil {
+		return false, fmt.Errorf("failed to lift ban: %w", err)
+	}
 	err := r.UserRepo.Unblock(ctx, id)
 	if err != nil {
 		return false, err
 	}
+	r.recordAudit(ctx, audit.ActionUserUnban, audit.TargetUser, id, current, nil)
 	return true, nil
 }
 
+// GrantRole is the resolver for the grantRole field.
+func (r *mutationResolver) GrantRole(ctx context.Context, userID string, role model.Role) (*model.User, error) {
+	actor := auth.ForContext(ctx)
+	if actor == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	target := roles.Role(role)
+	if target == roles.User {
+		return nil, fmt.Errorf("every user already has the USER role")
+	}
+	if roles.Privileged(target) && !auth.HasRole(actor, roles.SuperAdmin) {
+		return nil, fmt.Errorf("access denied: only superadmins can grant %s", target)
+	}
+
+	existing, err := r.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	updated, err := r.UserRepo.AddRole(ctx, userID, string(target))
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant role: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionUserGrantRole, audit.TargetUser, userID, userSnapshot(existing), userSnapshot(updated))
+	return mapUserToModel(updated), nil
+}
+
+// RevokeRole is the resolver for the revokeRole field.
+func (r *mutationResolver) RevokeRole(ctx context.Context, userID string, role model.Role) (*model.User, error) {
+	actor := auth.ForContext(ctx)
+	if actor == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	target := roles.Role(role)
+	if target == roles.User {
+		return nil, fmt.Errorf("the USER role can't be revoked")
+	}
+	if roles.Privileged(target) && !auth.HasRole(actor, roles.SuperAdmin) {
+		return nil, fmt.Errorf("access denied: only superadmins can revoke %s", target)
+	}
+	if actor.ID == userID && target == roles.SuperAdmin {
+		return nil, fmt.Errorf("superadmins can't revoke their own SUPERADMIN role")
+	}
+
+	existing, err := r.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	updated, err := r.UserRepo.RemoveRole(ctx, userID, string(target))
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke role: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionUserRevokeRole, audit.TargetUser, userID, userSnapshot(existing), userSnapshot(updated))
+	return mapUserToModel(updated), nil
+}
+
+// ReviewBanAppeal is the resolver for the reviewBanAppeal field.
+func (r *mutationResolver) ReviewBanAppeal(ctx context.Context, id string, accept bool, response *string) (*model.BanAppeal, error) {
+	reviewer := auth.ForContext(ctx)
+	if reviewer == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	status := bans.AppealRejected
+	if accept {
+		status = bans.AppealAccepted
+	}
+	text := ""
+	if response != nil {
+		text = strings.TrimSpace(*response)
+	}
+
+	appeal, err := r.BanRepo.ReviewAppeal(ctx, id, status, reviewer.ID, text)
+	if err != nil {
+		return nil, fmt.Errorf("appeal not found or already reviewed")
+	}
+	r.recordAudit(ctx, audit.ActionBanAppealReview, audit.TargetBanAppeal, appeal.ID, nil, appeal)
+
+	if accept {
+		if err := r.BanRepo.Lift(ctx, appeal.UserID, reviewer.ID); err != nil {
+			return nil, fmt.Errorf("failed to lift ban: %w", err)
+		}
+		if err := r.UserRepo.Unblock(ctx, appeal.UserID); err != nil {
+			return nil, fmt.Errorf("failed to unblock user: %w", err)
+		}
+	}
+
+	return r.banAppealToModel(ctx, appeal)
+}
+
+// AppealBan is the resolver for the appealBan field.
+func (r *mutationResolver) AppealBan(ctx context.Context, message string) (*model.BanAppeal, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	if !user.IsBanned {
+		return nil, fmt.Errorf("you are not banned")
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, fmt.Errorf("appeal message is required")
+	}
+	if len(message) > maxBanAppealLength {
+		return nil, fmt.Errorf("appeal message must be at most %d characters", maxBanAppealLength)
+	}
+
+	ban, err := r.BanRepo.Current(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("this ban can't be appealed")
+	}
+
+	appeal := &bans.Appeal{
+		BanID:     ban.ID,
+		UserID:    user.ID,
+		Message:   sanitization.SanitizeString(message),
+		Status:    bans.AppealPending,
+		CreatedAt: time.Now(),
+	}
+	if err := r.BanRepo.CreateAppeal(ctx, appeal); err != nil {
+		if err == bans.ErrAppealExists {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to submit appeal: %w", err)
+	}
+
+	return mapBanAppealToModel(appeal, ban, mapUserToPublic(user)), nil
+}
+
 // UpdateUser is the resolver for the updateUser field.
 func (r *mutationResolver) UpdateUser(ctx context.Context, input model.UpdateUserInput) (*model.User, error) {
 	user := auth.ForContext(ctx)
@@ -190,6 +379,9 @@ func (r *mutationResolver) UpdateUser(ctx context.Context, input model.UpdateUse
 	if err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
+	if input.Avatar != nil {
+		r.trackMedia(ctx, media.RefUser, user.ID, updatedUser.Avatar)
+	}
 
 	return mapUserToModel(updatedUser), nil
 }
@@ -201,11 +393,11 @@ func (r *mutationResolver) UploadAvatar(ctx context.Context, file graphql.Upload
 		return "", fmt.Errorf("not authenticated")
 	}
 
-	url, err := r.Uploader.UploadImage(ctx, file.File, "wikinitt/avatars")
+	m, err := r.uploadMedia(ctx, user, file, imaging.PurposeAvatar)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload avatar: %w", err)
+		return "", err
 	}
-	return url, nil
+	return m.URL, nil
 }
 
 // UploadUserImage is the resolver for the uploadUserImage field.
@@ -215,11 +407,298 @@ func (r *mutationResolver) UploadUserImage(ctx context.Context, file graphql.Upl
 		return "", fmt.Errorf("not authenticated")
 	}
 
-	url, err := r.Uploader.UploadImage(ctx, file.File, "wikinitt/user-uploads")
+	m, err := r.uploadMedia(ctx, user, file, imaging.PurposePost)
+	if err != nil {
+		return "", err
+	}
+	return m.URL, nil
+}
+
+// RequestPasswordReset is the resolver for the requestPasswordReset field.
+func (r *mutationResolver) RequestPasswordReset(ctx context.Context, email string) (bool, error) {
+	user, err := r.UserRepo.GetByEmail(ctx, email)
+	if err != nil {
+		// Same answer either way so the endpoint can't be used to probe accounts.
+		return true, nil
+	}
+
+	token, err := r.UserRepo.CreateToken(ctx, user.ID, users.TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return false, fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	r.sendPasswordResetEmail(ctx, user, token)
+	return true, nil
+}
+
+// ResetPassword is the resolver for the resetPassword field.
+func (r *mutationResolver) ResetPassword(ctx context.Context, token string, newPassword string) (bool, error) {
+	if err := auth.ValidatePassword(newPassword); err != nil {
+		return false, err
+	}
+
+	userID, err := r.UserRepo.ConsumeToken(ctx, users.TokenPurposePasswordReset, token)
+	if err != nil {
+		return false, err
+	}
+
+	hash, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := r.UserRepo.SetPassword(ctx, userID, hash); err != nil {
+		return false, fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	// The reset link proves control of the inbox.
+	_ = r.UserRepo.MarkEmailVerified(ctx, userID)
+
+	// A reset usually means the account was at risk, so drop API tokens too.
+	if err := r.APITokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return false, fmt.Errorf("failed to revoke api tokens: %w", err)
+	}
+	return true, nil
+}
+
+// ChangePassword is the resolver for the changePassword field.
+func (r *mutationResolver) ChangePassword(ctx context.Context, input model.ChangePasswordInput) (string, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return "", fmt.Errorf("not authenticated")
+	}
+
+	if user.PasswordHash != "" {
+		if input.CurrentPassword == nil || !auth.CheckPassword(user.PasswordHash, *input.CurrentPassword) {
+			return "", fmt.Errorf("current password is incorrect")
+		}
+	}
+
+	if err := auth.ValidatePassword(input.NewPassword); err != nil {
+		return "", err
+	}
+
+	hash, err := auth.HashPassword(input.NewPassword)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := r.UserRepo.SetPassword(ctx, user.ID, hash); err != nil {
+		return "", fmt.Errorf("failed to change password: %w", err)
+	}
+
+	// Older tokens are revoked by the password change, so hand back a fresh one.
+	return sessionToken(ctx, user.ID)
+}
+
+// RequestEmailVerification is the resolver for the requestEmailVerification field.
+func (r *mutationResolver) RequestEmailVerification(ctx context.Context) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+	if user.EmailVerified {
+		return true, nil
+	}
+
+	token, err := r.UserRepo.CreateToken(ctx, user.ID, users.TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return false, fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	if err := r.sendVerificationEmail(ctx, user, token); err != nil {
+		return false, fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return true, nil
+}
+
+// VerifyEmail is the resolver for the verifyEmail field.
+func (r *mutationResolver) VerifyEmail(ctx context.Context, token string) (bool, error) {
+	userID, err := r.UserRepo.ConsumeToken(ctx, users.TokenPurposeEmailVerification, token)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.UserRepo.MarkEmailVerified(ctx, userID); err != nil {
+		return false, fmt.Errorf("failed to verify email: %w", err)
+	}
+	return true, nil
+}
+
+// EnableTwoFactor is the resolver for the enableTwoFactor field.
+func (r *mutationResolver) EnableTwoFactor(ctx context.Context) (string, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return "", fmt.Errorf("not authenticated")
+	}
+	if user.TwoFactorEnabled {
+		return "", fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	if err := r.UserRepo.SetPendingTwoFactorSecret(ctx, user.ID, secret); err != nil {
+		return "", fmt.Errorf("failed to start two-factor enrollment: %w", err)
+	}
+
+	return auth.TOTPURI(twoFactorIssuer, user.Email, secret), nil
+}
+
+// ConfirmTwoFactor is the resolver for the confirmTwoFactor field.
+func (r *mutationResolver) ConfirmTwoFactor(ctx context.Context, code string) (*model.TwoFactorConfirmation, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	if user.TwoFactorPendingSecret == "" {
+		return nil, fmt.Errorf("call enableTwoFactor first")
+	}
+
+	step, ok := auth.ValidateTOTP(user.TwoFactorPendingSecret, code, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := r.UserRepo.EnableTwoFactor(ctx, user.ID, user.TwoFactorPendingSecret, hashes); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	_, _ = r.UserRepo.UseTOTPStep(ctx, user.ID, step)
+
+	token, err := auth.GenerateTwoFactorToken(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &model.TwoFactorConfirmation{
+		RecoveryCodes: codes,
+		Token:         token,
+	}, nil
+}
+
+// DisableTwoFactor is the resolver for the disableTwoFactor field.
+func (r *mutationResolver) DisableTwoFactor(ctx context.Context, code string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+	if !user.TwoFactorEnabled {
+		return true, nil
+	}
+
+	ok, err := r.verifySecondFactor(ctx, user, code)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, fmt.Errorf("invalid two-factor code")
+	}
+
+	if err := r.UserRepo.DisableTwoFactor(ctx, user.ID); err != nil {
+		return false, fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return true, nil
+}
+
+// RegenerateRecoveryCodes is the resolver for the regenerateRecoveryCodes field.
+func (r *mutationResolver) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	if !user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	ok, err := r.verifySecondFactor(ctx, user, code)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload image: %w", err)
+		return nil, err
 	}
-	return url, nil
+	if !ok {
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := r.UserRepo.SetRecoveryCodes(ctx, user.ID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// CreateAPIToken is the resolver for the createApiToken field.
+func (r *mutationResolver) CreateAPIToken(ctx context.Context, input model.NewAPIToken) (*model.CreatedAPIToken, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > 64 {
+		return nil, fmt.Errorf("token name must be between 1 and 64 characters")
+	}
+	if len(input.Scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	scopes := make([]apitokens.Scope, 0, len(input.Scopes))
+	for _, sc := range input.Scopes {
+		scope := apitokens.Scope(sc)
+		if !apitokens.ValidScope(scope) {
+			return nil, fmt.Errorf("invalid scope %s", sc)
+		}
+		scopes = append(scopes, scope)
+	}
+
+	days := defaultAPITokenDays
+	if input.ExpiresInDays != nil {
+		days = int(*input.ExpiresInDays)
+	}
+	if days < 0 || days > maxAPITokenDays {
+		return nil, fmt.Errorf("expiry must be between 0 and %d days", maxAPITokenDays)
+	}
+	var expiresAt *time.Time
+	if days > 0 {
+		t := time.Now().AddDate(0, 0, days)
+		expiresAt = &t
+	}
+
+	count, err := r.APITokenRepo.CountActive(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+	if count >= maxAPITokensPerUser {
+		return nil, fmt.Errorf("you can have at most %d active api tokens", maxAPITokensPerUser)
+	}
+
+	token, raw, err := r.APITokenRepo.Create(ctx, user.ID, name, scopes, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+	return &model.CreatedAPIToken{
+		Token:    raw,
+		APIToken: mapAPITokenToModel(token),
+	}, nil
+}
+
+// RevokeAPIToken is the resolver for the revokeApiToken field.
+func (r *mutationResolver) RevokeAPIToken(ctx context.Context, id string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+
+	if err := r.APITokenRepo.Revoke(ctx, id, user.ID); err != nil {
+		return false, fmt.Errorf("token not found")
+	}
+	return true, nil
 }
 
 // Posts is the resolver for the posts field.
@@ -297,6 +776,20 @@ func (r *publicUserResolver) Comments(ctx context.Context, obj *model.PublicUser
 	return modelComments, nil
 }
 
+// Karma is the resolver for the karma field.
+func (r *publicUserResolver) Karma(ctx context.Context, obj *model.PublicUser) (int32, error) {
+	total, err := r.KarmaRepo.Total(ctx, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return int32(total), nil
+}
+
+// GroupKarma is the resolver for the groupKarma field.
+func (r *publicUserResolver) GroupKarma(ctx context.Context, obj *model.PublicUser) ([]*model.GroupKarma, error) {
+	return r.groupKarma(ctx, obj.ID)
+}
+
 // Users is the resolver for the users field.
 func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
 	users, err := r.UserRepo.List(ctx)
@@ -330,6 +823,93 @@ func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
 	return mapUserToModel(user), nil
 }
 
+// APITokens is the resolver for the apiTokens field.
+func (r *queryResolver) APITokens(ctx context.Context) ([]*model.APIToken, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	tokens, err := r.APITokenRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+	result := make([]*model.APIToken, 0, len(tokens))
+	for _, t := range tokens {
+		result = append(result, mapAPITokenToModel(t))
+	}
+	return result, nil
+}
+
+// MyBanStatus is the resolver for the myBanStatus field.
+func (r *queryResolver) MyBanStatus(ctx context.Context) (*model.BanStatus, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	status := &model.BanStatus{Banned: user.IsBanned}
+	if !user.IsBanned {
+		return status, nil
+	}
+
+	ban, err := r.BanRepo.Current(ctx, user.ID)
+	if err != nil {
+		return status, nil
+	}
+	status.Ban = mapBanToModel(ban)
+
+	if appeal, err := r.BanRepo.GetAppealForBan(ctx, ban.ID); err == nil {
+		status.Appeal = mapBanAppealToModel(appeal, ban, mapUserToPublic(user))
+	}
+	return status, nil
+}
+
+// UserBans is the resolver for the userBans field.
+func (r *queryResolver) UserBans(ctx context.Context, userID string) ([]*model.Ban, error) {
+	list, err := r.BanRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bans: %w", err)
+	}
+	result := make([]*model.Ban, 0, len(list))
+	for _, b := range list {
+		result = append(result, mapBanToModel(b))
+	}
+	return result, nil
+}
+
+// BanAppeals is the resolver for the banAppeals field.
+func (r *queryResolver) BanAppeals(ctx context.Context, status *model.AppealStatus, limit *int32, offset *int32) ([]*model.BanAppeal, error) {
+	l := 20
+	o := 0
+	if limit != nil {
+		l = int(*limit)
+	}
+	if offset != nil {
+		o = int(*offset)
+	}
+
+	var filter *bans.AppealStatus
+	if status != nil {
+		s := bans.AppealStatus(*status)
+		filter = &s
+	}
+
+	appeals, err := r.BanRepo.ListAppeals(ctx, filter, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appeals: %w", err)
+	}
+	result := make([]*model.BanAppeal, 0, len(appeals))
+	for _, a := range appeals {
+		m, err := r.banAppealToModel(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
 // User is the resolver for the user field.
 func (r *queryResolver) User(ctx context.Context, username string) (*model.PublicUser, error) {
 	user, err := r.UserRepo.GetByUsername(ctx, username)
@@ -345,4 +925,8 @@ func (r *queryResolver) User(ctx context.Context, username string) (*model.Publi
 // PublicUser returns PublicUserResolver implementation.
 func (r *Resolver) PublicUser() PublicUserResolver { return &publicUserResolver{r} }
 
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
 type publicUserResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
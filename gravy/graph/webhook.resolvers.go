@@ -0,0 +1,203 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/webhooks"
+)
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.CreatedWebhook, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	groupID := ""
+	if input.GroupID != nil {
+		groupID = *input.GroupID
+	}
+	if err := r.authorizeWebhooks(ctx, user, groupID); err != nil {
+		return nil, err
+	}
+	if err := webhooks.ValidateURL(input.URL); err != nil {
+		return nil, err
+	}
+	events, err := webhookEvents(groupID, input.Events)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook secret: %w", err)
+	}
+
+	hook := &webhooks.Webhook{
+		GroupID:   groupID,
+		URL:       input.URL,
+		Secret:    secret,
+		Events:    events,
+		Active:    true,
+		CreatedBy: user.ID,
+	}
+	if err := r.WebhookRepo.Create(ctx, hook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionWebhookCreate, audit.TargetWebhook, hook.ID, nil, webhookSnapshot(hook))
+	return &model.CreatedWebhook{Webhook: webhookToModel(hook), Secret: secret}, nil
+}
+
+// UpdateWebhook is the resolver for the updateWebhook field.
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, id string, input model.UpdateWebhook) (*model.Webhook, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	hook, err := r.loadWebhook(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	before := webhookSnapshot(hook)
+
+	if input.URL != nil {
+		if err := webhooks.ValidateURL(*input.URL); err != nil {
+			return nil, err
+		}
+		hook.URL = *input.URL
+	}
+	if input.Events != nil {
+		events, err := webhookEvents(hook.GroupID, input.Events)
+		if err != nil {
+			return nil, err
+		}
+		hook.Events = events
+	}
+	if input.Active != nil {
+		hook.Active = *input.Active
+	}
+	if err := r.WebhookRepo.Update(ctx, hook); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionWebhookUpdate, audit.TargetWebhook, hook.ID, before, webhookSnapshot(hook))
+	return webhookToModel(hook), nil
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return false, fmt.Errorf("not authenticated")
+	}
+	hook, err := r.loadWebhook(ctx, user, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.WebhookRepo.Delete(ctx, hook.ID); err != nil {
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionWebhookDelete, audit.TargetWebhook, hook.ID, webhookSnapshot(hook), nil)
+	return true, nil
+}
+
+// RotateWebhookSecret is the resolver for the rotateWebhookSecret field.
+func (r *mutationResolver) RotateWebhookSecret(ctx context.Context, id string) (*model.CreatedWebhook, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	hook, err := r.loadWebhook(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook secret: %w", err)
+	}
+	hook.Secret = secret
+	if err := r.WebhookRepo.Update(ctx, hook); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	r.recordAudit(ctx, audit.ActionWebhookRotateSecret, audit.TargetWebhook, hook.ID, nil, nil)
+	return &model.CreatedWebhook{Webhook: webhookToModel(hook), Secret: secret}, nil
+}
+
+// TestWebhook is the resolver for the testWebhook field.
+func (r *mutationResolver) TestWebhook(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	hook, err := r.loadWebhook(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := r.Webhooks.Test(ctx, hook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send test delivery: %w", err)
+	}
+	return webhookDeliveryToModel(delivery), nil
+}
+
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context, groupID *string) ([]*model.Webhook, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	id := ""
+	if groupID != nil {
+		id = *groupID
+	}
+	if err := r.authorizeWebhooks(ctx, user, id); err != nil {
+		return nil, err
+	}
+	list, err := r.WebhookRepo.List(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhooks: %w", err)
+	}
+	result := make([]*model.Webhook, 0, len(list))
+	for _, h := range list {
+		result = append(result, webhookToModel(h))
+	}
+	return result, nil
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhookID string, limit *int32, offset *int32) ([]*model.WebhookDelivery, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	hook, err := r.loadWebhook(ctx, user, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	l := 20
+	if limit != nil {
+		l = int(*limit)
+	}
+	if l <= 0 || l > maxWebhookDeliveryPageSize {
+		l = maxWebhookDeliveryPageSize
+	}
+	o := 0
+	if offset != nil && *offset > 0 {
+		o = int(*offset)
+	}
+	list, err := r.WebhookRepo.ListDeliveries(ctx, hook.ID, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook deliveries: %w", err)
+	}
+	result := make([]*model.WebhookDelivery, 0, len(list))
+	for _, d := range list {
+		result = append(result, webhookDeliveryToModel(d))
+	}
+	return result, nil
+}
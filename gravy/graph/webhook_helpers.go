@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/webhooks"
+)
+
+const maxWebhookDeliveryPageSize = 100
+
+// authorizeWebhooks checks that the user can manage the webhooks of a group,
+// or the site-wide ones when groupID is empty. Only group owners manage group
+// webhooks since they see content moderators might not want shared. Site-wide
+// webhooks need the same second factor as @hasPermission fields.
+func (r *Resolver) authorizeWebhooks(ctx context.Context, user *users.User, groupID string) error {
+	if groupID == "" {
+		return auth.RequirePermission(ctx, user, roles.WebhooksManage)
+	}
+	_, err := r.authorizeGroup(ctx, user, groupID, community.GroupManageWebhooks)
+	return err
+}
+
+// loadWebhook fetches a webhook the user is allowed to manage.
+func (r *Resolver) loadWebhook(ctx context.Context, user *users.User, id string) (*webhooks.Webhook, error) {
+	hook, err := r.WebhookRepo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("webhook not found")
+	}
+	if err := r.authorizeWebhooks(ctx, user, hook.GroupID); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func webhookEvents(groupID string, list []model.WebhookEvent) ([]webhooks.Event, error) {
+	if len(list) == 0 {
+		return nil, fmt.Errorf("a webhook needs at least one event")
+	}
+	events := make([]webhooks.Event, 0, len(list))
+	seen := make(map[webhooks.Event]bool, len(list))
+	for _, e := range list {
+		event := webhooks.Event(e)
+		if !webhooks.Valid(event) {
+			return nil, fmt.Errorf("can't subscribe to %s", e)
+		}
+		if groupID != "" && !webhooks.GroupEvent(event) {
+			return nil, fmt.Errorf("group webhooks can't subscribe to %s", e)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// publishWebhook queues event for the subscribed webhooks. Failures are only
+// logged so they never fail the change that triggered them.
+func (r *Resolver) publishWebhook(ctx context.Context, groupID string, siteWide bool, event webhooks.Event, data interface{}) {
+	if r.Webhooks == nil {
+		return
+	}
+	if err := r.Webhooks.Publish(ctx, groupID, siteWide, event, data); err != nil {
+		log.Printf("Failed to queue %s webhooks: %v", event, err)
+	}
+}
+
+func (r *Resolver) publishArticleWebhook(ctx context.Context, event webhooks.Event, id, title, slug string) {
+	r.publishWebhook(ctx, "", true, event, map[string]string{
+		"id":    id,
+		"title": title,
+		"slug":  slug,
+		"url":   fmt.Sprintf("%s/articles/%s", frontendURL(), slug),
+	})
+}
+
+// Site-wide webhooks only see activity in public groups.
+func (r *Resolver) publishPostWebhook(ctx context.Context, group *community.Group, post *community.Post) {
+	r.publishWebhook(ctx, group.ID, group.Type == community.GroupTypePublic, webhooks.EventPostCreated, map[string]string{
+		"id":       post.ID,
+		"title":    post.Title,
+		"groupId":  group.ID,
+		"authorId": post.AuthorID,
+		"url":      fmt.Sprintf("%s/c/%s/posts/%s", frontendURL(), group.Slug, post.ID),
+	})
+}
+
+func (r *Resolver) publishCommentWebhook(ctx context.Context, group *community.Group, post *community.Post, comment *community.Comment) {
+	r.publishWebhook(ctx, group.ID, group.Type == community.GroupTypePublic, webhooks.EventCommentCreated, map[string]string{
+		"id":       comment.ID,
+		"postId":   post.ID,
+		"groupId":  group.ID,
+		"authorId": comment.AuthorID,
+		"url":      fmt.Sprintf("%s/c/%s/posts/%s", frontendURL(), group.Slug, post.ID),
+	})
+}
+
+func (r *Resolver) publishMemberWebhook(ctx context.Context, group *community.Group, userID string) {
+	r.publishWebhook(ctx, group.ID, group.Type == community.GroupTypePublic, webhooks.EventMemberJoined, map[string]string{
+		"groupId": group.ID,
+		"userId":  userID,
+	})
+}
+
+// webhookSnapshot leaves out the signing secret.
+func webhookSnapshot(h *webhooks.Webhook) interface{} {
+	if h == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"_id":     h.ID,
+		"groupId": h.GroupID,
+		"url":     h.URL,
+		"events":  h.Events,
+		"active":  h.Active,
+	}
+}
+
+func webhookToModel(h *webhooks.Webhook) *model.Webhook {
+	m := &model.Webhook{
+		ID:        h.ID,
+		URL:       h.URL,
+		Events:    make([]model.WebhookEvent, 0, len(h.Events)),
+		Active:    h.Active,
+		CreatedBy: h.CreatedBy,
+		CreatedAt: h.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: h.UpdatedAt.Format(time.RFC3339),
+	}
+	if h.GroupID != "" {
+		groupID := h.GroupID
+		m.GroupID = &groupID
+	}
+	for _, e := range h.Events {
+		m.Events = append(m.Events, model.WebhookEvent(e))
+	}
+	return m
+}
+
+func webhookDeliveryToModel(d *webhooks.Delivery) *model.WebhookDelivery {
+	m := &model.WebhookDelivery{
+		ID:        d.ID,
+		WebhookID: d.WebhookID,
+		Event:     model.WebhookEvent(d.Event),
+		Status:    model.WebhookDeliveryStatus(d.Status),
+		Attempts:  int32(d.Attempts),
+		Payload:   d.Payload,
+		CreatedAt: d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.ResponseCode != 0 {
+		code := int32(d.ResponseCode)
+		m.ResponseCode = &code
+	}
+	if d.Error != "" {
+		msg := d.Error
+		m.Error = &msg
+	}
+	if d.NextAttemptAt != nil {
+		next := d.NextAttemptAt.Format(time.RFC3339)
+		m.NextAttemptAt = &next
+	}
+	if d.DeliveredAt != nil {
+		delivered := d.DeliveredAt.Format(time.RFC3339)
+		m.DeliveredAt = &delivered
+	}
+	return m
+}
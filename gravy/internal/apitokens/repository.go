@@ -0,0 +1,199 @@
+package apitokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Prefix marks a bearer token as an API token rather than a session JWT.
+const Prefix = "wnt_"
+
+type Scope string
+
+const (
+	ScopeReadOnly       Scope = "READ_ONLY"
+	ScopeArticlesWrite  Scope = "ARTICLES_WRITE"
+	ScopeCommunityWrite Scope = "COMMUNITY_WRITE"
+)
+
+var ErrInvalidToken = errors.New("invalid, expired or revoked api token")
+
+type Token struct {
+	ID         string     `bson:"_id,omitempty"`
+	UserID     string     `bson:"userId"`
+	Name       string     `bson:"name"`
+	Hint       string     `bson:"hint"` // First characters of the token, for display
+	TokenHash  string     `bson:"tokenHash"`
+	Scopes     []Scope    `bson:"scopes"`
+	ExpiresAt  *time.Time `bson:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `bson:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `bson:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `bson:"createdAt"`
+}
+
+// Allows reports whether the token carries scope. Every token can read.
+func (t *Token) Allows(scope Scope) bool {
+	if scope == ScopeReadOnly {
+		return true
+	}
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func IsToken(raw string) bool {
+	return strings.HasPrefix(raw, Prefix)
+}
+
+type Repository interface {
+	Create(ctx context.Context, userID, name string, scopes []Scope, expiresAt *time.Time) (*Token, string, error)
+	ListByUser(ctx context.Context, userID string) ([]*Token, error)
+	CountActive(ctx context.Context, userID string) (int64, error)
+	Revoke(ctx context.Context, id, userID string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+	Authenticate(ctx context.Context, raw string) (*Token, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{
+		coll: db.Collection("apiTokens"),
+	}
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *repository) Create(ctx context.Context, userID, name string, scopes []Scope, expiresAt *time.Time) (*Token, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	raw := Prefix + base64.RawURLEncoding.EncodeToString(b)
+
+	token := &Token{
+		UserID:    userID,
+		Name:      name,
+		Hint:      raw[:len(Prefix)+6],
+		TokenHash: hashToken(raw),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	res, err := r.coll.InsertOne(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		token.ID = oid.Hex()
+	}
+	return token, raw, nil
+}
+
+func (r *repository) ListByUser(ctx context.Context, userID string) ([]*Token, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.coll.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []*Token
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func activeFilter(now time.Time) bson.M {
+	return bson.M{
+		"revokedAt": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$exists": false}},
+			bson.M{"expiresAt": bson.M{"$gt": now}},
+		},
+	}
+}
+
+func (r *repository) CountActive(ctx context.Context, userID string) (int64, error) {
+	filter := activeFilter(time.Now())
+	filter["userId"] = userID
+	return r.coll.CountDocuments(ctx, filter)
+}
+
+func (r *repository) Revoke(ctx context.Context, id, userID string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.coll.UpdateOne(ctx, bson.M{
+		"_id":       oid,
+		"userId":    userID,
+		"revokedAt": bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{"revokedAt": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *repository) RevokeAllForUser(ctx context.Context, userID string) error {
+	_, err := r.coll.UpdateMany(ctx, bson.M{
+		"userId":    userID,
+		"revokedAt": bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{"revokedAt": time.Now()}})
+	return err
+}
+
+// Authenticate looks up an active token and records its use.
+func (r *repository) Authenticate(ctx context.Context, raw string) (*Token, error) {
+	now := time.Now()
+	filter := activeFilter(now)
+	filter["tokenHash"] = hashToken(raw)
+
+	var token Token
+	err := r.coll.FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"lastUsedAt": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+	})
+	return err
+}
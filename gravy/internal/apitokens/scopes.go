@@ -0,0 +1,21 @@
+package apitokens
+
+import "github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+
+// ScopeForPermission returns the scope a token needs to exercise perm. The
+// second result is false for permissions that API tokens can never use.
+func ScopeForPermission(perm roles.Permission) (Scope, bool) {
+	switch perm {
+	case roles.ArticlesWrite, roles.CategoriesWrite:
+		return ScopeArticlesWrite, true
+	case roles.CommunityModerate:
+		return ScopeCommunityWrite, true
+	case roles.UsersView, roles.AuditView:
+		return ScopeReadOnly, true
+	}
+	return "", false
+}
+
+func ValidScope(s Scope) bool {
+	return s == ScopeReadOnly || s == ScopeArticlesWrite || s == ScopeCommunityWrite
+}
@@ -2,6 +2,7 @@ package articles
 
 import (
 	"context"
+	"regexp"
 	"time"
 	"strings"
 
@@ -13,18 +14,19 @@ import (
 )
 
 type Article struct {
-	ID        string            `bson:"_id,omitempty"`
-	Title     string            `bson:"title"`
-	Content   string            `bson:"content"`
-	Slug      string            `bson:"slug"`
-	Category  string            `bson:"category"`
-	Thumbnail string            `bson:"thumbnail"`
-	Featured  bool              `bson:"featured"`
-	AuthorID  string            `bson:"authorId"`
-	CreatedAt time.Time         `bson:"createdAt"`
-	UpdatedAt time.Time         `bson:"updatedAt"`
-	Indexed   bool              `bson:"indexed"`
-	Author    *users.PublicUser `bson:"-"`
+	ID         string            `bson:"_id,omitempty"`
+	Title      string            `bson:"title"`
+	Content    string            `bson:"content"`
+	Slug       string            `bson:"slug"`
+	Category   string            `bson:"category"`
+	Thumbnail  string            `bson:"thumbnail"`
+	Featured   bool              `bson:"featured"`
+	FeaturedAt *time.Time        `bson:"featuredAt,omitempty"` // When it was last featured
+	AuthorID   string            `bson:"authorId"`
+	CreatedAt  time.Time         `bson:"createdAt"`
+	UpdatedAt  time.Time         `bson:"updatedAt"`
+	Indexed    bool              `bson:"indexed"`
+	Author     *users.PublicUser `bson:"-"`
 }
 
 type Repository interface {
@@ -34,9 +36,14 @@ type Repository interface {
 	GetByID(ctx context.Context, id string) (*Article, error)
 	GetByIDs(ctx context.Context, ids []string) ([]*Article, error)
 	List(ctx context.Context, category *string, limit *int, offset *int, featured *bool) ([]*Article, error)
+	// ListFeaturedSince returns articles featured after since, most recently
+	// featured first.
+	ListFeaturedSince(ctx context.Context, since time.Time, limit int) ([]*Article, error)
 	ListUnindexed(ctx context.Context, limit int) ([]*Article, error)
 	MarkIndexed(ctx context.Context, id string) error
 	GetBySlug(ctx context.Context, slug string) (*Article, error)
+	// GetByTitle matches the title exactly, ignoring case.
+	GetByTitle(ctx context.Context, title string) (*Article, error)
 	EnsureIndexes(ctx context.Context) error
 	GetAllTitles(ctx context.Context) (map[string]string, error)
 	
@@ -60,24 +67,19 @@ func NewRepository(db *mongo.Database, searchClient *search.Client) Repository {
 func (r *repository) Create(ctx context.Context, article Article) (*Article, error) {
 	article.CreatedAt = time.Now()
 	article.UpdatedAt = time.Now()
+	if article.Featured {
+		featuredAt := article.CreatedAt
+		article.FeaturedAt = &featuredAt
+	}
 	res, err := r.coll.InsertOne(ctx, article)
 	if err != nil {
 		return nil, err
 	}
 	article.ID = res.InsertedID.(bson.ObjectID).Hex()
 
-	doc := map[string]interface{}{
-		"id":        article.ID,
-		"title":     article.Title,
-		"content":   article.Content,
-		"slug":      article.Slug,
-		"category":  article.Category,
-		"thumbnail": article.Thumbnail,
-		"authorID":  article.AuthorID,
-		"createdAt": article.CreatedAt.Unix(),
-	}
+	doc := SearchDocument(&article)
 	if err := r.searchClient.IndexArticle(ctx, doc); err == nil {
-		_, _ = r.coll.UpdateOne(ctx, bson.M{"_id": res.InsertedID}, bson.M{"$set": bson.M{"indexed": true}})
+		_, _ = r.coll.UpdateOne(ctx, bson.M{"_id": res.InsertedID}, bson.M{"$set": bson.M{"indexed": true, "searchVersion": SearchDocumentVersion}})
 		article.Indexed = true
 	}
 	return &article, nil
@@ -103,18 +105,9 @@ func (r *repository) Update(ctx context.Context, id string, updates bson.M) (*Ar
 		return nil, err
 	}
 
-	doc := map[string]interface{}{
-		"id":        updatedArticle.ID,
-		"title":     updatedArticle.Title,
-		"content":   updatedArticle.Content,
-		"slug":      updatedArticle.Slug,
-		"category":  updatedArticle.Category,
-		"thumbnail": updatedArticle.Thumbnail,
-		"authorID":  updatedArticle.AuthorID,
-		"createdAt": updatedArticle.CreatedAt.Unix(),
-	}
+	doc := SearchDocument(updatedArticle)
 	if err := r.searchClient.IndexArticle(ctx, doc); err == nil {
-		_, _ = r.coll.UpdateOne(ctx, bson.M{"_id": idObj}, bson.M{"$set": bson.M{"indexed": true}})
+		_, _ = r.coll.UpdateOne(ctx, bson.M{"_id": idObj}, bson.M{"$set": bson.M{"indexed": true, "searchVersion": SearchDocumentVersion}})
 		updatedArticle.Indexed = true
 	}
 
@@ -215,11 +208,27 @@ func (r *repository) List(ctx context.Context, category *string, limit *int, off
 	return articles, nil
 }
 
+func (r *repository) ListFeaturedSince(ctx context.Context, since time.Time, limit int) ([]*Article, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "featuredAt", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.coll.Find(ctx, bson.M{"featured": true, "featuredAt": bson.M{"$gt": since}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var articles []*Article
+	if err := cursor.All(ctx, &articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
 func (r *repository) ListUnindexed(ctx context.Context, limit int) ([]*Article, error) {
 	filter := bson.M{
 		"$or": []bson.M{
 			{"indexed": false},
 			{"indexed": bson.M{"$exists": false}},
+			{"searchVersion": bson.M{"$not": bson.M{"$gte": SearchDocumentVersion}}},
 		},
 	}
 	opts := options.Find().SetLimit(int64(limit))
@@ -239,7 +248,7 @@ func (r *repository) MarkIndexed(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": idObj}, bson.M{"$set": bson.M{"indexed": true}})
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": idObj}, bson.M{"$set": bson.M{"indexed": true, "searchVersion": SearchDocumentVersion}})
 	return err
 }
 
@@ -252,6 +261,16 @@ func (r *repository) GetBySlug(ctx context.Context, slug string) (*Article, erro
 	return &article, nil
 }
 
+func (r *repository) GetByTitle(ctx context.Context, title string) (*Article, error) {
+	var article Article
+	filter := bson.M{"title": bson.M{"$regex": "^" + regexp.QuoteMeta(title) + "$", "$options": "i"}}
+	err := r.coll.FindOne(ctx, filter).Decode(&article)
+	if err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
 func (r *repository) EnsureIndexes(ctx context.Context) error {
 	indices := []mongo.IndexModel{
 		{
@@ -260,6 +279,7 @@ func (r *repository) EnsureIndexes(ctx context.Context) error {
 		},
 		{Keys: bson.D{{Key: "category", Value: 1}}},
 		{Keys: bson.D{{Key: "featured", Value: 1}}},
+		{Keys: bson.D{{Key: "featured", Value: 1}, {Key: "featuredAt", Value: -1}}},
 		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
 		{Keys: bson.D{{Key: "indexed", Value: 1}}},
 	}
@@ -0,0 +1,25 @@
+package articles
+
+import (
+	"github.com/pranava-mohan/wikinitt/gravy/internal/sanitization"
+)
+
+// SearchDocumentVersion is bumped whenever SearchDocument changes shape, so
+// articles indexed with an older shape are picked up by ListUnindexed again.
+const SearchDocumentVersion = 2
+
+// SearchDocument is what the articles search index stores for a.
+func SearchDocument(a *Article) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        a.ID,
+		"title":     a.Title,
+		"content":   sanitization.PlainText(a.Content),
+		"slug":      a.Slug,
+		"category":  a.Category,
+		"thumbnail": a.Thumbnail,
+		"authorID":  a.AuthorID,
+		"featured":  a.Featured,
+		"createdAt": a.CreatedAt.Unix(),
+		"updatedAt": a.UpdatedAt.Unix(),
+	}
+}
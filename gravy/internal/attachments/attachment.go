@@ -0,0 +1,132 @@
+package attachments
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // Registers the GIF decoder
+	_ "image/jpeg" // Registers the JPEG decoder
+	_ "image/png"  // Registers the PNG decoder
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+)
+
+const (
+	// MaxSize is the largest file that can be attached.
+	MaxSize = 10 << 20
+	// MaxPerSource caps the attachments of one post or message.
+	MaxPerSource = 10
+
+	maxFilenameLength = 255
+)
+
+type Kind string
+
+const (
+	KindImage Kind = "IMAGE"
+	KindFile  Kind = "FILE"
+)
+
+type SourceType string
+
+const (
+	SourcePost    SourceType = "POST"
+	SourceMessage SourceType = "MESSAGE"
+)
+
+// Attachment is an uploaded file. It belongs to its uploader until it is
+// attached to a post or message, and is garbage-collected when that content
+// is deleted or when it is never attached.
+type Attachment struct {
+	ID          string           `bson:"_id,omitempty"`
+	OwnerID     string           `bson:"ownerId"`
+	Kind        Kind             `bson:"kind"`
+	Filename    string           `bson:"filename"`
+	ContentType string           `bson:"contentType"`
+	Size        int64            `bson:"size"`
+	Width       int              `bson:"width,omitempty"`
+	Height      int              `bson:"height,omitempty"`
+	URL         string           `bson:"url"`
+	Key         string           `bson:"key"`                // Storage key used to delete the file
+	Variants    []imaging.Stored `bson:"variants,omitempty"` // Resized copies of images, full size included
+	StoredSize  int64            `bson:"storedSize"`         // Of the file and its variants, counted against the owner's quota
+	SourceType  SourceType       `bson:"sourceType,omitempty"`
+	SourceID    string           `bson:"sourceId,omitempty"`
+	GroupID     string           `bson:"groupId,omitempty"`
+	DetachedAt  *time.Time       `bson:"detachedAt,omitempty"` // Set once its content was deleted
+	CreatedAt   time.Time        `bson:"createdAt"`
+	// Failed deletes are retried after RetryAt, waiting longer each attempt.
+	DeleteAttempts int        `bson:"deleteAttempts,omitempty"`
+	RetryAt        *time.Time `bson:"retryAt,omitempty"`
+}
+
+func (a *Attachment) Attached() bool {
+	return a.SourceID != ""
+}
+
+// Keys lists the storage keys of the file and its variants.
+func (a *Attachment) Keys() []string {
+	keys := []string{a.Key}
+	for _, v := range a.Variants {
+		if v.Key != a.Key {
+			keys = append(keys, v.Key)
+		}
+	}
+	return keys
+}
+
+// Describe inspects an upload and returns an attachment with its kind,
+// sniffed content type and, for images, dimensions. file is rewound so it
+// can be stored afterwards.
+func Describe(file io.ReadSeeker, filename string, size int64) (*Attachment, error) {
+	if size > MaxSize {
+		return nil, fmt.Errorf("attachments can be at most %d MB", MaxSize>>20)
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	head = head[:n]
+
+	a := &Attachment{
+		Kind:        KindFile,
+		Filename:    cleanFilename(filename),
+		ContentType: http.DetectContentType(head),
+		Size:        size,
+	}
+	if strings.HasPrefix(a.ContentType, "image/") {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if cfg, _, err := image.DecodeConfig(file); err == nil {
+			a.Kind = KindImage
+			a.Width = cfg.Width
+			a.Height = cfg.Height
+		}
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func cleanFilename(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	if utf8.RuneCountInString(name) > maxFilenameLength {
+		name = string([]rune(name)[:maxFilenameLength])
+	}
+	return name
+}
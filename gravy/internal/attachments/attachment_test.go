@@ -0,0 +1,47 @@
+package attachments
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestDescribeImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatal(err)
+	}
+	file := bytes.NewReader(buf.Bytes())
+
+	a, err := Describe(file, "../photos/cat.png", int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Kind != KindImage || a.ContentType != "image/png" || a.Width != 40 || a.Height != 30 {
+		t.Errorf("Describe() = %s %s %dx%d, want IMAGE image/png 40x30", a.Kind, a.ContentType, a.Width, a.Height)
+	}
+	if a.Filename != "cat.png" {
+		t.Errorf("filename = %q, want cat.png", a.Filename)
+	}
+	if pos, _ := file.Seek(0, 1); pos != 0 {
+		t.Errorf("file left at offset %d, want 0", pos)
+	}
+}
+
+func TestDescribeFile(t *testing.T) {
+	content := []byte("%PDF-1.4\n")
+	a, err := Describe(bytes.NewReader(content), "notes.pdf", int64(len(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Kind != KindFile || a.ContentType != "application/pdf" {
+		t.Errorf("Describe() = %s %s, want FILE application/pdf", a.Kind, a.ContentType)
+	}
+}
+
+func TestDescribeTooLarge(t *testing.T) {
+	if _, err := Describe(bytes.NewReader(nil), "big.bin", MaxSize+1); err == nil {
+		t.Error("Describe() accepted a file over MaxSize")
+	}
+}
@@ -0,0 +1,75 @@
+package attachments
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/quota"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/uploader"
+)
+
+const (
+	// UnattachedTTL is how long an upload may wait to be attached to a post
+	// or message before it is collected.
+	UnattachedTTL = 24 * time.Hour
+
+	gcBatchSize = 100
+	// maxRetryDelay caps how long a file that fails to delete waits for the
+	// next attempt.
+	maxRetryDelay = 24 * time.Hour
+)
+
+// Collector deletes the files of detached and abandoned attachments from
+// storage and gives their space back to the uploader's quota.
+type Collector struct {
+	Repo    Repository
+	Storage uploader.Uploader
+	Quota   quota.Repository
+}
+
+// Run collects one batch and returns how many attachments were removed.
+// Files that fail to delete are kept and retried later, backing off so they
+// don't fill every batch and hold up the rest.
+func (c *Collector) Run(ctx context.Context, now time.Time) (int, error) {
+	list, err := c.Repo.ListGarbage(ctx, now.Add(-UnattachedTTL), now, gcBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, a := range list {
+		if !c.deleteFiles(ctx, a) {
+			if err := c.Repo.Postpone(ctx, a.ID, now.Add(retryDelay(a.DeleteAttempts))); err != nil {
+				log.Printf("Failed to postpone attachment %s: %v", a.ID, err)
+			}
+			continue
+		}
+		if err := c.Repo.Delete(ctx, a.ID); err != nil {
+			log.Printf("Failed to delete attachment %s: %v", a.ID, err)
+			continue
+		}
+		if err := c.Quota.AddStorage(ctx, a.OwnerID, -a.StoredSize); err != nil {
+			log.Printf("Failed to update storage usage of user %s: %v", a.OwnerID, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (c *Collector) deleteFiles(ctx context.Context, a *Attachment) bool {
+	for _, key := range a.Keys() {
+		if err := c.Storage.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete attachment %s from storage: %v", a.ID, err)
+			return false
+		}
+	}
+	return true
+}
+
+// retryDelay doubles from an hour with each failed attempt.
+func retryDelay(attempts int) time.Duration {
+	if attempts >= 5 {
+		return maxRetryDelay
+	}
+	return min(time.Hour<<attempts, maxRetryDelay)
+}
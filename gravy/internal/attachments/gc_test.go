@@ -0,0 +1,21 @@
+package attachments
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:  time.Hour,
+		1:  2 * time.Hour,
+		4:  16 * time.Hour,
+		5:  maxRetryDelay,
+		70: maxRetryDelay,
+	}
+	for attempts, want := range cases {
+		if got := retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package attachments
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Repository interface {
+	Create(ctx context.Context, a *Attachment) error
+	GetMany(ctx context.Context, ids []string) ([]*Attachment, error)
+	// Attach links the owner's unattached uploads to a post or message.
+	Attach(ctx context.Context, ids []string, ownerID string, sourceType SourceType, sourceID, groupID string) error
+	ListForSource(ctx context.Context, sourceType SourceType, sourceID string) ([]*Attachment, error)
+	// Detach marks the attachments of deleted content for garbage collection.
+	Detach(ctx context.Context, sourceType SourceType, sourceID string) error
+	DetachGroup(ctx context.Context, groupID string) error
+	// ListGarbage returns detached attachments and uploads that were never
+	// attached before cutoff, oldest first, leaving out those whose retry is
+	// not due at now.
+	ListGarbage(ctx context.Context, cutoff, now time.Time, limit int) ([]*Attachment, error)
+	// Postpone records a failed delete and when to retry it.
+	Postpone(ctx context.Context, id string, retryAt time.Time) error
+	Delete(ctx context.Context, id string) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{coll: db.Collection("attachments")}
+}
+
+func objectIDs(ids []string) ([]bson.ObjectID, error) {
+	oids := make([]bson.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attachment id %q", id)
+		}
+		oids = append(oids, oid)
+	}
+	return oids, nil
+}
+
+func (r *repository) find(ctx context.Context, filter bson.M, opts ...options.Lister[options.FindOptions]) ([]*Attachment, error) {
+	cursor, err := r.coll.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var list []*Attachment
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repository) Create(ctx context.Context, a *Attachment) error {
+	a.CreatedAt = time.Now()
+	res, err := r.coll.InsertOne(ctx, a)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		a.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) GetMany(ctx context.Context, ids []string) ([]*Attachment, error) {
+	oids, err := objectIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": oids}})
+}
+
+func (r *repository) Attach(ctx context.Context, ids []string, ownerID string, sourceType SourceType, sourceID, groupID string) error {
+	oids, err := objectIDs(ids)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.UpdateMany(ctx, bson.M{
+		"_id":      bson.M{"$in": oids},
+		"ownerId":  ownerID,
+		"sourceId": bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{
+		"sourceType": sourceType,
+		"sourceId":   sourceID,
+		"groupId":    groupID,
+	}})
+	return err
+}
+
+func (r *repository) ListForSource(ctx context.Context, sourceType SourceType, sourceID string) ([]*Attachment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	return r.find(ctx, bson.M{"sourceType": sourceType, "sourceId": sourceID, "detachedAt": bson.M{"$exists": false}}, opts)
+}
+
+func (r *repository) Detach(ctx context.Context, sourceType SourceType, sourceID string) error {
+	_, err := r.coll.UpdateMany(ctx,
+		bson.M{"sourceType": sourceType, "sourceId": sourceID},
+		bson.M{"$set": bson.M{"detachedAt": time.Now()}})
+	return err
+}
+
+func (r *repository) DetachGroup(ctx context.Context, groupID string) error {
+	_, err := r.coll.UpdateMany(ctx,
+		bson.M{"groupId": groupID, "detachedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"detachedAt": time.Now()}})
+	return err
+}
+
+func (r *repository) ListGarbage(ctx context.Context, cutoff, now time.Time, limit int) ([]*Attachment, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit))
+	return r.find(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"detachedAt": bson.M{"$exists": true}},
+			bson.M{"sourceId": bson.M{"$exists": false}, "createdAt": bson.M{"$lt": cutoff}},
+		},
+		"retryAt": bson.M{"$not": bson.M{"$gt": now}},
+	}, opts)
+}
+
+func (r *repository) Postpone(ctx context.Context, id string, retryAt time.Time) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$inc": bson.M{"deleteAttempts": 1},
+		"$set": bson.M{"retryAt": retryAt},
+	})
+	return err
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sourceType", Value: 1}, {Key: "sourceId", Value: 1}}},
+		{Keys: bson.D{{Key: "groupId", Value: 1}}},
+		{Keys: bson.D{{Key: "detachedAt", Value: 1}}},
+		{Keys: bson.D{{Key: "sourceId", Value: 1}, {Key: "createdAt", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create attachment indexes: %w", err)
+	}
+	return nil
+}
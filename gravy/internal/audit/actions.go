@@ -0,0 +1,62 @@
+package audit
+
+const (
+	ActionArticleCreate        = "article.create"
+	ActionArticleUpdate        = "article.update"
+	ActionArticleDelete        = "article.delete"
+	ActionCategoryCreate       = "category.create"
+	ActionCategoryDelete       = "category.delete"
+	ActionMapLocationCreate    = "mapLocation.create"
+	ActionMapLocationDelete    = "mapLocation.delete"
+	ActionUserBan              = "user.ban"
+	ActionUserUnban            = "user.unban"
+	ActionUserGrantRole        = "user.grantRole"
+	ActionUserRevokeRole       = "user.revokeRole"
+	ActionBanAppealReview      = "banAppeal.review"
+	ActionGroupUpdate          = "group.update"
+	ActionGroupInvite          = "group.generateInvite"
+	ActionGroupAcceptRequest   = "group.acceptJoinRequest"
+	ActionGroupRejectRequest   = "group.rejectJoinRequest"
+	ActionGroupRemoveMember    = "group.removeMember"
+	ActionGroupAddModerator    = "group.addModerator"
+	ActionGroupRemoveModerator = "group.removeModerator"
+	ActionGroupTransfer        = "group.transferOwnership"
+	ActionGroupBan             = "group.ban"
+	ActionGroupUnban           = "group.unban"
+	ActionGroupMute            = "group.mute"
+	ActionGroupUnmute          = "group.unmute"
+	ActionGroupDelete          = "group.delete"
+	ActionPostDelete           = "post.delete"
+	ActionPostPin              = "post.pin"
+	ActionPostUnpin            = "post.unpin"
+	ActionPostLock             = "post.lock"
+	ActionPostUnlock           = "post.unlock"
+	ActionPostAnnouncement     = "post.setAnnouncement"
+	ActionCommentDelete        = "comment.delete"
+	ActionMessageDelete        = "message.delete"
+	ActionReportResolve        = "report.resolve"
+	ActionAutomodRuleCreate    = "automodRule.create"
+	ActionAutomodRuleUpdate    = "automodRule.update"
+	ActionAutomodRuleDelete    = "automodRule.delete"
+	ActionWebhookCreate        = "webhook.create"
+	ActionWebhookUpdate        = "webhook.update"
+	ActionWebhookDelete        = "webhook.delete"
+	ActionWebhookRotateSecret  = "webhook.rotateSecret"
+	ActionMediaDelete          = "media.delete"
+)
+
+const (
+	TargetArticle     = "article"
+	TargetCategory    = "category"
+	TargetMapLocation = "mapLocation"
+	TargetUser        = "user"
+	TargetBanAppeal   = "banAppeal"
+	TargetGroup       = "group"
+	TargetPost        = "post"
+	TargetComment     = "comment"
+	TargetMessage     = "message"
+	TargetReport      = "report"
+	TargetAutomodRule = "automodRule"
+	TargetWebhook     = "webhook"
+	TargetMedia       = "media"
+)
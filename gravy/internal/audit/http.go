@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var metadataCtxKey = &contextKey{"auditMetadata"}
+
+type contextKey struct {
+	name string
+}
+
+// Metadata describes the request an audited action came from.
+type Metadata struct {
+	IP        string
+	UserAgent string
+}
+
+func clientIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		return strings.TrimSpace(strings.Split(xff, ",")[0])
+	}
+	if xri := r.Header.Get("X-Real-IP"); xri != "" {
+		return xri
+	}
+	return r.RemoteAddr
+}
+
+// Middleware stores the request metadata for Record.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), metadataCtxKey, Metadata{
+			IP:        clientIP(r),
+			UserAgent: r.UserAgent(),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func filterFromQuery(r *http.Request) (Filter, error) {
+	q := r.URL.Query()
+	var f Filter
+	str := func(key string) *string {
+		if v := q.Get(key); v != "" {
+			return &v
+		}
+		return nil
+	}
+	f.ActorID = str("actorId")
+	f.Action = str("action")
+	f.TargetType = str("targetType")
+	f.TargetID = str("targetId")
+	for key, dst := range map[string]**time.Time{"since": &f.Since, "until": &f.Until} {
+		if v := q.Get(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, err
+			}
+			*dst = &t
+		}
+	}
+	return f, nil
+}
+
+// CSVHandler exports the entries matching the query string filters.
+// authorize decides whether the request may read the audit log.
+func CSVHandler(repo Repository, authorize func(*http.Request) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !authorize(r) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		filter, err := filterFromQuery(r)
+		if err != nil {
+			http.Error(w, "Invalid date, expected RFC3339", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit-log.csv"`)
+
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"id", "createdAt", "actorId", "action", "targetType", "targetId", "before", "after", "ip", "userAgent", "apiTokenId"})
+		err = repo.Each(r.Context(), filter, func(e *Entry) error {
+			record := []string{
+				e.ID, e.CreatedAt.Format(time.RFC3339), e.ActorID, e.Action,
+				e.TargetType, e.TargetID, e.Before, e.After, e.IP, e.UserAgent, e.APITokenID,
+			}
+			for i, cell := range record {
+				record[i] = csvCell(cell)
+			}
+			return cw.Write(record)
+		})
+		cw.Flush()
+		if err != nil {
+			// Headers are already sent, so all we can do is cut the file short.
+			return
+		}
+	})
+}
+
+// csvCell keeps spreadsheets from running user-controlled text, such as a
+// title in a snapshot, as a formula.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,20 @@
+package audit
+
+import "testing"
+
+func TestCSVCell(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                  "",
+		"plain":             "plain",
+		`{"title":"=1+1"}`:  `{"title":"=1+1"}`,
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+1":                "'+1",
+		"-1":                "'-1",
+		"@SUM(A1)":          "'@SUM(A1)",
+		"\t=1":              "'\t=1",
+	} {
+		if got := csvCell(in); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
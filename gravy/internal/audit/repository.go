@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Entry is a single privileged action. Entries are never updated or deleted.
+type Entry struct {
+	ID         string    `bson:"_id,omitempty"`
+	ActorID    string    `bson:"actorId"`
+	Action     string    `bson:"action"`
+	TargetType string    `bson:"targetType"`
+	TargetID   string    `bson:"targetId"`
+	Before     string    `bson:"before,omitempty"` // JSON snapshot
+	After      string    `bson:"after,omitempty"`  // JSON snapshot
+	IP         string    `bson:"ip,omitempty"`
+	UserAgent  string    `bson:"userAgent,omitempty"`
+	APITokenID string    `bson:"apiTokenId,omitempty"`
+	CreatedAt  time.Time `bson:"createdAt"`
+}
+
+type Filter struct {
+	ActorID    *string
+	Action     *string
+	TargetType *string
+	TargetID   *string
+	Since      *time.Time
+	Until      *time.Time
+}
+
+type Repository interface {
+	Record(ctx context.Context, entry *Entry) error
+	// List returns entries newest first. after is the ID of the last entry
+	// of the previous page.
+	List(ctx context.Context, filter Filter, after string, limit int) ([]*Entry, error)
+	Each(ctx context.Context, filter Filter, fn func(*Entry) error) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{
+		coll: db.Collection("auditLog"),
+	}
+}
+
+// Record stores entry, filling in the request metadata from ctx.
+func (r *repository) Record(ctx context.Context, entry *Entry) error {
+	if meta, ok := ctx.Value(metadataCtxKey).(Metadata); ok {
+		entry.IP = meta.IP
+		entry.UserAgent = meta.UserAgent
+	}
+	entry.CreatedAt = time.Now()
+
+	res, err := r.coll.InsertOne(ctx, entry)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		entry.ID = oid.Hex()
+	}
+	return nil
+}
+
+func buildFilter(f Filter) bson.M {
+	filter := bson.M{}
+	if f.ActorID != nil {
+		filter["actorId"] = *f.ActorID
+	}
+	if f.Action != nil {
+		filter["action"] = *f.Action
+	}
+	if f.TargetType != nil {
+		filter["targetType"] = *f.TargetType
+	}
+	if f.TargetID != nil {
+		filter["targetId"] = *f.TargetID
+	}
+	if f.Since != nil || f.Until != nil {
+		createdAt := bson.M{}
+		if f.Since != nil {
+			createdAt["$gte"] = *f.Since
+		}
+		if f.Until != nil {
+			createdAt["$lt"] = *f.Until
+		}
+		filter["createdAt"] = createdAt
+	}
+	return filter
+}
+
+func (r *repository) List(ctx context.Context, f Filter, after string, limit int) ([]*Entry, error) {
+	filter := buildFilter(f)
+	if after != "" {
+		oid, err := bson.ObjectIDFromHex(after)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$lt": oid}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *repository) Each(ctx context.Context, f Filter, fn func(*Entry) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.coll.Find(ctx, buildFilter(f), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry Entry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "actorId", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "action", Value: 1}, {Key: "_id", Value: -1}}},
+	})
+	return err
+}
+
+// Snapshot encodes v as JSON using its bson field names, so snapshots read
+// like the stored documents.
+func Snapshot(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := bson.MarshalExtJSON(v, false, false)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -16,10 +16,30 @@ func getJwtSecret() []byte {
 	return jwtSecret
 }
 
+type TokenClaims struct {
+	UserID   string
+	IssuedAt time.Time
+	// TwoFactor is set when the session was established with a second factor.
+	TwoFactor bool
+}
+
 func GenerateToken(userID string) (string, error) {
+	return generateToken(userID, false)
+}
+
+func GenerateTwoFactorToken(userID string) (string, error) {
+	return generateToken(userID, true)
+}
+
+func generateToken(userID string, twoFactor bool) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24 * 90).Unix(),
+		"iat":     now.Unix(),
+		"exp":     now.Add(time.Hour * 24 * 90).Unix(),
+	}
+	if twoFactor {
+		claims["mfa"] = true
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -27,18 +47,36 @@ func GenerateToken(userID string) (string, error) {
 }
 
 func ParseToken(tokenStr string) (string, error) {
+	claims, err := ParseTokenClaims(tokenStr)
+	if err != nil {
+		return "", err
+	}
+	return claims.UserID, nil
+}
+
+func ParseTokenClaims(tokenStr string) (*TokenClaims, error) {
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 		return getJwtSecret(), nil
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := claims["user_id"].(string)
-		return userID, nil
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, jwt.ErrTokenInvalidClaims
 	}
 
-	return "", jwt.ErrTokenInvalidClaims
+	result := &TokenClaims{UserID: userID}
+	result.TwoFactor, _ = claims["mfa"].(bool)
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Time
+	}
+	return result, nil
 }
@@ -2,55 +2,126 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/pranava-mohan/wikinitt/gravy/internal/apitokens"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/bans"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
 )
 
 var userCtxKey = &contextKey{"user"}
+var twoFactorCtxKey = &contextKey{"twoFactor"}
+var apiTokenCtxKey = &contextKey{"apiToken"}
 
 type contextKey struct {
 	name string
 }
 
-func Middleware(userRepo users.Repository) func(http.Handler) http.Handler {
+// ErrInvalidToken is returned for tokens that are malformed, unknown or
+// revoked.
+var ErrInvalidToken = errors.New("invalid token")
+
+func Middleware(userRepo users.Repository, tokenRepo apitokens.Repository, banRepo bans.Repository) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			header := r.Header.Get("Authorization")
-
-			if header == "" {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			tokenStr := header
-			splitToken := strings.Split(header, "Bearer ")
-			if len(splitToken) == 2 {
-				tokenStr = splitToken[1]
-			}
-
-			userID, err := ParseToken(tokenStr)
+			ctx, err := Authenticate(r.Context(), userRepo, tokenRepo, banRepo, r.Header.Get("Authorization"))
 			if err != nil {
 				http.Error(w, "Invalid token", http.StatusForbidden)
 				return
 			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
 
-			user, err := userRepo.GetByID(r.Context(), userID)
-			if err != nil {
-				next.ServeHTTP(w, r)
-				return
-			}
+// Authenticate returns ctx carrying the user an Authorization value belongs
+// to. Browsers can't set headers on websockets, so subscriptions pass the
+// same value in their connection params. An empty value, or a session token
+// of a deleted user, leaves ctx unauthenticated.
+func Authenticate(ctx context.Context, userRepo users.Repository, tokenRepo apitokens.Repository, banRepo bans.Repository, header string) (context.Context, error) {
+	if header == "" {
+		return ctx, nil
+	}
 
-			ctx := context.WithValue(r.Context(), userCtxKey, user)
+	tokenStr := header
+	splitToken := strings.Split(header, "Bearer ")
+	if len(splitToken) == 2 {
+		tokenStr = splitToken[1]
+	}
 
-			r = r.WithContext(ctx)
-			next.ServeHTTP(w, r)
-		})
+	if apitokens.IsToken(tokenStr) {
+		token, err := tokenRepo.Authenticate(ctx, tokenStr)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		user, err := userRepo.GetByID(ctx, token.UserID)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		liftExpiredBan(ctx, userRepo, banRepo, user)
+		ctx = context.WithValue(ctx, userCtxKey, user)
+		return context.WithValue(ctx, apiTokenCtxKey, token), nil
+	}
+
+	claims, err := ParseTokenClaims(tokenStr)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return ctx, nil
+	}
+
+	// Tokens issued before the last password change are revoked.
+	if user.PasswordChangedAt != nil && claims.IssuedAt.Unix() < user.PasswordChangedAt.Unix() {
+		return nil, ErrInvalidToken
 	}
+
+	liftExpiredBan(ctx, userRepo, banRepo, user)
+	ctx = context.WithValue(ctx, userCtxKey, user)
+	return context.WithValue(ctx, twoFactorCtxKey, claims.TwoFactor), nil
+}
+
+// liftExpiredBan unbans user once their temporary ban has run out. Bans
+// without a record (set before ban records existed) never expire.
+func liftExpiredBan(ctx context.Context, userRepo users.Repository, banRepo bans.Repository, user *users.User) {
+	if !user.IsBanned {
+		return
+	}
+	ban, err := banRepo.Current(ctx, user.ID)
+	if err != nil || !ban.Expired(time.Now()) {
+		return
+	}
+	if err := banRepo.Lift(ctx, user.ID, ""); err != nil {
+		log.Printf("Failed to lift expired ban for %s: %v", user.ID, err)
+		return
+	}
+	if err := userRepo.Unblock(ctx, user.ID); err != nil {
+		log.Printf("Failed to unblock %s: %v", user.ID, err)
+		return
+	}
+	user.IsBanned = false
 }
 
 func ForContext(ctx context.Context) *users.User {
 	raw, _ := ctx.Value(userCtxKey).(*users.User)
 	return raw
 }
+
+// TwoFactorVerified reports whether the current session passed a second factor.
+func TwoFactorVerified(ctx context.Context) bool {
+	v, _ := ctx.Value(twoFactorCtxKey).(bool)
+	return v
+}
+
+// APITokenForContext returns the API token the request authenticated with,
+// or nil for session tokens.
+func APITokenForContext(ctx context.Context) *apitokens.Token {
+	raw, _ := ctx.Value(apiTokenCtxKey).(*apitokens.Token)
+	return raw
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+// UserRoles returns the effective roles of u. Everyone is a USER, and the
+// legacy isAdmin flag counts as the ADMIN role.
+func UserRoles(u *users.User) []roles.Role {
+	if u == nil {
+		return nil
+	}
+	result := []roles.Role{roles.User}
+	seen := map[roles.Role]bool{roles.User: true}
+	if u.IsAdmin {
+		result = append(result, roles.Admin)
+		seen[roles.Admin] = true
+	}
+	for _, r := range u.Roles {
+		role := roles.Role(r)
+		if !seen[role] && roles.Valid(role) {
+			result = append(result, role)
+			seen[role] = true
+		}
+	}
+	return result
+}
+
+// HasRole reports whether u holds role. Admins implicitly hold every role
+// except SUPERADMIN.
+func HasRole(u *users.User, role roles.Role) bool {
+	for _, r := range UserRoles(u) {
+		if r == role || r == roles.SuperAdmin {
+			return true
+		}
+		if r == roles.Admin && role != roles.SuperAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+func IsAdmin(u *users.User) bool {
+	return HasRole(u, roles.Admin)
+}
+
+func HasPermission(u *users.User, perm roles.Permission) bool {
+	for _, r := range UserRoles(u) {
+		if roles.Grants(r, perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAdminTwoFactor refuses admins whose session wasn't established with a
+// second factor when REQUIRE_ADMIN_2FA is set.
+func CheckAdminTwoFactor(ctx context.Context, u *users.User) error {
+	required := strings.ToLower(os.Getenv("REQUIRE_ADMIN_2FA")) == "true"
+	if required && IsAdmin(u) && (!u.TwoFactorEnabled || !TwoFactorVerified(ctx)) {
+		return fmt.Errorf("access denied: two-factor authentication required for admins")
+	}
+	return nil
+}
+
+// RequirePermission applies the checks of the @hasPermission directive, for
+// resolvers that act on a permission their own field doesn't declare.
+func RequirePermission(ctx context.Context, u *users.User, perm roles.Permission) error {
+	if !HasPermission(u, perm) {
+		return fmt.Errorf("access denied: missing permission %s", perm)
+	}
+	return CheckAdminTwoFactor(ctx, u)
+}
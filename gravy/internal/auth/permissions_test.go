@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+func TestRequirePermission(t *testing.T) {
+	admin := &users.User{ID: "a", IsAdmin: true, TwoFactorEnabled: true}
+	verified := context.WithValue(context.Background(), twoFactorCtxKey, true)
+
+	if err := RequirePermission(context.Background(), &users.User{ID: "u"}, roles.UsersBan); err == nil {
+		t.Fatal("user without the permission was allowed")
+	}
+
+	t.Setenv("REQUIRE_ADMIN_2FA", "false")
+	if err := RequirePermission(context.Background(), admin, roles.UsersBan); err != nil {
+		t.Fatalf("admin without 2FA required: %v", err)
+	}
+
+	t.Setenv("REQUIRE_ADMIN_2FA", "true")
+	if err := RequirePermission(context.Background(), admin, roles.UsersBan); err == nil {
+		t.Fatal("admin session without a second factor was allowed")
+	}
+	if err := RequirePermission(verified, admin, roles.UsersBan); err != nil {
+		t.Fatalf("verified admin: %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package automod
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Content is a post, comment or message about to be saved.
+type Content struct {
+	Kind            ContentKind
+	GroupID         string
+	AuthorID        string
+	AuthorCreatedAt time.Time
+	AuthorKarma     int
+	Text            string
+}
+
+// Decision is the outcome of a check. Action is empty when no rule fired.
+type Decision struct {
+	Action   Action
+	Reasons  []string
+	Triggers []*Trigger
+
+	authorID    string
+	fingerprint string // Set when a DUPLICATE rule needs the content remembered
+}
+
+func (d *Decision) Triggered() bool {
+	return d.Action != ""
+}
+
+func (d *Decision) Reason() string {
+	return strings.Join(d.Reasons, "; ")
+}
+
+type Engine struct {
+	repo Repository
+
+	mu      sync.Mutex
+	regexes map[string]*regexp.Regexp
+}
+
+func NewEngine(repo Repository) *Engine {
+	return &Engine{repo: repo, regexes: make(map[string]*regexp.Regexp)}
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.`)
+
+const maxExcerptLength = 200
+
+// Check evaluates every active global and group rule against the content. The
+// decision carries the most severe action. Triggers of rejected content are
+// logged right away; otherwise Record logs them once the content is saved.
+func (e *Engine) Check(ctx context.Context, c Content) (*Decision, error) {
+	rules, err := e.repo.ActiveRules(ctx, c.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &Decision{authorID: c.AuthorID}
+	now := time.Now()
+	fingerprint := hashContent(c.Text)
+	checksDuplicates := false
+	for _, rule := range rules {
+		if rule.Type == RuleDuplicate {
+			checksDuplicates = true
+		}
+		reason, err := e.evaluate(ctx, rule, c, fingerprint, now)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			continue
+		}
+		decision.Reasons = append(decision.Reasons, reason)
+		decision.Triggers = append(decision.Triggers, &Trigger{
+			RuleID:    rule.ID,
+			RuleType:  rule.Type,
+			Action:    rule.Action,
+			Kind:      c.Kind,
+			AuthorID:  c.AuthorID,
+			GroupID:   c.GroupID,
+			Reason:    reason,
+			Excerpt:   excerpt(c.Text),
+			CreatedAt: now,
+		})
+		if rule.Action.severity() > decision.Action.severity() {
+			decision.Action = rule.Action
+		}
+	}
+
+	if decision.Action == ActionReject {
+		if err := e.repo.RecordTriggers(ctx, decision.Triggers); err != nil {
+			return nil, err
+		}
+		return decision, nil
+	}
+	if checksDuplicates {
+		decision.fingerprint = fingerprint
+	}
+	return decision, nil
+}
+
+// Record logs the triggers of content that has been saved and remembers it
+// for DUPLICATE rules. Content that failed to save is never recorded, so a
+// retry isn't taken for a copy.
+func (e *Engine) Record(ctx context.Context, d *Decision, contentID string) error {
+	if d.fingerprint != "" {
+		if err := e.repo.AddFingerprint(ctx, d.authorID, d.fingerprint); err != nil {
+			return err
+		}
+	}
+	for _, t := range d.Triggers {
+		t.ContentID = contentID
+	}
+	return e.repo.RecordTriggers(ctx, d.Triggers)
+}
+
+func (e *Engine) evaluate(ctx context.Context, rule *Rule, c Content, fingerprint string, now time.Time) (string, error) {
+	switch rule.Type {
+	case RuleBannedWords:
+		lower := strings.ToLower(c.Text)
+		for _, word := range rule.Words {
+			if word != "" && containsWord(lower, strings.ToLower(word)) {
+				return fmt.Sprintf("contains banned word %q", word), nil
+			}
+		}
+	case RuleRegex:
+		re, err := e.compile(rule.Pattern)
+		if err != nil {
+			// Patterns are validated when saved, so skip rather than block
+			// every post on a bad one.
+			return "", nil
+		}
+		if re.MatchString(c.Text) {
+			return "matches pattern " + rule.Pattern, nil
+		}
+	case RuleLinkLimit:
+		if accountAge(c, now) >= hours(rule.AccountAgeHours) {
+			return "", nil
+		}
+		if n := len(linkPattern.FindAllStringIndex(c.Text, -1)); n > rule.MaxLinks {
+			return fmt.Sprintf("%d links from an account younger than %d hours", n, rule.AccountAgeHours), nil
+		}
+	case RuleDuplicate:
+		window := time.Duration(rule.DuplicateWindowMinutes) * time.Minute
+		if window <= 0 || window > fingerprintTTL {
+			window = fingerprintTTL
+		}
+		count, err := e.repo.CountFingerprints(ctx, c.AuthorID, fingerprint, now.Add(-window))
+		if err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return fmt.Sprintf("duplicate of content posted in the last %d minutes", int(window.Minutes())), nil
+		}
+	case RuleMinAccountAge:
+		if accountAge(c, now) < hours(rule.AccountAgeHours) {
+			return fmt.Sprintf("account younger than %d hours", rule.AccountAgeHours), nil
+		}
+	case RuleMinKarma:
+		if c.AuthorKarma < rule.MinKarma {
+			return fmt.Sprintf("author has less than %d karma", rule.MinKarma), nil
+		}
+	}
+	return "", nil
+}
+
+func (e *Engine) compile(pattern string) (*regexp.Regexp, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if re, ok := e.regexes[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	e.regexes[pattern] = re
+	return re, nil
+}
+
+// Validate checks that a rule has the settings its type needs.
+func Validate(rule *Rule) error {
+	switch rule.Action {
+	case ActionFlag, ActionHold, ActionReject:
+	default:
+		return fmt.Errorf("invalid action %s", rule.Action)
+	}
+	switch rule.Type {
+	case RuleBannedWords:
+		if len(rule.Words) == 0 {
+			return fmt.Errorf("banned words rule needs at least one word")
+		}
+	case RuleRegex:
+		if rule.Pattern == "" {
+			return fmt.Errorf("regex rule needs a pattern")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	case RuleLinkLimit:
+		if rule.MaxLinks < 0 || rule.AccountAgeHours <= 0 {
+			return fmt.Errorf("link limit rule needs a max links count and an account age")
+		}
+	case RuleDuplicate:
+		if rule.DuplicateWindowMinutes <= 0 || time.Duration(rule.DuplicateWindowMinutes)*time.Minute > fingerprintTTL {
+			return fmt.Errorf("duplicate window must be between 1 minute and %d days", int(fingerprintTTL.Hours()/24))
+		}
+	case RuleMinAccountAge:
+		if rule.AccountAgeHours <= 0 {
+			return fmt.Errorf("minimum account age rule needs an account age")
+		}
+	case RuleMinKarma:
+		if rule.MinKarma <= 0 {
+			return fmt.Errorf("minimum karma rule needs a karma threshold")
+		}
+	default:
+		return fmt.Errorf("invalid rule type %s", rule.Type)
+	}
+	return nil
+}
+
+func containsWord(text, word string) bool {
+	for i := strings.Index(text, word); i >= 0; {
+		end := i + len(word)
+		if (i == 0 || !isWordByte(text[i-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		next := strings.Index(text[i+1:], word)
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return false
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 0x80
+}
+
+func accountAge(c Content, now time.Time) time.Duration {
+	return now.Sub(c.AuthorCreatedAt)
+}
+
+func hours(n int) time.Duration {
+	return time.Duration(n) * time.Hour
+}
+
+// hashContent ignores case and whitespace so trivially edited copies still
+// count as duplicates.
+func hashContent(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func excerpt(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxExcerptLength {
+		return text
+	}
+	return string(runes[:maxExcerptLength]) + "…"
+}
@@ -0,0 +1,291 @@
+package automod
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type RuleType string
+
+const (
+	RuleBannedWords   RuleType = "BANNED_WORDS"
+	RuleRegex         RuleType = "REGEX"
+	RuleLinkLimit     RuleType = "LINK_LIMIT"
+	RuleDuplicate     RuleType = "DUPLICATE"
+	RuleMinAccountAge RuleType = "MIN_ACCOUNT_AGE"
+	RuleMinKarma      RuleType = "MIN_KARMA"
+)
+
+// Action is what happens to content that triggers a rule, from least to
+// most severe.
+type Action string
+
+const (
+	ActionFlag   Action = "FLAG"
+	ActionHold   Action = "HOLD"
+	ActionReject Action = "REJECT"
+)
+
+func (a Action) severity() int {
+	switch a {
+	case ActionFlag:
+		return 1
+	case ActionHold:
+		return 2
+	case ActionReject:
+		return 3
+	}
+	return 0
+}
+
+type ContentKind string
+
+const (
+	KindPost    ContentKind = "POST"
+	KindComment ContentKind = "COMMENT"
+	KindMessage ContentKind = "MESSAGE"
+)
+
+func (k ContentKind) Noun() string {
+	return strings.ToLower(string(k))
+}
+
+// Rule applies to every group when GroupID is empty. Only the fields of its
+// Type are used.
+type Rule struct {
+	ID      string   `bson:"_id,omitempty"`
+	GroupID string   `bson:"groupId"`
+	Type    RuleType `bson:"type"`
+	Action  Action   `bson:"action"`
+	Enabled bool     `bson:"enabled"`
+
+	Words   []string `bson:"words,omitempty"`   // BANNED_WORDS
+	Pattern string   `bson:"pattern,omitempty"` // REGEX
+
+	// LINK_LIMIT allows at most MaxLinks links from accounts younger than
+	// AccountAgeHours. MIN_ACCOUNT_AGE rejects accounts younger than it.
+	MaxLinks        int `bson:"maxLinks,omitempty"`
+	AccountAgeHours int `bson:"accountAgeHours,omitempty"`
+
+	DuplicateWindowMinutes int `bson:"duplicateWindowMinutes,omitempty"` // DUPLICATE
+	MinKarma               int `bson:"minKarma,omitempty"`               // MIN_KARMA
+
+	CreatedBy string    `bson:"createdBy"`
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// Trigger records a rule firing on a piece of content.
+type Trigger struct {
+	ID        string      `bson:"_id,omitempty"`
+	RuleID    string      `bson:"ruleId"`
+	RuleType  RuleType    `bson:"ruleType"`
+	Action    Action      `bson:"action"`
+	Kind      ContentKind `bson:"kind"`
+	ContentID string      `bson:"contentId,omitempty"` // Empty for rejected content
+	AuthorID  string      `bson:"authorId"`
+	GroupID   string      `bson:"groupId"`
+	Reason    string      `bson:"reason"`
+	Excerpt   string      `bson:"excerpt"`
+	CreatedAt time.Time   `bson:"createdAt"`
+}
+
+type Repository interface {
+	CreateRule(ctx context.Context, rule *Rule) error
+	GetRule(ctx context.Context, id string) (*Rule, error)
+	UpdateRule(ctx context.Context, rule *Rule) error
+	DeleteRule(ctx context.Context, id string) error
+	// ListRules returns the rules of a group, or the global rules for "".
+	ListRules(ctx context.Context, groupID string) ([]*Rule, error)
+	// ActiveRules returns the enabled global and group rules.
+	ActiveRules(ctx context.Context, groupID string) ([]*Rule, error)
+
+	RecordTriggers(ctx context.Context, triggers []*Trigger) error
+	ListTriggers(ctx context.Context, groupIDs []string, limit, offset int) ([]*Trigger, error)
+
+	// AddFingerprint remembers a hash of content the author posted so that
+	// DUPLICATE rules can count repeats.
+	AddFingerprint(ctx context.Context, authorID, hash string) error
+	CountFingerprints(ctx context.Context, authorID, hash string, since time.Time) (int64, error)
+
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	rules        *mongo.Collection
+	triggers     *mongo.Collection
+	fingerprints *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{
+		rules:        db.Collection("automodRules"),
+		triggers:     db.Collection("automodTriggers"),
+		fingerprints: db.Collection("automodFingerprints"),
+	}
+}
+
+func (r *repository) CreateRule(ctx context.Context, rule *Rule) error {
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+	res, err := r.rules.InsertOne(ctx, rule)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		rule.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) GetRule(ctx context.Context, id string) (*Rule, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var rule Rule
+	if err := r.rules.FindOne(ctx, bson.M{"_id": oid}).Decode(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *repository) UpdateRule(ctx context.Context, rule *Rule) error {
+	oid, err := bson.ObjectIDFromHex(rule.ID)
+	if err != nil {
+		return err
+	}
+	rule.UpdatedAt = time.Now()
+	_, err = r.rules.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{
+		"action":                 rule.Action,
+		"enabled":                rule.Enabled,
+		"words":                  rule.Words,
+		"pattern":                rule.Pattern,
+		"maxLinks":               rule.MaxLinks,
+		"accountAgeHours":        rule.AccountAgeHours,
+		"duplicateWindowMinutes": rule.DuplicateWindowMinutes,
+		"updatedAt":              rule.UpdatedAt,
+	}})
+	return err
+}
+
+func (r *repository) DeleteRule(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.rules.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (r *repository) findRules(ctx context.Context, filter bson.M) ([]*Rule, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.rules.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*Rule
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *repository) ListRules(ctx context.Context, groupID string) ([]*Rule, error) {
+	return r.findRules(ctx, bson.M{"groupId": groupID})
+}
+
+func (r *repository) ActiveRules(ctx context.Context, groupID string) ([]*Rule, error) {
+	return r.findRules(ctx, bson.M{
+		"groupId": bson.M{"$in": bson.A{"", groupID}},
+		"enabled": true,
+	})
+}
+
+func (r *repository) RecordTriggers(ctx context.Context, triggers []*Trigger) error {
+	if len(triggers) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(triggers))
+	for _, t := range triggers {
+		docs = append(docs, t)
+	}
+	res, err := r.triggers.InsertMany(ctx, docs)
+	if err != nil {
+		return err
+	}
+	for i, id := range res.InsertedIDs {
+		if oid, ok := id.(bson.ObjectID); ok {
+			triggers[i].ID = oid.Hex()
+		}
+	}
+	return nil
+}
+
+func (r *repository) ListTriggers(ctx context.Context, groupIDs []string, limit, offset int) ([]*Trigger, error) {
+	filter := bson.M{}
+	if groupIDs != nil {
+		filter["groupId"] = bson.M{"$in": groupIDs}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+	cursor, err := r.triggers.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var triggers []*Trigger
+	if err := cursor.All(ctx, &triggers); err != nil {
+		return nil, err
+	}
+	return triggers, nil
+}
+
+func (r *repository) AddFingerprint(ctx context.Context, authorID, hash string) error {
+	_, err := r.fingerprints.InsertOne(ctx, bson.M{
+		"authorId":  authorID,
+		"hash":      hash,
+		"createdAt": time.Now(),
+	})
+	return err
+}
+
+func (r *repository) CountFingerprints(ctx context.Context, authorID, hash string, since time.Time) (int64, error) {
+	return r.fingerprints.CountDocuments(ctx, bson.M{
+		"authorId":  authorID,
+		"hash":      hash,
+		"createdAt": bson.M{"$gte": since},
+	})
+}
+
+// fingerprintTTL bounds the longest duplicate window a rule can use.
+const fingerprintTTL = 7 * 24 * time.Hour
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.rules.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "enabled", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.triggers.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "createdAt", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.fingerprints.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "authorId", Value: 1}, {Key: "hash", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(fingerprintTTL.Seconds())),
+		},
+	})
+	return err
+}
@@ -0,0 +1,240 @@
+package bans
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type AppealStatus string
+
+const (
+	AppealPending  AppealStatus = "PENDING"
+	AppealAccepted AppealStatus = "ACCEPTED"
+	AppealRejected AppealStatus = "REJECTED"
+)
+
+var ErrAppealExists = errors.New("this ban has already been appealed")
+
+type Ban struct {
+	ID          string     `bson:"_id,omitempty"`
+	UserID      string     `bson:"userId"`
+	Reason      string     `bson:"reason"`
+	ModeratorID string     `bson:"moderatorId"`
+	StartsAt    time.Time  `bson:"startsAt"`
+	EndsAt      *time.Time `bson:"endsAt,omitempty"` // nil for permanent bans
+	LiftedAt    *time.Time `bson:"liftedAt,omitempty"`
+	LiftedBy    string     `bson:"liftedBy,omitempty"` // empty when the ban expired
+}
+
+func (b *Ban) Expired(now time.Time) bool {
+	return b.EndsAt != nil && !b.EndsAt.After(now)
+}
+
+func (b *Ban) Active(now time.Time) bool {
+	return b.LiftedAt == nil && !b.Expired(now)
+}
+
+type Appeal struct {
+	ID         string       `bson:"_id,omitempty"`
+	BanID      string       `bson:"banId"`
+	UserID     string       `bson:"userId"`
+	Message    string       `bson:"message"`
+	Status     AppealStatus `bson:"status"`
+	Response   string       `bson:"response,omitempty"`
+	ReviewerID string       `bson:"reviewerId,omitempty"`
+	CreatedAt  time.Time    `bson:"createdAt"`
+	ReviewedAt *time.Time   `bson:"reviewedAt,omitempty"`
+}
+
+type Repository interface {
+	Create(ctx context.Context, ban *Ban) error
+	GetByID(ctx context.Context, id string) (*Ban, error)
+	// Current returns the user's most recent ban that hasn't been lifted.
+	Current(ctx context.Context, userID string) (*Ban, error)
+	ListByUser(ctx context.Context, userID string) ([]*Ban, error)
+	// Lift ends every outstanding ban of the user. liftedBy is empty for
+	// bans that ran out.
+	Lift(ctx context.Context, userID, liftedBy string) error
+
+	CreateAppeal(ctx context.Context, appeal *Appeal) error
+	GetAppeal(ctx context.Context, id string) (*Appeal, error)
+	GetAppealForBan(ctx context.Context, banID string) (*Appeal, error)
+	ListAppeals(ctx context.Context, status *AppealStatus, limit, offset int) ([]*Appeal, error)
+	ReviewAppeal(ctx context.Context, id string, status AppealStatus, reviewerID, response string) (*Appeal, error)
+
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll    *mongo.Collection
+	appeals *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{
+		coll:    db.Collection("bans"),
+		appeals: db.Collection("banAppeals"),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, ban *Ban) error {
+	res, err := r.coll.InsertOne(ctx, ban)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		ban.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id string) (*Ban, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var ban Ban
+	if err := r.coll.FindOne(ctx, bson.M{"_id": oid}).Decode(&ban); err != nil {
+		return nil, err
+	}
+	return &ban, nil
+}
+
+func (r *repository) Current(ctx context.Context, userID string) (*Ban, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "startsAt", Value: -1}})
+	var ban Ban
+	err := r.coll.FindOne(ctx, bson.M{
+		"userId":   userID,
+		"liftedAt": bson.M{"$exists": false},
+	}, opts).Decode(&ban)
+	if err != nil {
+		return nil, err
+	}
+	return &ban, nil
+}
+
+func (r *repository) ListByUser(ctx context.Context, userID string) ([]*Ban, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startsAt", Value: -1}})
+	cursor, err := r.coll.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var bans []*Ban
+	if err := cursor.All(ctx, &bans); err != nil {
+		return nil, err
+	}
+	return bans, nil
+}
+
+func (r *repository) Lift(ctx context.Context, userID, liftedBy string) error {
+	set := bson.M{"liftedAt": time.Now()}
+	if liftedBy != "" {
+		set["liftedBy"] = liftedBy
+	}
+	_, err := r.coll.UpdateMany(ctx, bson.M{
+		"userId":   userID,
+		"liftedAt": bson.M{"$exists": false},
+	}, bson.M{"$set": set})
+	return err
+}
+
+func (r *repository) CreateAppeal(ctx context.Context, appeal *Appeal) error {
+	res, err := r.appeals.InsertOne(ctx, appeal)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrAppealExists
+	}
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		appeal.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) GetAppeal(ctx context.Context, id string) (*Appeal, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var appeal Appeal
+	if err := r.appeals.FindOne(ctx, bson.M{"_id": oid}).Decode(&appeal); err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+func (r *repository) GetAppealForBan(ctx context.Context, banID string) (*Appeal, error) {
+	var appeal Appeal
+	if err := r.appeals.FindOne(ctx, bson.M{"banId": banID}).Decode(&appeal); err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+func (r *repository) ListAppeals(ctx context.Context, status *AppealStatus, limit, offset int) ([]*Appeal, error) {
+	filter := bson.M{}
+	if status != nil {
+		filter["status"] = *status
+	}
+	// Oldest first so the queue is worked in order.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+	cursor, err := r.appeals.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var appeals []*Appeal
+	if err := cursor.All(ctx, &appeals); err != nil {
+		return nil, err
+	}
+	return appeals, nil
+}
+
+func (r *repository) ReviewAppeal(ctx context.Context, id string, status AppealStatus, reviewerID, response string) (*Appeal, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var appeal Appeal
+	err = r.appeals.FindOneAndUpdate(ctx, bson.M{
+		"_id":    oid,
+		"status": AppealPending,
+	}, bson.M{"$set": bson.M{
+		"status":     status,
+		"response":   response,
+		"reviewerId": reviewerID,
+		"reviewedAt": time.Now(),
+	}}, opts).Decode(&appeal)
+	if err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "userId", Value: 1}, {Key: "startsAt", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.appeals.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "banId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+		},
+	})
+	return err
+}
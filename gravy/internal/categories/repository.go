@@ -21,6 +21,7 @@ type Repository interface {
 	List(ctx context.Context) ([]*Category, error)
 	Delete(ctx context.Context, id string) error
 	GetByName(ctx context.Context, name string) (*Category, error)
+	GetByID(ctx context.Context, id string) (*Category, error)
 }
 
 type repository struct {
@@ -81,3 +82,15 @@ func (r *repository) GetByName(ctx context.Context, name string) (*Category, err
 	}
 	return &category, nil
 }
+
+func (r *repository) GetByID(ctx context.Context, id string) (*Category, error) {
+	idObj, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var category Category
+	if err := r.coll.FindOne(ctx, bson.M{"_id": idObj}).Decode(&category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
@@ -0,0 +1,83 @@
+package community
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ListPostsSince returns posts created in any of the groups after since,
+// newest first.
+func (r *repository) ListPostsSince(ctx context.Context, groupIDs []string, since time.Time, limit int) ([]*Post, error) {
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+	filter := bson.M{
+		"groupId":   bson.M{"$in": groupIDs},
+		"createdAt": bson.M{"$gt": since},
+		"held":      notHeld,
+	}
+	opts := options.Find().SetLimit(int64(limit)).SetSort(bson.M{"createdAt": -1})
+	cursor, err := r.db.Collection("posts").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var posts []*Post
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListRepliesTo returns comments by other users made after since that are
+// either top level on the author's posts or replies to the author's
+// comments, newest first.
+func (r *repository) ListRepliesTo(ctx context.Context, authorID string, since time.Time, limit int) ([]*Comment, error) {
+	postIDs, err := r.idsByAuthor(ctx, "posts", authorID)
+	if err != nil {
+		return nil, err
+	}
+	commentIDs, err := r.idsByAuthor(ctx, "comments", authorID)
+	if err != nil {
+		return nil, err
+	}
+	if len(postIDs) == 0 && len(commentIDs) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.M{
+		"authorId":  bson.M{"$ne": authorID},
+		"createdAt": bson.M{"$gt": since},
+		"held":      notHeld,
+		"$or": []bson.M{
+			{"parentId": nil, "postId": bson.M{"$in": postIDs}},
+			{"parentId": bson.M{"$in": commentIDs}},
+		},
+	}
+	opts := options.Find().SetLimit(int64(limit)).SetSort(bson.M{"createdAt": -1})
+	cursor, err := r.db.Collection("comments").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var comments []*Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// idsByAuthor returns the hex ids of the author's documents in coll, which is
+// how other documents refer to them.
+func (r *repository) idsByAuthor(ctx context.Context, coll, authorID string) ([]string, error) {
+	var oids []bson.ObjectID
+	if err := r.db.Collection(coll).Distinct(ctx, "_id", bson.M{"authorId": authorID}).Decode(&oids); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(oids))
+	for _, oid := range oids {
+		ids = append(ids, oid.Hex())
+	}
+	return ids, nil
+}
@@ -18,6 +18,7 @@ type Group struct {
 	Slug           string    `bson:"slug"`
 	Type           GroupType `bson:"type"`
 	OwnerID        string    `bson:"ownerId"`
+	ModeratorIDs   []string  `bson:"moderatorIds,omitempty"`
 	MembersCount   int       `bson:"membersCount"`
 	CreatedAt      time.Time `bson:"createdAt"`
 	MemberIDs      []string  `bson:"memberIds"`
@@ -25,25 +26,38 @@ type Group struct {
 	Icon           string    `bson:"icon,omitempty"`
 	InviteToken    string    `bson:"inviteToken,omitempty"`
 	JoinRequestIDs []string  `bson:"joinRequestIds,omitempty"`
+	// Karma members need to post or comment. Owners and moderators are exempt.
+	MinPostKarma    int `bson:"minPostKarma,omitempty"`
+	MinCommentKarma int `bson:"minCommentKarma,omitempty"`
 }
 
 type GroupFilter struct {
 	OwnerID *string
-	Type    *GroupType
+	// ManagerID matches groups the user owns or moderates.
+	ManagerID *string
+	Type      *GroupType
 }
 
 type Post struct {
-	ID             string    `bson:"_id,omitempty"`
-	Title          string    `bson:"title"`
-	Content        string    `bson:"content"`
-	AuthorID       string    `bson:"authorId"`
-	GroupID        string    `bson:"groupId"`
-	CommentsCount  int       `bson:"commentsCount"`
-	UpvotesCount   int       `bson:"upvotesCount"`
-	DownvotesCount int       `bson:"downvotesCount"`
-	Indexed        bool      `bson:"indexed"`
-	IsEdited       bool      `bson:"isEdited"`
-	CreatedAt      time.Time `bson:"createdAt"`
+	ID             string     `bson:"_id,omitempty"`
+	Title          string     `bson:"title"`
+	Content        string     `bson:"content"`
+	AuthorID       string     `bson:"authorId"`
+	GroupID        string     `bson:"groupId"`
+	CommentsCount  int        `bson:"commentsCount"`
+	UpvotesCount   int        `bson:"upvotesCount"`
+	DownvotesCount int        `bson:"downvotesCount"`
+	Indexed        bool       `bson:"indexed"`
+	IsEdited       bool       `bson:"isEdited"`
+	Held           bool       `bson:"held,omitempty"` // Waiting for automod review
+	Pinned         bool       `bson:"pinned,omitempty"`
+	PinnedAt       *time.Time `bson:"pinnedAt,omitempty"`
+	Locked         bool       `bson:"locked,omitempty"` // No new comments or votes
+	Announcement   bool       `bson:"announcement,omitempty"`
+	Score          int        `bson:"score"` // Upvotes minus downvotes
+	HotScore       float64    `bson:"hotScore"`
+	Controversy    float64    `bson:"controversy"`
+	CreatedAt      time.Time  `bson:"createdAt"`
 }
 
 type Comment struct {
@@ -57,6 +71,10 @@ type Comment struct {
 	RepliesCount   int       `bson:"repliesCount"`
 	Indexed        bool      `bson:"indexed"`
 	IsEdited       bool      `bson:"isEdited"`
+	Held           bool      `bson:"held,omitempty"`
+	Score          int       `bson:"score"`
+	WilsonScore    float64   `bson:"wilsonScore"`
+	Controversy    float64   `bson:"controversy"`
 	CreatedAt      time.Time `bson:"createdAt"`
 }
 
@@ -100,5 +118,6 @@ type Message struct {
 	ChannelID string    `bson:"channelId"`
 	SenderID  string    `bson:"senderId"`
 	Content   string    `bson:"content"`
+	Held      bool      `bson:"held,omitempty"`
 	CreatedAt time.Time `bson:"createdAt"`
 }
@@ -0,0 +1,281 @@
+package community
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type SortOrder string
+
+const (
+	SortNew           SortOrder = "NEW"
+	SortHot           SortOrder = "HOT" // Wilson score for comments
+	SortTop           SortOrder = "TOP"
+	SortControversial SortOrder = "CONTROVERSIAL"
+)
+
+type TimeWindow string
+
+const (
+	WindowDay   TimeWindow = "DAY"
+	WindowWeek  TimeWindow = "WEEK"
+	WindowMonth TimeWindow = "MONTH"
+	WindowAll   TimeWindow = "ALL"
+)
+
+// Since returns the start of the window, or the zero time for WindowAll.
+func (w TimeWindow) Since(now time.Time) time.Time {
+	switch w {
+	case WindowDay:
+		return now.AddDate(0, 0, -1)
+	case WindowWeek:
+		return now.AddDate(0, 0, -7)
+	case WindowMonth:
+		return now.AddDate(0, -1, 0)
+	}
+	return time.Time{}
+}
+
+// Sort orders a post or comment listing. Window only narrows TOP and
+// CONTROVERSIAL.
+type Sort struct {
+	Order  SortOrder
+	Window TimeWindow
+}
+
+var SortByNew = Sort{Order: SortNew}
+
+const (
+	// hotGravity controls how quickly posts fall off the hot feed.
+	hotGravity = 1.8
+	// HotScoreWindow is how long hot scores keep being recomputed. Past it
+	// they are close enough to zero that the ordering no longer changes.
+	HotScoreWindow = 14 * 24 * time.Hour
+)
+
+// HotScore ranks posts by net votes, decaying with age.
+func HotScore(up, down int, createdAt, now time.Time) float64 {
+	ageHours := now.Sub(createdAt).Hours()
+	if ageHours < 0 {
+		ageHours = 0
+	}
+	return float64(up-down) / math.Pow(ageHours+2, hotGravity)
+}
+
+// ControversyScore is high for content with many votes split evenly.
+func ControversyScore(up, down int) float64 {
+	if up <= 0 || down <= 0 {
+		return 0
+	}
+	magnitude := float64(up + down)
+	balance := float64(down) / float64(up)
+	if up < down {
+		balance = float64(up) / float64(down)
+	}
+	return math.Pow(magnitude, balance)
+}
+
+// WilsonScore is the lower bound of the 95% confidence interval for the
+// share of upvotes, so a comment at 10/0 ranks above one at 1/0.
+func WilsonScore(up, down int) float64 {
+	n := float64(up + down)
+	if n == 0 {
+		return 0
+	}
+	const z = 1.96
+	p := float64(up) / n
+	return (p + z*z/(2*n) - z*math.Sqrt((p*(1-p)+z*z/(4*n))/n)) / (1 + z*z/n)
+}
+
+func postScores(p *Post, now time.Time) bson.M {
+	return bson.M{
+		"score":       p.UpvotesCount - p.DownvotesCount,
+		"hotScore":    HotScore(p.UpvotesCount, p.DownvotesCount, p.CreatedAt, now),
+		"controversy": ControversyScore(p.UpvotesCount, p.DownvotesCount),
+	}
+}
+
+func commentScores(c *Comment) bson.M {
+	return bson.M{
+		"score":       c.UpvotesCount - c.DownvotesCount,
+		"wilsonScore": WilsonScore(c.UpvotesCount, c.DownvotesCount),
+		"controversy": ControversyScore(c.UpvotesCount, c.DownvotesCount),
+	}
+}
+
+// applySort adds the sort and any time window of s to a listing query.
+func applySort(filter bson.M, s Sort, scoreField string, now time.Time) bson.D {
+	switch s.Order {
+	case SortHot:
+		return bson.D{{Key: scoreField, Value: -1}, {Key: "createdAt", Value: -1}}
+	case SortTop, SortControversial:
+		if since := s.Window.Since(now); !since.IsZero() {
+			filter["createdAt"] = bson.M{"$gte": since}
+		}
+		field := "score"
+		if s.Order == SortControversial {
+			field = "controversy"
+		}
+		return bson.D{{Key: field, Value: -1}, {Key: "createdAt", Value: -1}}
+	}
+	return bson.D{{Key: "createdAt", Value: -1}}
+}
+
+// The expressions below compute the same scores as the functions above inside
+// an update pipeline, from the counters of the document being updated, so the
+// scores can be written in the same atomic update as the counters.
+
+var (
+	upExpr   = bson.M{"$ifNull": bson.A{"$upvotesCount", 0}}
+	downExpr = bson.M{"$ifNull": bson.A{"$downvotesCount", 0}}
+)
+
+func controversyExpr() bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$or": bson.A{bson.M{"$lte": bson.A{upExpr, 0}}, bson.M{"$lte": bson.A{downExpr, 0}}}},
+		0.0,
+		bson.M{"$pow": bson.A{
+			bson.M{"$add": bson.A{upExpr, downExpr}},
+			bson.M{"$divide": bson.A{bson.M{"$min": bson.A{upExpr, downExpr}}, bson.M{"$max": bson.A{upExpr, downExpr}}}},
+		}},
+	}}
+}
+
+func postScoresExpr(now time.Time) bson.D {
+	ageHours := bson.M{"$max": bson.A{0, bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{now, "$createdAt"}}, float64(time.Hour / time.Millisecond),
+	}}}}
+	return bson.D{
+		{Key: "score", Value: bson.M{"$subtract": bson.A{upExpr, downExpr}}},
+		{Key: "hotScore", Value: bson.M{"$divide": bson.A{
+			bson.M{"$subtract": bson.A{upExpr, downExpr}},
+			bson.M{"$pow": bson.A{bson.M{"$add": bson.A{ageHours, 2}}, hotGravity}},
+		}}},
+		{Key: "controversy", Value: controversyExpr()},
+	}
+}
+
+func commentScoresExpr() bson.D {
+	const z = 1.96
+	n := bson.M{"$add": bson.A{upExpr, downExpr}}
+	p := bson.M{"$divide": bson.A{upExpr, n}}
+	wilson := bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{
+			bson.M{"$add": bson.A{p, bson.M{"$divide": bson.A{z * z / 2, n}}}},
+			bson.M{"$multiply": bson.A{z, bson.M{"$sqrt": bson.M{"$divide": bson.A{
+				bson.M{"$add": bson.A{
+					bson.M{"$multiply": bson.A{p, bson.M{"$subtract": bson.A{1, p}}}},
+					bson.M{"$divide": bson.A{z * z / 4, n}},
+				}},
+				n,
+			}}}}},
+		}},
+		bson.M{"$add": bson.A{1, bson.M{"$divide": bson.A{z * z, n}}}},
+	}}
+	return bson.D{
+		{Key: "score", Value: bson.M{"$subtract": bson.A{upExpr, downExpr}}},
+		{Key: "wilsonScore", Value: bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{n, 0}}, 0.0, wilson}}},
+		{Key: "controversy", Value: controversyExpr()},
+	}
+}
+
+// scoreDrift returns the score fields whose stored value differs from the
+// recomputed one by more than rounding.
+func scoreDrift(values map[string][2]float64) []string {
+	var drift []string
+	for field, v := range values {
+		if math.Abs(v[0]-v[1]) > 1e-9*math.Max(1, math.Abs(v[1])) {
+			drift = append(drift, field)
+		}
+	}
+	return drift
+}
+
+const rescoreBatchSize = 500
+
+// decodeRaw decodes a document read straight off a cursor the way the client
+// would, with ObjectIDs decoded into string IDs.
+func decodeRaw(raw bson.Raw, v interface{}) error {
+	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(raw)))
+	dec.ObjectIDAsHexString()
+	return dec.Decode(v)
+}
+
+// RecomputeScores refreshes the hot score of recent posts and fills in the
+// scores of any post or comment that doesn't have them yet.
+func (r *repository) RecomputeScores(ctx context.Context) (int, error) {
+	now := time.Now()
+	posts, err := r.rescore(ctx, r.db.Collection("posts"), bson.M{"$or": bson.A{
+		bson.M{"createdAt": bson.M{"$gte": now.Add(-HotScoreWindow)}},
+		bson.M{"hotScore": bson.M{"$exists": false}},
+	}}, func(raw bson.Raw) (bson.M, error) {
+		var p Post
+		if err := decodeRaw(raw, &p); err != nil {
+			return nil, err
+		}
+		return postScores(&p, now), nil
+	})
+	if err != nil {
+		return posts, err
+	}
+	comments, err := r.rescore(ctx, r.db.Collection("comments"), bson.M{
+		"wilsonScore": bson.M{"$exists": false},
+	}, func(raw bson.Raw) (bson.M, error) {
+		var c Comment
+		if err := decodeRaw(raw, &c); err != nil {
+			return nil, err
+		}
+		return commentScores(&c), nil
+	})
+	return posts + comments, err
+}
+
+func (r *repository) rescore(ctx context.Context, coll *mongo.Collection, filter bson.M, scores func(bson.Raw) (bson.M, error)) (int, error) {
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{
+		"upvotesCount":   1,
+		"downvotesCount": 1,
+		"createdAt":      1,
+	}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	var models []mongo.WriteModel
+	flush := func() error {
+		if len(models) == 0 {
+			return nil
+		}
+		if _, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+		updated += len(models)
+		models = models[:0]
+		return nil
+	}
+	for cursor.Next(ctx) {
+		set, err := scores(cursor.Current)
+		if err != nil {
+			return updated, err
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": cursor.Current.Lookup("_id")}).
+			SetUpdate(bson.M{"$set": set}))
+		if len(models) == rescoreBatchSize {
+			if err := flush(); err != nil {
+				return updated, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return updated, err
+	}
+	return updated, flush()
+}
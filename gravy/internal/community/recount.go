@@ -0,0 +1,262 @@
+package community
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// RecountReport lists how many documents had each counter out of sync.
+// Drift is keyed by "collection.field".
+type RecountReport struct {
+	Scanned map[string]int
+	Drift   map[string]int
+}
+
+func (rep *RecountReport) Fixed() int {
+	n := 0
+	for _, d := range rep.Drift {
+		n += d
+	}
+	return n
+}
+
+type voteTally struct {
+	Up   int
+	Down int
+}
+
+func (r *repository) RecountCounters(ctx context.Context, dryRun bool) (*RecountReport, error) {
+	rep := &RecountReport{Scanned: map[string]int{}, Drift: map[string]int{}}
+	if err := r.recountPosts(ctx, rep, dryRun); err != nil {
+		return rep, err
+	}
+	if err := r.recountComments(ctx, rep, dryRun); err != nil {
+		return rep, err
+	}
+	if err := r.recountGroups(ctx, rep, dryRun); err != nil {
+		return rep, err
+	}
+	return rep, nil
+}
+
+func (r *repository) recountPosts(ctx context.Context, rep *RecountReport, dryRun bool) error {
+	votes, err := r.tallyVotes(ctx, r.db.Collection("votes"), "postId")
+	if err != nil {
+		return err
+	}
+	comments, err := r.countBy(ctx, r.db.Collection("comments"), "postId")
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return r.reconcile(ctx, rep, dryRun, r.db.Collection("posts"), bson.M{
+		"upvotesCount":   1,
+		"downvotesCount": 1,
+		"commentsCount":  1,
+		"createdAt":      1,
+		"score":          1,
+		"controversy":    1,
+	}, func(raw bson.Raw, id string) (bson.M, []string, error) {
+		var p Post
+		if err := decodeRaw(raw, &p); err != nil {
+			return nil, nil, err
+		}
+		drift := recountDrift(map[string][2]int{
+			"upvotesCount":   {p.UpvotesCount, votes[id].Up},
+			"downvotesCount": {p.DownvotesCount, votes[id].Down},
+			"commentsCount":  {p.CommentsCount, comments[id]},
+			"score":          {p.Score, votes[id].Up - votes[id].Down},
+		})
+		// The hot score decays over time and is refreshed by
+		// RecomputeScores, so only the stable scores are compared.
+		drift = append(drift, scoreDrift(map[string][2]float64{
+			"controversy": {p.Controversy, ControversyScore(votes[id].Up, votes[id].Down)},
+		})...)
+		if len(drift) == 0 {
+			return nil, nil, nil
+		}
+		p.UpvotesCount, p.DownvotesCount, p.CommentsCount = votes[id].Up, votes[id].Down, comments[id]
+		set := postScores(&p, now)
+		set["upvotesCount"] = p.UpvotesCount
+		set["downvotesCount"] = p.DownvotesCount
+		set["commentsCount"] = p.CommentsCount
+		return set, drift, nil
+	})
+}
+
+func (r *repository) recountComments(ctx context.Context, rep *RecountReport, dryRun bool) error {
+	votes, err := r.tallyVotes(ctx, r.db.Collection("commentVotes"), "commentId")
+	if err != nil {
+		return err
+	}
+	replies, err := r.countBy(ctx, r.db.Collection("comments"), "parentId")
+	if err != nil {
+		return err
+	}
+	return r.reconcile(ctx, rep, dryRun, r.db.Collection("comments"), bson.M{
+		"upvotesCount":   1,
+		"downvotesCount": 1,
+		"repliesCount":   1,
+		"score":          1,
+		"wilsonScore":    1,
+		"controversy":    1,
+	}, func(raw bson.Raw, id string) (bson.M, []string, error) {
+		var c Comment
+		if err := decodeRaw(raw, &c); err != nil {
+			return nil, nil, err
+		}
+		drift := recountDrift(map[string][2]int{
+			"upvotesCount":   {c.UpvotesCount, votes[id].Up},
+			"downvotesCount": {c.DownvotesCount, votes[id].Down},
+			"repliesCount":   {c.RepliesCount, replies[id]},
+			"score":          {c.Score, votes[id].Up - votes[id].Down},
+		})
+		drift = append(drift, scoreDrift(map[string][2]float64{
+			"wilsonScore": {c.WilsonScore, WilsonScore(votes[id].Up, votes[id].Down)},
+			"controversy": {c.Controversy, ControversyScore(votes[id].Up, votes[id].Down)},
+		})...)
+		if len(drift) == 0 {
+			return nil, nil, nil
+		}
+		c.UpvotesCount, c.DownvotesCount, c.RepliesCount = votes[id].Up, votes[id].Down, replies[id]
+		set := commentScores(&c)
+		set["upvotesCount"] = c.UpvotesCount
+		set["downvotesCount"] = c.DownvotesCount
+		set["repliesCount"] = c.RepliesCount
+		return set, drift, nil
+	})
+}
+
+func (r *repository) recountGroups(ctx context.Context, rep *RecountReport, dryRun bool) error {
+	return r.reconcile(ctx, rep, dryRun, r.db.Collection("groups"), bson.M{
+		"membersCount": 1,
+		"memberIds":    1,
+	}, func(raw bson.Raw, id string) (bson.M, []string, error) {
+		var g Group
+		if err := decodeRaw(raw, &g); err != nil {
+			return nil, nil, err
+		}
+		if g.MembersCount == len(g.MemberIDs) {
+			return nil, nil, nil
+		}
+		return bson.M{"membersCount": len(g.MemberIDs)}, []string{"membersCount"}, nil
+	})
+}
+
+// recountDrift returns the fields whose stored value differs from the
+// recounted one.
+func recountDrift(values map[string][2]int) []string {
+	var drift []string
+	for field, v := range values {
+		if v[0] != v[1] {
+			drift = append(drift, field)
+		}
+	}
+	return drift
+}
+
+// tallyVotes counts up and down votes per target.
+func (r *repository) tallyVotes(ctx context.Context, coll *mongo.Collection, targetField string) (map[string]voteTally, error) {
+	cursor, err := coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":  "$" + targetField,
+			"up":   bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$type", VoteUp}}, 1, 0}}},
+			"down": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$type", VoteDown}}, 1, 0}}},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		ID   string `bson:"_id"`
+		Up   int    `bson:"up"`
+		Down int    `bson:"down"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	tallies := make(map[string]voteTally, len(rows))
+	for _, row := range rows {
+		tallies[row.ID] = voteTally{Up: row.Up, Down: row.Down}
+	}
+	return tallies, nil
+}
+
+// countBy counts the documents of coll sharing each value of field.
+func (r *repository) countBy(ctx context.Context, coll *mongo.Collection, field string) (map[string]int, error) {
+	cursor, err := coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{field: bson.M{"$type": "string"}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + field, "n": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		ID string `bson:"_id"`
+		N  int    `bson:"n"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.ID] = row.N
+	}
+	return counts, nil
+}
+
+// reconcile runs check over every document of coll and writes back the
+// corrections it returns, unless dryRun is set.
+func (r *repository) reconcile(ctx context.Context, rep *RecountReport, dryRun bool, coll *mongo.Collection, projection bson.M, check func(raw bson.Raw, id string) (bson.M, []string, error)) error {
+	cursor, err := coll.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var models []mongo.WriteModel
+	flush := func() error {
+		if len(models) == 0 || dryRun {
+			models = models[:0]
+			return nil
+		}
+		if _, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+		models = models[:0]
+		return nil
+	}
+	for cursor.Next(ctx) {
+		rep.Scanned[coll.Name()]++
+		oid, ok := cursor.Current.Lookup("_id").ObjectIDOK()
+		if !ok {
+			continue
+		}
+		set, drift, err := check(cursor.Current, oid.Hex())
+		if err != nil {
+			return err
+		}
+		if set == nil {
+			continue
+		}
+		for _, field := range drift {
+			rep.Drift[coll.Name()+"."+field]++
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": oid}).
+			SetUpdate(bson.M{"$set": set}))
+		if len(models) == rescoreBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
@@ -11,9 +11,15 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
+	"github.com/pranava-mohan/wikinitt/gravy/internal/karma"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/search"
 )
 
+// notHeld matches content that isn't waiting for automod review. Older
+// documents have no held field. Listing the values, rather than using $ne,
+// gives point index bounds, so feed indexes on held can still provide the sort.
+var notHeld = bson.M{"$in": bson.A{false, nil}}
+
 type Repository interface {
 	CreateGroup(ctx context.Context, group *Group) error
 	GetGroup(ctx context.Context, slug string) (*Group, error)
@@ -26,26 +32,36 @@ type Repository interface {
 	ListGroupsByMember(ctx context.Context, userID string) ([]*Group, error)
 	ListPublicGroupsByMember(ctx context.Context, userID string) ([]*Group, error)
 	UpdateGroup(ctx context.Context, groupID string, name *string, description *string, icon *string) (*Group, error)
+	SetKarmaRequirements(ctx context.Context, groupID string, minPostKarma, minCommentKarma *int) error
 
 	CreatePost(ctx context.Context, post *Post) error
 	GetPost(ctx context.Context, id string) (*Post, error)
 	GetPostsByIDs(ctx context.Context, ids []string) ([]*Post, error)
 	GetGroupsByIDs(ctx context.Context, ids []string) ([]*Group, error)
 	GetCommentsByIDs(ctx context.Context, ids []string) ([]*Comment, error)
-	ListPosts(ctx context.Context, groupID string, limit, offset int) ([]*Post, error)
-	ListPublicPosts(ctx context.Context, limit, offset int) ([]*Post, error)
+	ListPosts(ctx context.Context, groupID string, sort Sort, limit, offset int) ([]*Post, error)
+	ListPublicPosts(ctx context.Context, sort Sort, limit, offset int) ([]*Post, error)
 	ListPostsByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*Post, error)
 	ListPublicPostsByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*Post, error)
-	UpdatePost(ctx context.Context, postID string, title *string, content *string) (*Post, error)
+	ListPostsSince(ctx context.Context, groupIDs []string, since time.Time, limit int) ([]*Post, error)
+	// UpdatePost edits a post, holding it for review when hold is set.
+	UpdatePost(ctx context.Context, postID string, title *string, content *string, hold bool) (*Post, error)
+	PinPost(ctx context.Context, postID string, pinned bool) error
+	CountPinnedPosts(ctx context.Context, groupID string) (int64, error)
+	LockPost(ctx context.Context, postID string, locked bool) error
+	SetPostAnnouncement(ctx context.Context, postID string, announcement bool) error
 	DeletePost(ctx context.Context, postID string) error
 
 	CreateComment(ctx context.Context, comment *Comment) error
 	GetComment(ctx context.Context, id string) (*Comment, error)
-	ListComments(ctx context.Context, postID string, parentID *string, limit, offset int) ([]*Comment, error)
+	ListComments(ctx context.Context, postID string, parentID *string, sort Sort, limit, offset int) ([]*Comment, error)
 	ListReplies(ctx context.Context, parentID string, limit, offset int) ([]*Comment, error)
 	ListCommentsByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*Comment, error)
 	ListPublicCommentsByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*Comment, error)
-	UpdateComment(ctx context.Context, commentID string, content string) (*Comment, error)
+	// ListRepliesTo returns other users' comments on the author's posts and
+	// replies to the author's comments made after since.
+	ListRepliesTo(ctx context.Context, authorID string, since time.Time, limit int) ([]*Comment, error)
+	UpdateComment(ctx context.Context, commentID string, content string, hold bool) (*Comment, error)
 	DeleteComment(ctx context.Context, commentID string) error
 
 	VotePost(ctx context.Context, userID, postID string, voteType string) error
@@ -63,6 +79,12 @@ type Repository interface {
 
 	CreateMessage(ctx context.Context, message *Message) error
 	ListMessages(ctx context.Context, channelID string, limit, offset int) ([]*Message, error)
+	GetMessage(ctx context.Context, id string) (*Message, error)
+	DeleteMessage(ctx context.Context, id string) error
+
+	ReleasePost(ctx context.Context, id string) error
+	ReleaseComment(ctx context.Context, id string) error
+	ReleaseMessage(ctx context.Context, id string) error
 
 	ListUnindexedGroups(ctx context.Context, limit int) ([]*Group, error)
 	MarkGroupIndexed(ctx context.Context, id string) error
@@ -71,23 +93,45 @@ type Repository interface {
 	ListUnindexedComments(ctx context.Context, limit int) ([]*Comment, error)
 	MarkCommentIndexed(ctx context.Context, id string) error
 	EnsureIndexes(ctx context.Context) error
+	// RecomputeScores refreshes decaying hot scores and backfills missing
+	// ranking scores, returning how many documents it updated.
+	RecomputeScores(ctx context.Context) (int, error)
+	// RecountCounters rebuilds denormalized counters from their source
+	// collections. With dryRun set it only reports the drift.
+	RecountCounters(ctx context.Context, dryRun bool) (*RecountReport, error)
 
 	GenerateInviteToken(ctx context.Context, groupID string) (string, error)
 	GetGroupByInviteToken(ctx context.Context, token string) (*Group, error)
 	AddJoinRequest(ctx context.Context, groupID, userID string) error
 	RemoveJoinRequest(ctx context.Context, groupID, userID string) error
 	RemoveMember(ctx context.Context, groupID, userID string) error
+
+	AddModerator(ctx context.Context, groupID, userID string) error
+	RemoveModerator(ctx context.Context, groupID, userID string) error
+	// TransferOwnership hands the group to a member, keeping the previous
+	// owner on as a moderator.
+	TransferOwnership(ctx context.Context, groupID, fromID, toID string) (*Group, error)
+
+	AddSanction(ctx context.Context, sanction *GroupSanction) error
+	// ActiveSanction returns nil when the user has no ban or mute of that
+	// type in force.
+	ActiveSanction(ctx context.Context, groupID, userID string, sanctionType SanctionType) (*GroupSanction, error)
+	LiftSanction(ctx context.Context, groupID, userID string, sanctionType SanctionType, liftedBy string) error
+	ListSanctions(ctx context.Context, groupID string, sanctionType *SanctionType, limit, offset int) ([]*GroupSanction, error)
 }
 
 type repository struct {
 	db           *mongo.Database
 	searchClient *search.Client
+	karma        karma.Repository
 }
 
-func NewRepository(db *mongo.Database, searchClient *search.Client) Repository {
+// NewRepository keeps author karma in step with votes when karmaRepo is set.
+func NewRepository(db *mongo.Database, searchClient *search.Client, karmaRepo karma.Repository) Repository {
 	return &repository{
 		db:           db,
 		searchClient: searchClient,
+		karma:        karmaRepo,
 	}
 }
 
@@ -147,6 +191,12 @@ func (r *repository) ListGroups(ctx context.Context, filter GroupFilter, limit,
 	if filter.OwnerID != nil {
 		query["ownerId"] = *filter.OwnerID
 	}
+	if filter.ManagerID != nil {
+		query["$or"] = bson.A{
+			bson.M{"ownerId": *filter.ManagerID},
+			bson.M{"moderatorIds": *filter.ManagerID},
+		}
+	}
 	if filter.Type != nil {
 		query["type"] = *filter.Type
 	}
@@ -202,7 +252,7 @@ func (r *repository) LeaveGroup(ctx context.Context, groupID, userID string) err
 	}
 
 	_, err = r.db.Collection("groups").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
-		"$pull": bson.M{"memberIds": userID},
+		"$pull": bson.M{"memberIds": userID, "moderatorIds": userID},
 		"$inc":  bson.M{"membersCount": -1},
 	})
 	if err != nil {
@@ -242,6 +292,25 @@ func (r *repository) IsMember(ctx context.Context, groupID, userID string) (bool
 	return count > 0, nil
 }
 
+func (r *repository) SetKarmaRequirements(ctx context.Context, groupID string, minPostKarma, minCommentKarma *int) error {
+	oid, err := bson.ObjectIDFromHex(groupID)
+	if err != nil {
+		return err
+	}
+	update := bson.M{}
+	if minPostKarma != nil {
+		update["minPostKarma"] = *minPostKarma
+	}
+	if minCommentKarma != nil {
+		update["minCommentKarma"] = *minCommentKarma
+	}
+	if len(update) == 0 {
+		return nil
+	}
+	_, err = r.db.Collection("groups").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update})
+	return err
+}
+
 func (r *repository) UpdateGroup(ctx context.Context, groupID string, name *string, description *string, icon *string) (*Group, error) {
 	oid, err := bson.ObjectIDFromHex(groupID)
 	if err != nil {
@@ -397,24 +466,50 @@ func (r *repository) CreatePost(ctx context.Context, post *Post) error {
 		post.ID = oid.Hex()
 	}
 
+	// Held posts are indexed once a moderator releases them.
+	if !post.Held {
+		r.indexPost(ctx, post)
+	}
+
+	return nil
+}
+
+func (r *repository) indexPost(ctx context.Context, post *Post) {
 	group, err := r.GetGroupByID(ctx, post.GroupID)
-	if err == nil {
-		doc := map[string]interface{}{
-			"id":         post.ID,
-			"type":       "post",
-			"group_id":   post.GroupID,
-			"group_type": string(group.Type),
-			"title":      post.Title,
-			"content":    post.Content,
-			"authorId":   post.AuthorID,
-			"createdAt":  post.CreatedAt.Unix(),
-		}
-		if err := r.searchClient.IndexPost(ctx, doc); err == nil {
-			_, _ = r.db.Collection("posts").UpdateOne(ctx, bson.M{"_id": res.InsertedID}, bson.M{"$set": bson.M{"indexed": true}})
-			post.Indexed = true
-		}
+	if err != nil {
+		return
+	}
+	doc := map[string]interface{}{
+		"id":         post.ID,
+		"type":       "post",
+		"group_id":   post.GroupID,
+		"group_type": string(group.Type),
+		"title":      post.Title,
+		"content":    post.Content,
+		"authorId":   post.AuthorID,
+		"createdAt":  post.CreatedAt.Unix(),
+	}
+	if err := r.searchClient.IndexPost(ctx, doc); err == nil {
+		_ = r.MarkPostIndexed(ctx, post.ID)
+		post.Indexed = true
 	}
+}
 
+func (r *repository) ReleasePost(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	var post Post
+	err = r.db.Collection("posts").FindOneAndUpdate(ctx,
+		bson.M{"_id": oid},
+		bson.M{"$set": bson.M{"held": false}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&post)
+	if err != nil {
+		return err
+	}
+	r.indexPost(ctx, &post)
 	return nil
 }
 
@@ -553,9 +648,12 @@ func (r *repository) GetCommentsByIDs(ctx context.Context, ids []string) ([]*Com
 	return final, nil
 }
 
-func (r *repository) ListPosts(ctx context.Context, groupID string, limit, offset int) ([]*Post, error) {
-	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(bson.M{"createdAt": -1})
-	cursor, err := r.db.Collection("posts").Find(ctx, bson.M{"groupId": groupID}, opts)
+func (r *repository) ListPosts(ctx context.Context, groupID string, sort Sort, limit, offset int) ([]*Post, error) {
+	filter := bson.M{"groupId": groupID, "held": notHeld}
+	// Pinned posts first, most recently pinned on top.
+	order := append(bson.D{{Key: "pinned", Value: -1}, {Key: "pinnedAt", Value: -1}}, applySort(filter, sort, "hotScore", time.Now())...)
+	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(order)
+	cursor, err := r.db.Collection("posts").Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -566,7 +664,7 @@ func (r *repository) ListPosts(ctx context.Context, groupID string, limit, offse
 	return posts, nil
 }
 
-func (r *repository) ListPublicPosts(ctx context.Context, limit, offset int) ([]*Post, error) {
+func (r *repository) ListPublicPosts(ctx context.Context, sort Sort, limit, offset int) ([]*Post, error) {
 
 	pipeline := mongo.Pipeline{
 
@@ -602,8 +700,9 @@ func (r *repository) ListPublicPosts(ctx context.Context, limit, offset int) ([]
 		return []*Post{}, nil
 	}
 
-	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(bson.M{"createdAt": -1})
-	cursor, err := r.db.Collection("posts").Find(ctx, bson.M{"groupId": bson.M{"$in": publicGroupIDs}}, opts)
+	filter := bson.M{"groupId": bson.M{"$in": publicGroupIDs}, "held": notHeld}
+	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(applySort(filter, sort, "hotScore", time.Now()))
+	cursor, err := r.db.Collection("posts").Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -616,7 +715,7 @@ func (r *repository) ListPublicPosts(ctx context.Context, limit, offset int) ([]
 
 func (r *repository) ListPostsByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*Post, error) {
 	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(bson.M{"createdAt": -1})
-	cursor, err := r.db.Collection("posts").Find(ctx, bson.M{"authorId": authorID}, opts)
+	cursor, err := r.db.Collection("posts").Find(ctx, bson.M{"authorId": authorID, "held": notHeld}, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -629,7 +728,7 @@ func (r *repository) ListPostsByAuthor(ctx context.Context, authorID string, lim
 
 func (r *repository) ListPublicPostsByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*Post, error) {
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{"authorId": authorID}}},
+		{{Key: "$match", Value: bson.M{"authorId": authorID, "held": notHeld}}},
 		{{Key: "$addFields", Value: bson.M{"groupIdObj": bson.M{"$toObjectId": "$groupId"}}}},
 		{{Key: "$lookup", Value: bson.M{
 			"from":         "groups",
@@ -674,27 +773,53 @@ func (r *repository) CreateComment(ctx context.Context, comment *Comment) error
 		}
 	}
 
+	if !comment.Held {
+		r.indexComment(ctx, comment)
+	}
+	return nil
+}
+
+func (r *repository) indexComment(ctx context.Context, comment *Comment) {
 	post, err := r.GetPost(ctx, comment.PostID)
-	if err == nil {
-		group, err := r.GetGroupByID(ctx, post.GroupID)
-		if err == nil {
-			doc := map[string]interface{}{
-				"id":         comment.ID,
-				"type":       "comment",
-				"group_id":   post.GroupID,
-				"group_type": string(group.Type),
-				"content":    comment.Content,
-				"authorId":   comment.AuthorID,
-				"postId":     comment.PostID,
-				"parentId":   comment.ParentID,
-				"createdAt":  comment.CreatedAt.Unix(),
-			}
-			if err := r.searchClient.IndexComment(ctx, doc); err == nil {
-				_, _ = r.db.Collection("comments").UpdateOne(ctx, bson.M{"_id": res.InsertedID}, bson.M{"$set": bson.M{"indexed": true}})
-				comment.Indexed = true
-			}
-		}
+	if err != nil {
+		return
+	}
+	group, err := r.GetGroupByID(ctx, post.GroupID)
+	if err != nil {
+		return
+	}
+	doc := map[string]interface{}{
+		"id":         comment.ID,
+		"type":       "comment",
+		"group_id":   post.GroupID,
+		"group_type": string(group.Type),
+		"content":    comment.Content,
+		"authorId":   comment.AuthorID,
+		"postId":     comment.PostID,
+		"parentId":   comment.ParentID,
+		"createdAt":  comment.CreatedAt.Unix(),
+	}
+	if err := r.searchClient.IndexComment(ctx, doc); err == nil {
+		_ = r.MarkCommentIndexed(ctx, comment.ID)
+		comment.Indexed = true
 	}
+}
+
+func (r *repository) ReleaseComment(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	var comment Comment
+	err = r.db.Collection("comments").FindOneAndUpdate(ctx,
+		bson.M{"_id": oid},
+		bson.M{"$set": bson.M{"held": false}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&comment)
+	if err != nil {
+		return err
+	}
+	r.indexComment(ctx, &comment)
 	return nil
 }
 
@@ -711,15 +836,15 @@ func (r *repository) GetComment(ctx context.Context, id string) (*Comment, error
 	return &comment, nil
 }
 
-func (r *repository) ListComments(ctx context.Context, postID string, parentID *string, limit, offset int) ([]*Comment, error) {
-	filter := bson.M{"postId": postID}
+func (r *repository) ListComments(ctx context.Context, postID string, parentID *string, sort Sort, limit, offset int) ([]*Comment, error) {
+	filter := bson.M{"postId": postID, "held": notHeld}
 	if parentID != nil {
 		filter["parentId"] = *parentID
 	} else {
 		filter["parentId"] = nil
 	}
 
-	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(bson.M{"createdAt": -1})
+	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(applySort(filter, sort, "wilsonScore", time.Now()))
 	cursor, err := r.db.Collection("comments").Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
@@ -732,7 +857,7 @@ func (r *repository) ListComments(ctx context.Context, postID string, parentID *
 }
 
 func (r *repository) ListReplies(ctx context.Context, parentID string, limit, offset int) ([]*Comment, error) {
-	filter := bson.M{"parentId": parentID}
+	filter := bson.M{"parentId": parentID, "held": notHeld}
 
 	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(bson.M{"createdAt": 1})
 	cursor, err := r.db.Collection("comments").Find(ctx, filter, opts)
@@ -748,7 +873,7 @@ func (r *repository) ListReplies(ctx context.Context, parentID string, limit, of
 
 func (r *repository) ListCommentsByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*Comment, error) {
 	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(bson.M{"createdAt": -1})
-	cursor, err := r.db.Collection("comments").Find(ctx, bson.M{"authorId": authorID}, opts)
+	cursor, err := r.db.Collection("comments").Find(ctx, bson.M{"authorId": authorID, "held": notHeld}, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -761,7 +886,7 @@ func (r *repository) ListCommentsByAuthor(ctx context.Context, authorID string,
 
 func (r *repository) ListPublicCommentsByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*Comment, error) {
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{"authorId": authorID}}},
+		{{Key: "$match", Value: bson.M{"authorId": authorID, "held": notHeld}}},
 		{{Key: "$addFields", Value: bson.M{"postIdObj": bson.M{"$toObjectId": "$postId"}}}},
 		{{Key: "$lookup", Value: bson.M{
 			"from":         "posts",
@@ -795,101 +920,6 @@ func (r *repository) ListPublicCommentsByAuthor(ctx context.Context, authorID st
 	return comments, nil
 }
 
-func (r *repository) VotePost(ctx context.Context, userID, postID string, voteType string) error {
-
-	var existingVote Vote
-	err := r.db.Collection("votes").FindOne(ctx, bson.M{"userId": userID, "postId": postID}).Decode(&existingVote)
-
-	postOid, _ := bson.ObjectIDFromHex(postID)
-
-	if err == mongo.ErrNoDocuments {
-
-		if voteType == "NONE" {
-			return nil
-		}
-		_, err := r.db.Collection("votes").InsertOne(ctx, bson.M{
-			"userId":    userID,
-			"postId":    postID,
-			"type":      voteType,
-			"createdAt": time.Now(),
-		})
-		if err != nil {
-			return err
-		}
-
-		inc := bson.M{}
-		if voteType == "UP" {
-			inc["upvotesCount"] = 1
-		} else {
-			inc["downvotesCount"] = 1
-		}
-		_, _ = r.db.Collection("posts").UpdateOne(ctx, bson.M{"_id": postOid}, bson.M{"$inc": inc})
-
-	} else if err == nil {
-
-		if existingVote.Type == voteType {
-			return nil
-		}
-
-		voteOid, err := bson.ObjectIDFromHex(existingVote.ID)
-		if err != nil {
-			return err
-		}
-
-		if voteType == "NONE" {
-
-			_, err := r.db.Collection("votes").DeleteOne(ctx, bson.M{"_id": voteOid})
-			if err != nil {
-				return err
-			}
-
-			inc := bson.M{}
-			if existingVote.Type == "UP" {
-				inc["upvotesCount"] = -1
-			} else {
-				inc["downvotesCount"] = -1
-			}
-			_, _ = r.db.Collection("posts").UpdateOne(ctx, bson.M{"_id": postOid}, bson.M{"$inc": inc})
-
-		} else {
-
-			_, err := r.db.Collection("votes").UpdateOne(ctx, bson.M{"_id": voteOid}, bson.M{
-				"$set": bson.M{"type": voteType},
-			})
-			if err != nil {
-				return err
-			}
-
-			inc := bson.M{}
-
-			if existingVote.Type == "UP" {
-				inc["upvotesCount"] = -1
-			} else {
-				inc["downvotesCount"] = -1
-			}
-
-			if voteType == "UP" {
-				if val, ok := inc["upvotesCount"]; ok {
-					inc["upvotesCount"] = val.(int) + 1
-				} else {
-					inc["upvotesCount"] = 1
-				}
-			} else {
-				if val, ok := inc["downvotesCount"]; ok {
-					inc["downvotesCount"] = val.(int) + 1
-				} else {
-					inc["downvotesCount"] = 1
-				}
-			}
-			_, _ = r.db.Collection("posts").UpdateOne(ctx, bson.M{"_id": postOid}, bson.M{"$inc": inc})
-		}
-	} else {
-		return err
-	}
-
-	return nil
-}
-
 func (r *repository) GetUserVote(ctx context.Context, userID, postID string) (string, error) {
 	var vote Vote
 	err := r.db.Collection("votes").FindOne(ctx, bson.M{"userId": userID, "postId": postID}).Decode(&vote)
@@ -902,101 +932,6 @@ func (r *repository) GetUserVote(ctx context.Context, userID, postID string) (st
 	return vote.Type, nil
 }
 
-func (r *repository) VoteComment(ctx context.Context, userID, commentID string, voteType string) error {
-
-	var existingVote CommentVote
-	err := r.db.Collection("commentVotes").FindOne(ctx, bson.M{"userId": userID, "commentId": commentID}).Decode(&existingVote)
-
-	commentOid, _ := bson.ObjectIDFromHex(commentID)
-
-	if err == mongo.ErrNoDocuments {
-
-		if voteType == "NONE" {
-			return nil
-		}
-		_, err := r.db.Collection("commentVotes").InsertOne(ctx, bson.M{
-			"userId":    userID,
-			"commentId": commentID,
-			"type":      voteType,
-			"createdAt": time.Now(),
-		})
-		if err != nil {
-			return err
-		}
-
-		inc := bson.M{}
-		if voteType == "UP" {
-			inc["upvotesCount"] = 1
-		} else {
-			inc["downvotesCount"] = 1
-		}
-		_, _ = r.db.Collection("comments").UpdateOne(ctx, bson.M{"_id": commentOid}, bson.M{"$inc": inc})
-
-	} else if err == nil {
-
-		if existingVote.Type == voteType {
-			return nil
-		}
-
-		voteOid, err := bson.ObjectIDFromHex(existingVote.ID)
-		if err != nil {
-			return err
-		}
-
-		if voteType == "NONE" {
-
-			_, err := r.db.Collection("commentVotes").DeleteOne(ctx, bson.M{"_id": voteOid})
-			if err != nil {
-				return err
-			}
-
-			inc := bson.M{}
-			if existingVote.Type == "UP" {
-				inc["upvotesCount"] = -1
-			} else {
-				inc["downvotesCount"] = -1
-			}
-			_, _ = r.db.Collection("comments").UpdateOne(ctx, bson.M{"_id": commentOid}, bson.M{"$inc": inc})
-
-		} else {
-
-			_, err := r.db.Collection("commentVotes").UpdateOne(ctx, bson.M{"_id": voteOid}, bson.M{
-				"$set": bson.M{"type": voteType},
-			})
-			if err != nil {
-				return err
-			}
-
-			inc := bson.M{}
-
-			if existingVote.Type == "UP" {
-				inc["upvotesCount"] = -1
-			} else {
-				inc["downvotesCount"] = -1
-			}
-
-			if voteType == "UP" {
-				if val, ok := inc["upvotesCount"]; ok {
-					inc["upvotesCount"] = val.(int) + 1
-				} else {
-					inc["upvotesCount"] = 1
-				}
-			} else {
-				if val, ok := inc["downvotesCount"]; ok {
-					inc["downvotesCount"] = val.(int) + 1
-				} else {
-					inc["downvotesCount"] = 1
-				}
-			}
-			_, _ = r.db.Collection("comments").UpdateOne(ctx, bson.M{"_id": commentOid}, bson.M{"$inc": inc})
-		}
-	} else {
-		return err
-	}
-
-	return nil
-}
-
 func (r *repository) GetUserCommentVote(ctx context.Context, userID, commentID string) (string, error) {
 	var vote CommentVote
 	err := r.db.Collection("commentVotes").FindOne(ctx, bson.M{"userId": userID, "commentId": commentID}).Decode(&vote)
@@ -1097,7 +1032,7 @@ func (r *repository) CreateMessage(ctx context.Context, message *Message) error
 
 func (r *repository) ListMessages(ctx context.Context, channelID string, limit, offset int) ([]*Message, error) {
 	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)).SetSort(bson.M{"createdAt": 1})
-	cursor, err := r.db.Collection("messages").Find(ctx, bson.M{"channelId": channelID}, opts)
+	cursor, err := r.db.Collection("messages").Find(ctx, bson.M{"channelId": channelID, "held": notHeld}, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -1108,6 +1043,36 @@ func (r *repository) ListMessages(ctx context.Context, channelID string, limit,
 	return messages, nil
 }
 
+func (r *repository) GetMessage(ctx context.Context, id string) (*Message, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var message Message
+	if err := r.db.Collection("messages").FindOne(ctx, bson.M{"_id": oid}).Decode(&message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *repository) ReleaseMessage(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Collection("messages").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"held": false}})
+	return err
+}
+
+func (r *repository) DeleteMessage(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Collection("messages").DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
 func (r *repository) ListUnindexedGroups(ctx context.Context, limit int) ([]*Group, error) {
 	filter := bson.M{
 		"$or": []bson.M{
@@ -1142,6 +1107,7 @@ func (r *repository) ListUnindexedPosts(ctx context.Context, limit int) ([]*Post
 			{"indexed": false},
 			{"indexed": bson.M{"$exists": false}},
 		},
+		"held": notHeld,
 	}
 	opts := options.Find().SetLimit(int64(limit))
 	cursor, err := r.db.Collection("posts").Find(ctx, filter, opts)
@@ -1170,6 +1136,7 @@ func (r *repository) ListUnindexedComments(ctx context.Context, limit int) ([]*C
 			{"indexed": false},
 			{"indexed": bson.M{"$exists": false}},
 		},
+		"held": notHeld,
 	}
 	opts := options.Find().SetLimit(int64(limit))
 	cursor, err := r.db.Collection("comments").Find(ctx, filter, opts)
@@ -1212,6 +1179,12 @@ func (r *repository) EnsureIndexes(ctx context.Context) error {
 		{Keys: bson.D{{Key: "groupId", Value: 1}}},
 		{Keys: bson.D{{Key: "authorId", Value: 1}}},
 		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+		// Group feeds, one per sort order.
+		{Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "held", Value: 1}, {Key: "pinned", Value: -1}, {Key: "pinnedAt", Value: -1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "held", Value: 1}, {Key: "pinned", Value: -1}, {Key: "pinnedAt", Value: -1}, {Key: "hotScore", Value: -1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "held", Value: 1}, {Key: "pinned", Value: -1}, {Key: "pinnedAt", Value: -1}, {Key: "score", Value: -1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "held", Value: 1}, {Key: "pinned", Value: -1}, {Key: "pinnedAt", Value: -1}, {Key: "controversy", Value: -1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "hotScore", Value: -1}}},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create post indexes: %w", err)
@@ -1220,6 +1193,7 @@ func (r *repository) EnsureIndexes(ctx context.Context) error {
 	// Comments
 	_, err = r.db.Collection("comments").Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{Keys: bson.D{{Key: "postId", Value: 1}}},
+		{Keys: bson.D{{Key: "postId", Value: 1}, {Key: "parentId", Value: 1}, {Key: "wilsonScore", Value: -1}}},
 		{Keys: bson.D{{Key: "parentId", Value: 1}}},
 		{Keys: bson.D{{Key: "authorId", Value: 1}}},
 		{Keys: bson.D{{Key: "createdAt", Value: 1}}},
@@ -1270,6 +1244,15 @@ func (r *repository) EnsureIndexes(ctx context.Context) error {
 		return fmt.Errorf("failed to create message indexes: %w", err)
 	}
 
+	// Group sanctions
+	_, err = r.sanctions().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "userId", Value: 1}, {Key: "type", Value: 1}}},
+		{Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "createdAt", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create group sanction indexes: %w", err)
+	}
+
 	return nil
 }
 
@@ -1318,6 +1301,122 @@ func (r *repository) RemoveMember(ctx context.Context, groupID, userID string) e
 	return r.LeaveGroup(ctx, groupID, userID)
 }
 
+func (r *repository) AddModerator(ctx context.Context, groupID, userID string) error {
+	oid, err := bson.ObjectIDFromHex(groupID)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Collection("groups").UpdateOne(ctx, bson.M{
+		"_id":       oid,
+		"memberIds": userID,
+		"ownerId":   bson.M{"$ne": userID},
+	}, bson.M{"$addToSet": bson.M{"moderatorIds": userID}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("user is not a member of the group")
+	}
+	return nil
+}
+
+func (r *repository) RemoveModerator(ctx context.Context, groupID, userID string) error {
+	oid, err := bson.ObjectIDFromHex(groupID)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Collection("groups").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$pull": bson.M{"moderatorIds": userID},
+	})
+	return err
+}
+
+func (r *repository) TransferOwnership(ctx context.Context, groupID, fromID, toID string) (*Group, error) {
+	oid, err := bson.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, err
+	}
+	coll := r.db.Collection("groups")
+	// Matching on the current owner keeps two concurrent transfers from both
+	// succeeding.
+	res, err := coll.UpdateOne(ctx, bson.M{
+		"_id":       oid,
+		"ownerId":   fromID,
+		"memberIds": toID,
+	}, bson.M{
+		"$set":  bson.M{"ownerId": toID},
+		"$pull": bson.M{"moderatorIds": toID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.MatchedCount == 0 {
+		return nil, fmt.Errorf("new owner must be a member of the group")
+	}
+	_, err = coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$addToSet": bson.M{"moderatorIds": fromID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := r.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]interface{}{
+		"id":           group.ID,
+		"type":         "group",
+		"group_id":     group.ID,
+		"group_type":   string(group.Type),
+		"name":         group.Name,
+		"description":  group.Description,
+		"slug":         group.Slug,
+		"ownerId":      group.OwnerID,
+		"createdAt":    group.CreatedAt.Unix(),
+		"membersCount": group.MembersCount,
+	}
+	if group.Icon != "" {
+		doc["icon"] = group.Icon
+	}
+	_ = r.searchClient.IndexGroup(ctx, doc)
+	return group, nil
+}
+
+func (r *repository) setPostFields(ctx context.Context, postID string, update bson.M) error {
+	oid, err := bson.ObjectIDFromHex(postID)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Collection("posts").UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("post not found")
+	}
+	return nil
+}
+
+func (r *repository) PinPost(ctx context.Context, postID string, pinned bool) error {
+	if !pinned {
+		return r.setPostFields(ctx, postID, bson.M{"$unset": bson.M{"pinned": "", "pinnedAt": ""}})
+	}
+	return r.setPostFields(ctx, postID, bson.M{"$set": bson.M{"pinned": true, "pinnedAt": time.Now()}})
+}
+
+func (r *repository) CountPinnedPosts(ctx context.Context, groupID string) (int64, error) {
+	return r.db.Collection("posts").CountDocuments(ctx, bson.M{"groupId": groupID, "pinned": true})
+}
+
+func (r *repository) LockPost(ctx context.Context, postID string, locked bool) error {
+	return r.setPostFields(ctx, postID, bson.M{"$set": bson.M{"locked": locked}})
+}
+
+func (r *repository) SetPostAnnouncement(ctx context.Context, postID string, announcement bool) error {
+	return r.setPostFields(ctx, postID, bson.M{"$set": bson.M{"announcement": announcement}})
+}
+
 func generateRandomString(n int) string {
 	b := make([]byte, n)
 	_, err := rand.Read(b)
@@ -1327,7 +1426,7 @@ func generateRandomString(n int) string {
 	return base64.RawURLEncoding.EncodeToString(b)
 }
 
-func (r *repository) UpdatePost(ctx context.Context, postID string, title *string, content *string) (*Post, error) {
+func (r *repository) UpdatePost(ctx context.Context, postID string, title *string, content *string, hold bool) (*Post, error) {
 	oid, err := bson.ObjectIDFromHex(postID)
 	if err != nil {
 		return nil, err
@@ -1340,6 +1439,9 @@ func (r *repository) UpdatePost(ctx context.Context, postID string, title *strin
 	if content != nil {
 		update["content"] = *content
 	}
+	if hold {
+		update["held"] = true
+	}
 
 	_, err = r.db.Collection("posts").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update})
 	if err != nil {
@@ -1350,6 +1452,10 @@ func (r *repository) UpdatePost(ctx context.Context, postID string, title *strin
 	if err != nil {
 		return nil, err
 	}
+	if post.Held {
+		_ = r.searchClient.DeletePost(ctx, postID)
+		return post, nil
+	}
 
 	group, err := r.GetGroupByID(ctx, post.GroupID)
 	if err == nil {
@@ -1370,11 +1476,39 @@ func (r *repository) UpdatePost(ctx context.Context, postID string, title *strin
 }
 
 func (r *repository) DeletePost(ctx context.Context, postID string) error {
+	post, err := r.GetPost(ctx, postID)
+	if err != nil {
+		return err
+	}
 	oid, err := bson.ObjectIDFromHex(postID)
 	if err != nil {
 		return err
 	}
 
+	cursor, err := r.db.Collection("comments").Find(ctx, bson.M{"postId": postID}, options.Find().SetProjection(bson.M{"_id": 1, "authorId": 1}))
+	if err != nil {
+		return err
+	}
+	var comments []*Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return err
+	}
+	commentIDs := make([]string, len(comments))
+	commentAuthors := make(map[string]string, len(comments))
+	for i, c := range comments {
+		commentIDs[i] = c.ID
+		commentAuthors[c.ID] = c.AuthorID
+	}
+
+	err = r.revokeKarma(ctx, r.db.Collection("votes"), "postId", map[string]string{postID: post.AuthorID}, post.GroupID, karma.SourcePost)
+	if err != nil {
+		return err
+	}
+	err = r.revokeKarma(ctx, r.db.Collection("commentVotes"), "commentId", commentAuthors, post.GroupID, karma.SourceComment)
+	if err != nil {
+		return err
+	}
+
 	_, err = r.db.Collection("comments").DeleteMany(ctx, bson.M{"postId": postID})
 	if err != nil {
 		return err
@@ -1385,7 +1519,7 @@ func (r *repository) DeletePost(ctx context.Context, postID string) error {
 		return err
 	}
 
-	_, err = r.db.Collection("comment_votes").DeleteMany(ctx, bson.M{"postId": postID})
+	_, err = r.db.Collection("commentVotes").DeleteMany(ctx, bson.M{"commentId": bson.M{"$in": commentIDs}})
 	if err != nil {
 		return err
 	}
@@ -1400,7 +1534,7 @@ func (r *repository) DeletePost(ctx context.Context, postID string) error {
 	return nil
 }
 
-func (r *repository) UpdateComment(ctx context.Context, commentID string, content string) (*Comment, error) {
+func (r *repository) UpdateComment(ctx context.Context, commentID string, content string, hold bool) (*Comment, error) {
 	oid, err := bson.ObjectIDFromHex(commentID)
 	if err != nil {
 		return nil, err
@@ -1410,6 +1544,9 @@ func (r *repository) UpdateComment(ctx context.Context, commentID string, conten
 		"content":  content,
 		"isEdited": true,
 	}
+	if hold {
+		update["held"] = true
+	}
 
 	_, err = r.db.Collection("comments").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update})
 	if err != nil {
@@ -1420,6 +1557,10 @@ func (r *repository) UpdateComment(ctx context.Context, commentID string, conten
 	if err != nil {
 		return nil, err
 	}
+	if comment.Held {
+		_ = r.searchClient.DeleteComment(ctx, commentID)
+		return comment, nil
+	}
 
 	post, err := r.GetPost(ctx, comment.PostID)
 	if err == nil {
@@ -1454,12 +1595,19 @@ func (r *repository) DeleteComment(ctx context.Context, commentID string) error
 		return err
 	}
 
+	if post, err := r.GetPost(ctx, comment.PostID); err == nil {
+		err = r.revokeKarma(ctx, r.db.Collection("commentVotes"), "commentId", map[string]string{commentID: comment.AuthorID}, post.GroupID, karma.SourceComment)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = r.db.Collection("comments").DeleteOne(ctx, bson.M{"_id": oid})
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.Collection("comment_votes").DeleteMany(ctx, bson.M{"commentId": commentID})
+	_, err = r.db.Collection("commentVotes").DeleteMany(ctx, bson.M{"commentId": commentID})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,80 @@
+package community
+
+// GroupRole is a user's standing within a single group.
+type GroupRole string
+
+const (
+	GroupRoleOwner     GroupRole = "OWNER"
+	GroupRoleModerator GroupRole = "MODERATOR"
+	GroupRoleMember    GroupRole = "MEMBER"
+)
+
+// GroupPermission is something only some group roles may do.
+type GroupPermission string
+
+const (
+	GroupEdit             GroupPermission = "EDIT"             // Name, description and icon
+	GroupManageMembers    GroupPermission = "MANAGE_MEMBERS"   // Invites, join requests and removals
+	GroupManageChannels   GroupPermission = "MANAGE_CHANNELS"  // Discussion channels
+	GroupModerateContent  GroupPermission = "MODERATE_CONTENT" // Other members' posts, comments and messages
+	GroupManageModerators GroupPermission = "MANAGE_MODERATORS"
+	GroupManageWebhooks   GroupPermission = "MANAGE_WEBHOOKS"
+	GroupTransfer         GroupPermission = "TRANSFER"
+	GroupDelete           GroupPermission = "DELETE"
+)
+
+var moderatorPermissions = map[GroupPermission]bool{
+	GroupManageMembers:   true,
+	GroupManageChannels:  true,
+	GroupModerateContent: true,
+}
+
+// RoleOf returns the user's role in the group, or "" for non-members.
+func (g *Group) RoleOf(userID string) GroupRole {
+	if userID == "" {
+		return ""
+	}
+	if g.OwnerID == userID {
+		return GroupRoleOwner
+	}
+	for _, id := range g.ModeratorIDs {
+		if id == userID {
+			return GroupRoleModerator
+		}
+	}
+	for _, id := range g.MemberIDs {
+		if id == userID {
+			return GroupRoleMember
+		}
+	}
+	return ""
+}
+
+// Can reports whether the user's role in the group grants perm.
+func (g *Group) Can(userID string, perm GroupPermission) bool {
+	switch g.RoleOf(userID) {
+	case GroupRoleOwner:
+		return true
+	case GroupRoleModerator:
+		return moderatorPermissions[perm]
+	}
+	return false
+}
+
+// Outranks reports whether actor's role is above target's, e.g. whether a
+// moderator may remove a member.
+func (g *Group) Outranks(actorID, targetID string) bool {
+	return groupRoleRank(g.RoleOf(actorID)) > groupRoleRank(g.RoleOf(targetID))
+}
+
+func groupRoleRank(role GroupRole) int {
+	switch role {
+	case GroupRoleOwner:
+		return 3
+	case GroupRoleModerator:
+		return 2
+	case GroupRoleMember:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package community
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type SanctionType string
+
+const (
+	// SanctionBan keeps a user out of the group entirely.
+	SanctionBan SanctionType = "BAN"
+	// SanctionMute keeps a member in the group but stops them posting,
+	// commenting and chatting.
+	SanctionMute SanctionType = "MUTE"
+)
+
+type GroupSanction struct {
+	ID          string       `bson:"_id,omitempty"`
+	GroupID     string       `bson:"groupId"`
+	UserID      string       `bson:"userId"`
+	Type        SanctionType `bson:"type"`
+	Reason      string       `bson:"reason"`
+	ModeratorID string       `bson:"moderatorId"`
+	CreatedAt   time.Time    `bson:"createdAt"`
+	ExpiresAt   *time.Time   `bson:"expiresAt,omitempty"` // nil for permanent bans
+	LiftedAt    *time.Time   `bson:"liftedAt,omitempty"`
+	LiftedBy    string       `bson:"liftedBy,omitempty"`
+}
+
+func (s *GroupSanction) Active(now time.Time) bool {
+	return s.LiftedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(now))
+}
+
+func (r *repository) sanctions() *mongo.Collection {
+	return r.db.Collection("groupSanctions")
+}
+
+// activeSanctionFilter matches sanctions that are neither lifted nor expired.
+func activeSanctionFilter(groupID, userID string, sanctionType SanctionType, now time.Time) bson.M {
+	return bson.M{
+		"groupId":  groupID,
+		"userId":   userID,
+		"type":     sanctionType,
+		"liftedAt": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$exists": false}},
+			bson.M{"expiresAt": bson.M{"$gt": now}},
+		},
+	}
+}
+
+func (r *repository) AddSanction(ctx context.Context, sanction *GroupSanction) error {
+	// A new ban or mute replaces the one in force.
+	if err := r.LiftSanction(ctx, sanction.GroupID, sanction.UserID, sanction.Type, sanction.ModeratorID); err != nil {
+		return err
+	}
+	sanction.CreatedAt = time.Now()
+	res, err := r.sanctions().InsertOne(ctx, sanction)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		sanction.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) ActiveSanction(ctx context.Context, groupID, userID string, sanctionType SanctionType) (*GroupSanction, error) {
+	var sanction GroupSanction
+	opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	err := r.sanctions().FindOne(ctx, activeSanctionFilter(groupID, userID, sanctionType, time.Now()), opts).Decode(&sanction)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sanction, nil
+}
+
+func (r *repository) LiftSanction(ctx context.Context, groupID, userID string, sanctionType SanctionType, liftedBy string) error {
+	now := time.Now()
+	_, err := r.sanctions().UpdateMany(ctx, activeSanctionFilter(groupID, userID, sanctionType, now), bson.M{
+		"$set": bson.M{"liftedAt": now, "liftedBy": liftedBy},
+	})
+	return err
+}
+
+func (r *repository) ListSanctions(ctx context.Context, groupID string, sanctionType *SanctionType, limit, offset int) ([]*GroupSanction, error) {
+	filter := bson.M{"groupId": groupID}
+	if sanctionType != nil {
+		filter["type"] = *sanctionType
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+	cursor, err := r.sanctions().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var sanctions []*GroupSanction
+	if err := cursor.All(ctx, &sanctions); err != nil {
+		return nil, err
+	}
+	return sanctions, nil
+}
@@ -0,0 +1,189 @@
+package community
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/karma"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	VoteUp   = "UP"
+	VoteDown = "DOWN"
+	VoteNone = "NONE"
+)
+
+// voteRetries bounds how often a vote is retried after losing an upsert race
+// against another request from the same user.
+const voteRetries = 3
+
+func counterField(voteType string) string {
+	if voteType == VoteUp {
+		return "upvotesCount"
+	}
+	return "downvotesCount"
+}
+
+// castVote moves a user's vote on a target to voteType and adjusts the
+// target's counters by exactly the change that was made. It decodes the
+// updated target into target and returns the vote that was replaced.
+//
+// Every transition goes through a single atomic operation on the vote
+// document, which returns the vote it replaced. Concurrent requests are
+// serialized by that document (and the unique userId+target index), so each
+// one applies the counter delta of its own transition and the counters can't
+// drift the way separate read, write and $inc steps did. The scores are
+// computed in the same update as the counters, so they always match them.
+func (r *repository) castVote(ctx context.Context, votes, targets *mongo.Collection, targetField, userID, targetID, voteType string, scores bson.D, target interface{}) (string, error) {
+	if voteType != VoteUp && voteType != VoteDown && voteType != VoteNone {
+		return "", fmt.Errorf("invalid vote type %s", voteType)
+	}
+	targetOid, err := bson.ObjectIDFromHex(targetID)
+	if err != nil {
+		return "", err
+	}
+	key := bson.M{"userId": userID, targetField: targetID}
+
+	var previous string
+	for attempt := 0; ; attempt++ {
+		previous, err = swapVote(ctx, votes, key, voteType)
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == voteRetries {
+			return "", err
+		}
+	}
+
+	inc := map[string]int{}
+	if previous != voteType {
+		if previous != VoteNone {
+			inc[counterField(previous)]--
+		}
+		if voteType != VoteNone {
+			inc[counterField(voteType)]++
+		}
+	}
+	counters := bson.D{}
+	for _, field := range []string{"upvotesCount", "downvotesCount"} {
+		counters = append(counters, bson.E{Key: field, Value: bson.M{"$add": bson.A{
+			bson.M{"$ifNull": bson.A{"$" + field, 0}}, inc[field],
+		}}})
+	}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: counters}},
+		{{Key: "$set", Value: scores}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = targets.FindOneAndUpdate(ctx, bson.M{"_id": targetOid}, update, opts).Decode(target)
+	return previous, err
+}
+
+func voteValue(voteType string) int {
+	switch voteType {
+	case VoteUp:
+		return 1
+	case VoteDown:
+		return -1
+	}
+	return 0
+}
+
+// swapVote atomically replaces the vote identified by key and returns the
+// previous vote type, VoteNone if there was none.
+func swapVote(ctx context.Context, votes *mongo.Collection, key bson.M, voteType string) (string, error) {
+	var previous struct {
+		Type string `bson:"type"`
+	}
+	var err error
+	if voteType == VoteNone {
+		err = votes.FindOneAndDelete(ctx, key).Decode(&previous)
+	} else {
+		opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+		err = votes.FindOneAndUpdate(ctx, key, bson.M{
+			"$set":         bson.M{"type": voteType},
+			"$setOnInsert": bson.M{"createdAt": time.Now()},
+		}, opts).Decode(&previous)
+	}
+	if err == mongo.ErrNoDocuments {
+		return VoteNone, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return previous.Type, nil
+}
+
+func (r *repository) VotePost(ctx context.Context, userID, postID string, voteType string) error {
+	var post Post
+	previous, err := r.castVote(ctx, r.db.Collection("votes"), r.db.Collection("posts"), "postId", userID, postID, voteType, postScoresExpr(time.Now()), &post)
+	if err != nil {
+		return err
+	}
+	return r.addKarma(ctx, userID, post.AuthorID, post.GroupID, karma.SourcePost, voteValue(voteType)-voteValue(previous))
+}
+
+func (r *repository) VoteComment(ctx context.Context, userID, commentID string, voteType string) error {
+	var comment Comment
+	previous, err := r.castVote(ctx, r.db.Collection("commentVotes"), r.db.Collection("comments"), "commentId", userID, commentID, voteType, commentScoresExpr(), &comment)
+	if err != nil {
+		return err
+	}
+	delta := voteValue(voteType) - voteValue(previous)
+	if r.karma == nil || delta == 0 || userID == comment.AuthorID {
+		return nil
+	}
+	post, err := r.GetPost(ctx, comment.PostID)
+	if err != nil {
+		return err
+	}
+	return r.addKarma(ctx, userID, comment.AuthorID, post.GroupID, karma.SourceComment, delta)
+}
+
+// addKarma credits the author with a vote by someone else. Self-votes don't
+// count towards karma.
+func (r *repository) addKarma(ctx context.Context, voterID, authorID, groupID string, source karma.Source, delta int) error {
+	if r.karma == nil || delta == 0 || voterID == authorID {
+		return nil
+	}
+	return r.karma.Add(ctx, authorID, groupID, source, delta)
+}
+
+// revokeKarma takes back the karma authors earned from the votes on content
+// that is about to be deleted. authors maps content ids to their authors.
+func (r *repository) revokeKarma(ctx context.Context, votes *mongo.Collection, targetField string, authors map[string]string, groupID string, source karma.Source) error {
+	if r.karma == nil || len(authors) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(authors))
+	for id := range authors {
+		ids = append(ids, id)
+	}
+	cursor, err := votes.Find(ctx, bson.M{targetField: bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	var rows []bson.M
+	if err := cursor.All(ctx, &rows); err != nil {
+		return err
+	}
+	earned := map[string]int{}
+	for _, row := range rows {
+		target, _ := row[targetField].(string)
+		voter, _ := row["userId"].(string)
+		voteType, _ := row["type"].(string)
+		if author := authors[target]; author != "" && author != voter {
+			earned[author] += voteValue(voteType)
+		}
+	}
+	for author, n := range earned {
+		if err := r.karma.Add(ctx, author, groupID, source, -n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
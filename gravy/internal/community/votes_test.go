@@ -0,0 +1,217 @@
+package community
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/db"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// testRepository connects to WIKINITT_TEST_MONGODB_URI and returns a
+// repository on a throwaway database, skipping the test when it isn't set.
+func testRepository(t *testing.T) (*repository, context.Context) {
+	t.Helper()
+	uri := os.Getenv("WIKINITT_TEST_MONGODB_URI")
+	if uri == "" {
+		t.Skip("WIKINITT_TEST_MONGODB_URI not set")
+	}
+	client, err := db.Connect(uri)
+	if err != nil {
+		t.Skipf("MongoDB not reachable: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	database := client.Database(fmt.Sprintf("wikinitt_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		_ = database.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+		cancel()
+	})
+
+	repo := NewRepository(database, nil, nil).(*repository)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+	return repo, ctx
+}
+
+func insertTestDoc(t *testing.T, ctx context.Context, coll *mongo.Collection, doc interface{}) string {
+	t.Helper()
+	res, err := coll.InsertOne(ctx, doc)
+	if err != nil {
+		t.Fatalf("insert into %s: %v", coll.Name(), err)
+	}
+	return res.InsertedID.(bson.ObjectID).Hex()
+}
+
+// hammerVotes has every user flip their vote on the target from several
+// goroutines at once.
+func hammerVotes(t *testing.T, vote func(userID string, voteType string) error) {
+	t.Helper()
+	const (
+		voters     = 8
+		perVoter   = 4
+		iterations = 25
+	)
+	types := []string{VoteUp, VoteDown, VoteNone}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, voters*perVoter*iterations)
+	for v := 0; v < voters; v++ {
+		userID := fmt.Sprintf("user-%d", v)
+		for g := 0; g < perVoter; g++ {
+			wg.Add(1)
+			go func(seed int64) {
+				defer wg.Done()
+				rng := rand.New(rand.NewSource(seed))
+				for i := 0; i < iterations; i++ {
+					if err := vote(userID, types[rng.Intn(len(types))]); err != nil {
+						errs <- err
+					}
+				}
+			}(int64(v*perVoter + g))
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("vote failed: %v", err)
+	}
+}
+
+func TestConcurrentPostVotes(t *testing.T) {
+	repo, ctx := testRepository(t)
+	postID := insertTestDoc(t, ctx, repo.db.Collection("posts"), &Post{
+		Title:     "Concurrent votes",
+		GroupID:   "group",
+		AuthorID:  "author",
+		CreatedAt: time.Now(),
+	})
+
+	hammerVotes(t, func(userID, voteType string) error {
+		return repo.VotePost(ctx, userID, postID, voteType)
+	})
+
+	tallies, err := repo.tallyVotes(ctx, repo.db.Collection("votes"), "postId")
+	if err != nil {
+		t.Fatalf("tallyVotes: %v", err)
+	}
+	post, err := repo.GetPost(ctx, postID)
+	if err != nil {
+		t.Fatalf("GetPost: %v", err)
+	}
+	want := tallies[postID]
+	if post.UpvotesCount != want.Up || post.DownvotesCount != want.Down {
+		t.Errorf("counters drifted: got %d up / %d down, votes say %d / %d",
+			post.UpvotesCount, post.DownvotesCount, want.Up, want.Down)
+	}
+	if post.Score != post.UpvotesCount-post.DownvotesCount {
+		t.Errorf("score %d doesn't match counters %d / %d", post.Score, post.UpvotesCount, post.DownvotesCount)
+	}
+}
+
+func TestConcurrentCommentVotes(t *testing.T) {
+	repo, ctx := testRepository(t)
+	commentID := insertTestDoc(t, ctx, repo.db.Collection("comments"), &Comment{
+		Content:   "Concurrent votes",
+		PostID:    "post",
+		AuthorID:  "author",
+		CreatedAt: time.Now(),
+	})
+
+	hammerVotes(t, func(userID, voteType string) error {
+		return repo.VoteComment(ctx, userID, commentID, voteType)
+	})
+
+	report, err := repo.RecountCounters(ctx, true)
+	if err != nil {
+		t.Fatalf("RecountCounters: %v", err)
+	}
+	if report.Fixed() != 0 {
+		t.Errorf("counters drifted: %v", report.Drift)
+	}
+}
+
+func TestRecountFixesDrift(t *testing.T) {
+	repo, ctx := testRepository(t)
+	postID := insertTestDoc(t, ctx, repo.db.Collection("posts"), &Post{
+		Title:         "Drifted",
+		GroupID:       "group",
+		UpvotesCount:  5,
+		CommentsCount: 3,
+		CreatedAt:     time.Now(),
+	})
+	if err := repo.VotePost(ctx, "user", postID, VoteDown); err != nil {
+		t.Fatalf("VotePost: %v", err)
+	}
+
+	report, err := repo.RecountCounters(ctx, false)
+	if err != nil {
+		t.Fatalf("RecountCounters: %v", err)
+	}
+	for _, field := range []string{"posts.upvotesCount", "posts.commentsCount"} {
+		if report.Drift[field] != 1 {
+			t.Errorf("expected drift in %s, got %v", field, report.Drift)
+		}
+	}
+
+	post, err := repo.GetPost(ctx, postID)
+	if err != nil {
+		t.Fatalf("GetPost: %v", err)
+	}
+	if post.UpvotesCount != 0 || post.DownvotesCount != 1 || post.CommentsCount != 0 || post.Score != -1 {
+		t.Errorf("post not fixed: %+v", post)
+	}
+}
+
+func TestScoreExpressionsMatch(t *testing.T) {
+	repo, ctx := testRepository(t)
+	now := time.Now()
+	createdAt := now.Add(-30 * time.Hour).Truncate(time.Millisecond)
+	for _, c := range [][2]int{{0, 0}, {1, 0}, {0, 3}, {7, 2}, {40, 41}} {
+		postID := insertTestDoc(t, ctx, repo.db.Collection("posts"), &Post{
+			UpvotesCount: c[0], DownvotesCount: c[1], CreatedAt: createdAt,
+		})
+		commentID := insertTestDoc(t, ctx, repo.db.Collection("comments"), &Comment{
+			UpvotesCount: c[0], DownvotesCount: c[1], CreatedAt: createdAt,
+		})
+
+		var post Post
+		oid, _ := bson.ObjectIDFromHex(postID)
+		err := repo.db.Collection("posts").FindOneAndUpdate(ctx, bson.M{"_id": oid},
+			mongo.Pipeline{{{Key: "$set", Value: postScoresExpr(now)}}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&post)
+		if err != nil {
+			t.Fatalf("post update: %v", err)
+		}
+		want := postScores(&post, now)
+		if post.Score != want["score"] || len(scoreDrift(map[string][2]float64{
+			"hotScore":    {post.HotScore, want["hotScore"].(float64)},
+			"controversy": {post.Controversy, want["controversy"].(float64)},
+		})) > 0 {
+			t.Errorf("%v: post scores %d/%v/%v, want %v", c, post.Score, post.HotScore, post.Controversy, want)
+		}
+
+		var comment Comment
+		oid, _ = bson.ObjectIDFromHex(commentID)
+		err = repo.db.Collection("comments").FindOneAndUpdate(ctx, bson.M{"_id": oid},
+			mongo.Pipeline{{{Key: "$set", Value: commentScoresExpr()}}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&comment)
+		if err != nil {
+			t.Fatalf("comment update: %v", err)
+		}
+		if drift := scoreDrift(map[string][2]float64{
+			"wilsonScore": {comment.WilsonScore, WilsonScore(c[0], c[1])},
+			"controversy": {comment.Controversy, ControversyScore(c[0], c[1])},
+		}); len(drift) > 0 || comment.Score != c[0]-c[1] {
+			t.Errorf("%v: comment scores differ in %v", c, drift)
+		}
+	}
+}
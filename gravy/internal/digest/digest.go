@@ -0,0 +1,258 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"log"
+	"net/url"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/articles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mailer"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/sanitization"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+// maxItems caps each section of a digest.
+const maxItems = 10
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/digest.html"))
+	textTemplate = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/digest.txt"))
+)
+
+type PostItem struct {
+	Title  string
+	Group  string
+	Author string
+	URL    string
+}
+
+type ReplyItem struct {
+	Author    string
+	PostTitle string
+	Excerpt   string
+	URL       string
+}
+
+type ArticleItem struct {
+	Title string
+	URL   string
+}
+
+// Digest is everything one email covers.
+type Digest struct {
+	Name           string
+	Frequency      string
+	Since          time.Time
+	Posts          []PostItem
+	Replies        []ReplyItem
+	Articles       []ArticleItem
+	UnsubscribeURL string
+}
+
+func (d *Digest) Empty() bool {
+	return len(d.Posts) == 0 && len(d.Replies) == 0 && len(d.Articles) == 0
+}
+
+// Render builds the HTML and plain text email for d.
+func Render(to string, d *Digest) (mailer.Message, error) {
+	var text, body bytes.Buffer
+	if err := textTemplate.Execute(&text, d); err != nil {
+		return mailer.Message{}, fmt.Errorf("failed to render digest text: %w", err)
+	}
+	if err := htmlTemplate.Execute(&body, d); err != nil {
+		return mailer.Message{}, fmt.Errorf("failed to render digest html: %w", err)
+	}
+	subject := "Your WikiNITT digest"
+	if d.Frequency != "" {
+		subject = fmt.Sprintf("Your %s WikiNITT digest", d.Frequency)
+	}
+	return mailer.Message{To: to, Subject: subject, Text: text.String(), HTML: body.String()}, nil
+}
+
+// Job sends digests to every user who is due one.
+type Job struct {
+	Users     users.Repository
+	Community community.Repository
+	Articles  articles.Repository
+	Settings  Repository
+	Mailer    mailer.Mailer
+	// FrontendURL is where content links point. APIURL serves the
+	// unsubscribe endpoint.
+	FrontendURL string
+	APIURL      string
+}
+
+// Run sends every due digest and returns how many emails went out. A failure
+// for one user is logged and doesn't stop the others.
+func (j *Job) Run(ctx context.Context, now time.Time) (int, error) {
+	list, err := j.Users.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	sent := 0
+	for _, u := range list {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		ok, err := j.runUser(ctx, u, now)
+		if err != nil {
+			log.Printf("Failed to send digest to user %s: %v", u.ID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (j *Job) runUser(ctx context.Context, u *users.User, now time.Time) (bool, error) {
+	// Only mail addresses the user has shown they own.
+	if u.Email == "" || !u.EmailVerified || u.IsBanned {
+		return false, nil
+	}
+	settings, err := j.Settings.Get(ctx, u.ID)
+	if err != nil {
+		return false, err
+	}
+	period := settings.Frequency.Period()
+	if period == 0 {
+		return false, nil
+	}
+	if settings.LastSentAt == nil {
+		// Start the clock rather than mailing everyone the moment digests
+		// are first deployed.
+		return false, j.Settings.MarkSent(ctx, u.ID, now)
+	}
+	// An hour of slack keeps a job that runs hourly from drifting a digest
+	// into the next slot.
+	if now.Sub(*settings.LastSentAt) < period-time.Hour {
+		return false, nil
+	}
+	since := *settings.LastSentAt
+
+	d, err := j.Build(ctx, u, since)
+	if err != nil {
+		return false, err
+	}
+	d.Frequency = strings.ToLower(string(settings.Frequency))
+	d.UnsubscribeURL = fmt.Sprintf("%s/digest/unsubscribe?token=%s", strings.TrimRight(j.APIURL, "/"), url.QueryEscape(settings.UnsubscribeToken))
+
+	if !d.Empty() {
+		msg, err := Render(u.Email, d)
+		if err != nil {
+			return false, err
+		}
+		if err := j.Mailer.Send(ctx, msg); err != nil {
+			return false, err
+		}
+	}
+	return !d.Empty(), j.Settings.MarkSent(ctx, u.ID, now)
+}
+
+// Build collects the activity since for u: new posts in their groups, replies
+// to their posts and comments, and newly featured articles.
+func (j *Job) Build(ctx context.Context, u *users.User, since time.Time) (*Digest, error) {
+	d := &Digest{Name: u.DisplayName, Since: since}
+	if d.Name == "" {
+		d.Name = u.Name
+	}
+	base := strings.TrimRight(j.FrontendURL, "/")
+
+	groups, err := j.Community.ListGroupsByMember(ctx, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	groupByID := make(map[string]*community.Group, len(groups))
+	groupIDs := make([]string, 0, len(groups))
+	for _, g := range groups {
+		groupByID[g.ID] = g
+		groupIDs = append(groupIDs, g.ID)
+	}
+
+	// Fetch extra to make up for the user's own posts being skipped.
+	posts, err := j.Community.ListPostsSince(ctx, groupIDs, since, 2*maxItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+	for _, p := range posts {
+		if p.AuthorID == u.ID || len(d.Posts) == maxItems {
+			continue
+		}
+		g := groupByID[p.GroupID]
+		d.Posts = append(d.Posts, PostItem{
+			Title:  p.Title,
+			Group:  g.Name,
+			Author: j.authorName(ctx, p.AuthorID),
+			URL:    fmt.Sprintf("%s/c/%s/posts/%s", base, g.Slug, p.ID),
+		})
+	}
+
+	replies, err := j.Community.ListRepliesTo(ctx, u.ID, since, maxItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replies: %w", err)
+	}
+	for _, c := range replies {
+		post, err := j.Community.GetPost(ctx, c.PostID)
+		if err != nil {
+			continue
+		}
+		group, err := j.Community.GetGroupByID(ctx, post.GroupID)
+		if err != nil {
+			continue
+		}
+		d.Replies = append(d.Replies, ReplyItem{
+			Author:    j.authorName(ctx, c.AuthorID),
+			PostTitle: post.Title,
+			Excerpt:   excerpt(c.Content, 140),
+			URL:       fmt.Sprintf("%s/c/%s/posts/%s", base, group.Slug, post.ID),
+		})
+	}
+
+	// Articles count from when they were featured, so older ones featured
+	// this week are included.
+	list, err := j.Articles.ListFeaturedSince(ctx, since, maxItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list featured articles: %w", err)
+	}
+	for _, a := range list {
+		d.Articles = append(d.Articles, ArticleItem{
+			Title: a.Title,
+			URL:   fmt.Sprintf("%s/articles/%s", base, a.Slug),
+		})
+	}
+	return d, nil
+}
+
+func (j *Job) authorName(ctx context.Context, userID string) string {
+	u, err := j.Users.GetByID(ctx, userID)
+	if err != nil {
+		return "Someone"
+	}
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Name
+}
+
+// excerpt turns sanitized HTML content into at most n runes of plain text.
+func excerpt(content string, n int) string {
+	text := strings.Join(strings.Fields(html.UnescapeString(sanitization.SanitizeString(content))), " ")
+	runes := []rune(text)
+	if len(runes) > n {
+		return string(runes[:n]) + "..."
+	}
+	return text
+}
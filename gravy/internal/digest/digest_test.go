@@ -0,0 +1,218 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/articles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mailer"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+)
+
+func TestRender(t *testing.T) {
+	d := &Digest{
+		Name:      "Asha",
+		Frequency: "weekly",
+		Since:     time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		Posts: []PostItem{
+			{Title: "Fest <volunteers>", Group: "Pragyan", Author: "Ravi", URL: "https://example.com/c/pragyan/posts/1"},
+		},
+		Replies: []ReplyItem{
+			{Author: "Meera", PostTitle: "Hostel wifi", Excerpt: "Same here", URL: "https://example.com/c/hostel/posts/2"},
+		},
+		UnsubscribeURL: "https://api.example.com/digest/unsubscribe?token=abc",
+	}
+
+	msg, err := Render("asha@example.com", d)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if msg.To != "asha@example.com" || msg.Subject != "Your weekly WikiNITT digest" {
+		t.Errorf("got To %q, Subject %q", msg.To, msg.Subject)
+	}
+	for _, want := range []string{"Mar 2", "Fest <volunteers>", "Meera on \"Hostel wifi\": Same here", d.UnsubscribeURL} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("text is missing %q:\n%s", want, msg.Text)
+		}
+	}
+	if strings.Contains(msg.Text, "FEATURED ARTICLES") {
+		t.Errorf("text has an empty articles section:\n%s", msg.Text)
+	}
+	if !strings.Contains(msg.HTML, "Fest &lt;volunteers&gt;") {
+		t.Errorf("html doesn't escape titles:\n%s", msg.HTML)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := excerpt("<p>Tom &amp; <b>Jerry</b></p>\n<p>again</p>", 100); got != "Tom & Jerry again" {
+		t.Errorf("excerpt = %q", got)
+	}
+	if got := excerpt("abcdef", 3); got != "abc..." {
+		t.Errorf("excerpt = %q", got)
+	}
+}
+
+type fakeUsers struct {
+	users.Repository
+	byID map[string]*users.User
+}
+
+func (f *fakeUsers) GetByID(ctx context.Context, id string) (*users.User, error) {
+	if u, ok := f.byID[id]; ok {
+		return u, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type fakeCommunity struct {
+	community.Repository
+	groups  []*community.Group
+	posts   []*community.Post
+	replies []*community.Comment
+}
+
+func (f *fakeCommunity) ListGroupsByMember(ctx context.Context, userID string) ([]*community.Group, error) {
+	return f.groups, nil
+}
+
+func (f *fakeCommunity) ListPostsSince(ctx context.Context, groupIDs []string, since time.Time, limit int) ([]*community.Post, error) {
+	return f.posts, nil
+}
+
+func (f *fakeCommunity) ListRepliesTo(ctx context.Context, authorID string, since time.Time, limit int) ([]*community.Comment, error) {
+	return f.replies, nil
+}
+
+func (f *fakeCommunity) GetPost(ctx context.Context, id string) (*community.Post, error) {
+	for _, p := range f.posts {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeCommunity) GetGroupByID(ctx context.Context, id string) (*community.Group, error) {
+	for _, g := range f.groups {
+		if g.ID == id {
+			return g, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+type fakeArticles struct {
+	articles.Repository
+	featured []*articles.Article
+}
+
+func (f *fakeArticles) ListFeaturedSince(ctx context.Context, since time.Time, limit int) ([]*articles.Article, error) {
+	var list []*articles.Article
+	for _, a := range f.featured {
+		if a.FeaturedAt != nil && a.FeaturedAt.After(since) {
+			list = append(list, a)
+		}
+	}
+	return list, nil
+}
+
+type fakeSettings struct {
+	Repository
+	settings map[string]*Settings
+	sentAt   map[string]time.Time
+}
+
+func (f *fakeSettings) Get(ctx context.Context, userID string) (*Settings, error) {
+	return f.settings[userID], nil
+}
+
+func (f *fakeSettings) MarkSent(ctx context.Context, userID string, at time.Time) error {
+	f.sentAt[userID] = at
+	f.settings[userID].LastSentAt = &at
+	return nil
+}
+
+type fakeMailer struct {
+	sent []mailer.Message
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestJobRunUser(t *testing.T) {
+	now := time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)
+	lastSent := now.Add(-7 * 24 * time.Hour)
+	reader := &users.User{ID: "reader", DisplayName: "Asha", Email: "asha@example.com", EmailVerified: true}
+	ravi := &users.User{ID: "ravi", DisplayName: "Ravi"}
+	group := &community.Group{ID: "g1", Name: "Pragyan", Slug: "pragyan"}
+	mine := &community.Post{ID: "p1", GroupID: "g1", AuthorID: "reader", Title: "My post"}
+	theirs := &community.Post{ID: "p2", GroupID: "g1", AuthorID: "ravi", Title: "Fest volunteers"}
+
+	settings := &fakeSettings{settings: map[string]*Settings{}, sentAt: map[string]time.Time{}}
+	mail := &fakeMailer{}
+	job := &Job{
+		Users: &fakeUsers{byID: map[string]*users.User{"reader": reader, "ravi": ravi}},
+		Community: &fakeCommunity{
+			groups:  []*community.Group{group},
+			posts:   []*community.Post{mine, theirs},
+			replies: []*community.Comment{{PostID: "p1", AuthorID: "ravi", Content: "<p>Count me in</p>"}},
+		},
+		Articles: &fakeArticles{featured: []*articles.Article{
+			{Title: "New", Slug: "new", CreatedAt: now.Add(-time.Hour), FeaturedAt: timePtr(now.Add(-time.Hour))},
+			{Title: "Classic", Slug: "classic", CreatedAt: lastSent.Add(-90 * 24 * time.Hour), FeaturedAt: timePtr(now.Add(-2 * time.Hour))},
+			{Title: "Old", Slug: "old", CreatedAt: lastSent.Add(-time.Hour), FeaturedAt: timePtr(lastSent.Add(-time.Hour))},
+		}},
+		Settings:    settings,
+		Mailer:      mail,
+		FrontendURL: "https://example.com/",
+		APIURL:      "https://api.example.com",
+	}
+	for _, id := range []string{"reader", "unverified"} {
+		settings.settings[id] = &Settings{UserID: id, Frequency: FrequencyWeekly, LastSentAt: &lastSent, UnsubscribeToken: "tok"}
+	}
+
+	unverified := &users.User{ID: "unverified", Email: "someone@example.com"}
+	if ok, err := job.runUser(context.Background(), unverified, now); ok || err != nil {
+		t.Fatalf("runUser(unverified) = %v, %v; want no digest", ok, err)
+	}
+	if len(mail.sent) != 0 {
+		t.Fatalf("mailed an unverified address: %+v", mail.sent)
+	}
+
+	ok, err := job.runUser(context.Background(), reader, now)
+	if err != nil || !ok {
+		t.Fatalf("runUser(reader) = %v, %v", ok, err)
+	}
+	if len(mail.sent) != 1 || mail.sent[0].To != reader.Email {
+		t.Fatalf("sent %+v, want one digest to %s", mail.sent, reader.Email)
+	}
+	if !settings.sentAt["reader"].Equal(now) {
+		t.Errorf("LastSentAt not advanced: %v", settings.sentAt["reader"])
+	}
+	text := mail.sent[0].Text
+	for _, want := range []string{"Fest volunteers", "https://example.com/c/pragyan/posts/p2", "Count me in", "New", "Classic", "token=tok"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("digest is missing %q:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"My post\n", "Old"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("digest has %q:\n%s", unwanted, text)
+		}
+	}
+
+	// Nothing is due again until the next period.
+	if ok, err := job.runUser(context.Background(), reader, now.Add(time.Hour)); ok || err != nil {
+		t.Errorf("runUser an hour later = %v, %v; want nothing due", ok, err)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,49 @@
+package digest
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+// writePage sends a minimal HTML page. body must already be escaped.
+func writePage(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>WikiNITT digest</title></head>
+<body style="font-family: sans-serif">%s</body></html>`, body)
+}
+
+// UnsubscribeHandler turns digests off for the token in the query string.
+// GET only shows a confirmation form so that mail scanners following links
+// don't unsubscribe anyone.
+func UnsubscribeHandler(repo Repository) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+
+		switch r.Method {
+		case http.MethodGet:
+			writePage(w, http.StatusOK, fmt.Sprintf(
+				`<p>Stop receiving WikiNITT digest emails?</p><form method="post" action="?token=%s"><button type="submit">Unsubscribe</button></form>`,
+				html.EscapeString(url.QueryEscape(token)),
+			))
+		case http.MethodPost:
+			ok, err := repo.Unsubscribe(r.Context(), token)
+			if err != nil {
+				log.Printf("Failed to unsubscribe from digests: %v", err)
+				writePage(w, http.StatusInternalServerError, "<p>Something went wrong, please try again later.</p>")
+				return
+			}
+			if !ok {
+				writePage(w, http.StatusNotFound, "<p>This unsubscribe link is invalid.</p>")
+				return
+			}
+			writePage(w, http.StatusOK, "<p>You won't receive WikiNITT digests anymore. You can turn them back on in your settings.</p>")
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
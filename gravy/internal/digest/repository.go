@@ -0,0 +1,139 @@
+package digest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily  Frequency = "DAILY"
+	FrequencyWeekly Frequency = "WEEKLY"
+	FrequencyOff    Frequency = "OFF"
+)
+
+// DefaultFrequency applies to users who never picked one.
+const DefaultFrequency = FrequencyWeekly
+
+func (f Frequency) Valid() bool {
+	return f == FrequencyDaily || f == FrequencyWeekly || f == FrequencyOff
+}
+
+// Period is how much activity one digest covers. It is zero for OFF.
+func (f Frequency) Period() time.Duration {
+	switch f {
+	case FrequencyDaily:
+		return 24 * time.Hour
+	case FrequencyWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+type Settings struct {
+	UserID    string    `bson:"userId"`
+	Frequency Frequency `bson:"frequency"`
+	// LastSentAt is when the last digest was built, even if it was empty
+	// and never mailed.
+	LastSentAt *time.Time `bson:"lastSentAt,omitempty"`
+	// UnsubscribeToken turns digests off without signing in.
+	UnsubscribeToken string `bson:"unsubscribeToken"`
+}
+
+type Repository interface {
+	// Get returns the user's settings, creating the defaults on first use.
+	Get(ctx context.Context, userID string) (*Settings, error)
+	SetFrequency(ctx context.Context, userID string, frequency Frequency) (*Settings, error)
+	MarkSent(ctx context.Context, userID string, at time.Time) error
+	// Unsubscribe turns digests off for the token's owner and reports
+	// whether the token was known.
+	Unsubscribe(ctx context.Context, token string) (bool, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{coll: db.Collection("digestSettings")}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// upsert applies update to the user's settings, filling in the defaults
+// when the document doesn't exist yet.
+func (r *repository) upsert(ctx context.Context, userID string, update bson.M) (*Settings, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	onInsert := bson.M{"unsubscribeToken": token}
+	if _, ok := update["$set"]; !ok {
+		onInsert["frequency"] = DefaultFrequency
+	}
+	update["$setOnInsert"] = onInsert
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	settings := &Settings{}
+	err = r.coll.FindOneAndUpdate(ctx, bson.M{"userId": userID}, update, opts).Decode(settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *repository) Get(ctx context.Context, userID string) (*Settings, error) {
+	return r.upsert(ctx, userID, bson.M{})
+}
+
+func (r *repository) SetFrequency(ctx context.Context, userID string, frequency Frequency) (*Settings, error) {
+	return r.upsert(ctx, userID, bson.M{"$set": bson.M{"frequency": frequency}})
+}
+
+func (r *repository) MarkSent(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.coll.UpdateOne(ctx, bson.M{"userId": userID}, bson.M{"$set": bson.M{"lastSentAt": at}})
+	return err
+}
+
+func (r *repository) Unsubscribe(ctx context.Context, token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	res, err := r.coll.UpdateOne(ctx, bson.M{"unsubscribeToken": token}, bson.M{"$set": bson.M{"frequency": FrequencyOff}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "unsubscribeToken", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create digest indexes: %w", err)
+	}
+	return nil
+}
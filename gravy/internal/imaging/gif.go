@@ -0,0 +1,74 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errBadGIF = errors.New("malformed GIF")
+
+// gifFrames walks the blocks of a GIF without decompressing anything and
+// returns how many frames it has and how many pixels they cover together.
+// gif.DecodeAll allocates every frame up front, so this is checked first.
+func gifFrames(data []byte) (frames int, pixels int64, err error) {
+	// Header and logical screen descriptor.
+	if len(data) < 13 {
+		return 0, 0, errBadGIF
+	}
+	pos := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << ((flags & 7) + 1)
+	}
+
+	// skipSubBlocks moves past a chain of data sub-blocks and its
+	// terminator.
+	skipSubBlocks := func() error {
+		for {
+			if pos >= len(data) {
+				return errBadGIF
+			}
+			n := int(data[pos])
+			pos++
+			if n == 0 {
+				return nil
+			}
+			pos += n
+		}
+	}
+
+	for {
+		if pos >= len(data) {
+			return 0, 0, errBadGIF
+		}
+		block := data[pos]
+		pos++
+		switch block {
+		case 0x21: // Extension: label, then sub-blocks.
+			pos++
+			if err := skipSubBlocks(); err != nil {
+				return 0, 0, err
+			}
+		case 0x2C: // Image descriptor.
+			if pos+9 > len(data) {
+				return 0, 0, errBadGIF
+			}
+			w := binary.LittleEndian.Uint16(data[pos+4:])
+			h := binary.LittleEndian.Uint16(data[pos+6:])
+			flags := data[pos+8]
+			pos += 9
+			if flags&0x80 != 0 {
+				pos += 3 << ((flags & 7) + 1)
+			}
+			pos++ // LZW minimum code size
+			if err := skipSubBlocks(); err != nil {
+				return 0, 0, err
+			}
+			frames++
+			pixels += int64(w) * int64(h)
+		case 0x3B: // Trailer.
+			return frames, pixels, nil
+		default:
+			return 0, 0, errBadGIF
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+// Purpose selects the size limits for an upload.
+type Purpose string
+
+const (
+	PurposeAvatar  Purpose = "AVATAR"
+	PurposeArticle Purpose = "ARTICLE"
+	PurposePost    Purpose = "POST" // Community posts, messages and profile content
+)
+
+type limits struct {
+	maxBytes int64
+	maxSide  int // Longest side of the full variant
+}
+
+var purposeLimits = map[Purpose]limits{
+	PurposeAvatar:  {maxBytes: 2 << 20, maxSide: 512},
+	PurposeArticle: {maxBytes: 10 << 20, maxSide: 2560},
+	PurposePost:    {maxBytes: 8 << 20, maxSide: 2048},
+}
+
+func (p Purpose) Valid() bool {
+	_, ok := purposeLimits[p]
+	return ok
+}
+
+// MaxBytes is the largest upload accepted for p.
+func (p Purpose) MaxBytes() int64 {
+	return purposeLimits[p].maxBytes
+}
+
+const (
+	VariantThumbnail = "thumbnail"
+	VariantMedium    = "medium"
+	VariantFull      = "full"
+
+	// maxPixels guards against images that are small on disk but huge once
+	// decoded. It also caps all frames of a GIF together.
+	maxPixels   = 40_000_000
+	jpegQuality = 85
+)
+
+// Smaller variants are only made when they are smaller than the full one.
+var variantSides = []struct {
+	name string
+	side int
+}{
+	{VariantThumbnail, 200},
+	{VariantMedium, 800},
+}
+
+type Variant struct {
+	Name   string
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// Result holds the re-encoded variants, smallest first. Re-encoding drops
+// EXIF and any other metadata of the upload.
+type Result struct {
+	ContentType string
+	Variants    []Variant
+}
+
+// Full returns the largest variant.
+func (r *Result) Full() *Variant {
+	return &r.Variants[len(r.Variants)-1]
+}
+
+// TotalSize is the size of all variants together.
+func (r *Result) TotalSize() int64 {
+	var n int64
+	for _, v := range r.Variants {
+		n += int64(len(v.Data))
+	}
+	return n
+}
+
+// Process validates an uploaded image by its content rather than its name or
+// declared type, and returns it re-encoded in the variants for purpose.
+// JPEG, PNG and GIF are accepted.
+func Process(file io.Reader, purpose Purpose) (*Result, error) {
+	lim, ok := purposeLimits[purpose]
+	if !ok {
+		return nil, fmt.Errorf("invalid image purpose %s", purpose)
+	}
+	data, err := io.ReadAll(io.LimitReader(file, lim.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > lim.maxBytes {
+		return nil, fmt.Errorf("image is too large; the limit is %d MB", lim.maxBytes>>20)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("image is empty")
+	}
+
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/gif" {
+		return nil, fmt.Errorf("unsupported image type %s; use JPEG, PNG or GIF", contentType)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are not supported", cfg.Width, cfg.Height)
+	}
+
+	switch contentType {
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid image: %w", err)
+		}
+		// The orientation lives in the EXIF data being dropped, so it is
+		// applied to the pixels instead.
+		src := orient(toRGBA(img), jpegOrientation(data))
+		return variants(src, lim.maxSide, "image/jpeg", encodeJPEG)
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid image: %w", err)
+		}
+		return variants(toRGBA(img), lim.maxSide, "image/png", png.Encode)
+	default:
+		frames, pixels, err := gifFrames(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image: %w", err)
+		}
+		if frames > 1 && (cfg.Width > lim.maxSide || cfg.Height > lim.maxSide) {
+			return nil, fmt.Errorf("animated GIFs can be at most %dx%d", lim.maxSide, lim.maxSide)
+		}
+		if pixels > maxPixels {
+			return nil, fmt.Errorf("animated GIF has too many frames for its size")
+		}
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil || len(g.Image) == 0 {
+			return nil, fmt.Errorf("invalid image: %v", err)
+		}
+		if len(g.Image) == 1 {
+			return variants(toRGBA(g.Image[0]), lim.maxSide, "image/png", png.Encode)
+		}
+		return animatedVariants(g, lim.maxSide)
+	}
+}
+
+func encodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
+
+func variants(src *image.RGBA, maxSide int, contentType string, encode func(io.Writer, image.Image) error) (*Result, error) {
+	full := src
+	if w, h := fit(src.Rect.Dx(), src.Rect.Dy(), maxSide); w != src.Rect.Dx() || h != src.Rect.Dy() {
+		full = resize(src, w, h)
+	}
+
+	res := &Result{ContentType: contentType}
+	add := func(name string, img *image.RGBA) error {
+		var buf bytes.Buffer
+		if err := encode(&buf, img); err != nil {
+			return fmt.Errorf("failed to encode image: %w", err)
+		}
+		res.Variants = append(res.Variants, Variant{Name: name, Data: buf.Bytes(), Width: img.Rect.Dx(), Height: img.Rect.Dy()})
+		return nil
+	}
+	for _, v := range variantSides {
+		w, h := fit(full.Rect.Dx(), full.Rect.Dy(), v.side)
+		if w == full.Rect.Dx() && h == full.Rect.Dy() {
+			continue
+		}
+		if err := add(v.name, resize(full, w, h)); err != nil {
+			return nil, err
+		}
+	}
+	if err := add(VariantFull, full); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// animatedVariants keeps the animation for the full variant. Smaller
+// variants show the first frame.
+func animatedVariants(g *gif.GIF, maxSide int) (*Result, error) {
+	if g.Config.Width > maxSide || g.Config.Height > maxSide {
+		return nil, fmt.Errorf("animated GIFs can be at most %dx%d", maxSide, maxSide)
+	}
+	var full bytes.Buffer
+	// Only frames and timing are written, which drops comments and
+	// application extensions.
+	err := gif.EncodeAll(&full, &gif.GIF{
+		Image:           g.Image,
+		Delay:           g.Delay,
+		LoopCount:       g.LoopCount,
+		Disposal:        g.Disposal,
+		Config:          g.Config,
+		BackgroundIndex: g.BackgroundIndex,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	first := image.NewRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	draw.Draw(first, g.Image[0].Bounds(), g.Image[0], g.Image[0].Bounds().Min, draw.Over)
+	still, err := variants(first, maxSide, "image/png", png.Encode)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{ContentType: "image/gif", Variants: still.Variants[:len(still.Variants)-1]}
+	res.Variants = append(res.Variants, Variant{Name: VariantFull, Data: full.Bytes(), Width: g.Config.Width, Height: g.Config.Height})
+	return res, nil
+}
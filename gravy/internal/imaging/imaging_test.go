@@ -0,0 +1,184 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// jpegWithOrientation encodes a w×h JPEG and inserts an EXIF segment that
+// carries orientation.
+func jpegWithOrientation(t *testing.T, w, h, orientation int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)), nil); err != nil {
+		t.Fatal(err)
+	}
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01")
+	tiff = binary.BigEndian.AppendUint16(tiff, uint16(orientation))
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestProcessStripsEXIFAndRotates(t *testing.T) {
+	data := jpegWithOrientation(t, 300, 100, 6)
+	if got := jpegOrientation(data); got != 6 {
+		t.Fatalf("jpegOrientation() = %d, want 6", got)
+	}
+
+	res, err := Process(bytes.NewReader(data), PurposePost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full := res.Full()
+	if bytes.Contains(full.Data, []byte("Exif")) {
+		t.Error("full variant still contains EXIF data")
+	}
+	if full.Width != 100 || full.Height != 300 {
+		t.Errorf("full variant is %dx%d, want 100x300 after rotating", full.Width, full.Height)
+	}
+	if res.ContentType != "image/jpeg" {
+		t.Errorf("content type = %s, want image/jpeg", res.ContentType)
+	}
+}
+
+func TestProcessVariants(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3000, 1500))); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Process(&buf, PurposePost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Variant{
+		{Name: VariantThumbnail, Width: 200, Height: 100},
+		{Name: VariantMedium, Width: 800, Height: 400},
+		{Name: VariantFull, Width: 2048, Height: 1024},
+	}
+	if len(res.Variants) != len(want) {
+		t.Fatalf("got %d variants, want %d", len(res.Variants), len(want))
+	}
+	for i, w := range want {
+		v := res.Variants[i]
+		if v.Name != w.Name || v.Width != w.Width || v.Height != w.Height {
+			t.Errorf("variant %d = %s %dx%d, want %s %dx%d", i, v.Name, v.Width, v.Height, w.Name, w.Width, w.Height)
+		}
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	var small bytes.Buffer
+	if err := png.Encode(&small, image.NewRGBA(image.Rect(0, 0, 10, 10))); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		purpose Purpose
+	}{
+		{name: "not an image", data: []byte("<html><script>alert(1)</script></html>"), purpose: PurposePost},
+		{name: "too large", data: make([]byte, PurposeAvatar.MaxBytes()+1), purpose: PurposeAvatar},
+		{name: "unknown purpose", data: small.Bytes(), purpose: "BANNER"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Process(bytes.NewReader(tt.data), tt.purpose); err == nil {
+				t.Error("Process() accepted the upload")
+			}
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 2×1 image with a red left pixel.
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	tests := []struct {
+		orientation int
+		w, h        int
+		redX, redY  int
+	}{
+		{orientation: 1, w: 2, h: 1, redX: 0, redY: 0},
+		{orientation: 2, w: 2, h: 1, redX: 1, redY: 0},
+		{orientation: 3, w: 2, h: 1, redX: 1, redY: 0},
+		{orientation: 6, w: 1, h: 2, redX: 0, redY: 0},
+		{orientation: 8, w: 1, h: 2, redX: 0, redY: 1},
+	}
+	for _, tt := range tests {
+		got := orient(src, tt.orientation)
+		if got.Rect.Dx() != tt.w || got.Rect.Dy() != tt.h {
+			t.Errorf("orientation %d: size %dx%d, want %dx%d", tt.orientation, got.Rect.Dx(), got.Rect.Dy(), tt.w, tt.h)
+			continue
+		}
+		if r, _, _, _ := got.At(tt.redX, tt.redY).RGBA(); r == 0 {
+			t.Errorf("orientation %d: red pixel not at (%d,%d)", tt.orientation, tt.redX, tt.redY)
+		}
+	}
+}
+
+func encodeGIF(t *testing.T, w, h, frames int) []byte {
+	t.Helper()
+	g := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		g.Image = append(g.Image, image.NewPaletted(image.Rect(0, 0, w, h), palette.Plan9))
+		g.Delay = append(g.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGIFFrames(t *testing.T) {
+	frames, pixels, err := gifFrames(encodeGIF(t, 20, 10, 3))
+	if err != nil || frames != 3 || pixels != 600 {
+		t.Fatalf("gifFrames() = %d, %d, %v; want 3, 600", frames, pixels, err)
+	}
+	if _, _, err := gifFrames([]byte("GIF89a")); err == nil {
+		t.Error("gifFrames() accepted a truncated GIF")
+	}
+}
+
+func TestProcessRejectsLargeAnimations(t *testing.T) {
+	if _, err := Process(bytes.NewReader(encodeGIF(t, 600, 10, 2)), PurposeAvatar); err == nil {
+		t.Error("Process() accepted an animation larger than the avatar size")
+	}
+
+	// Repeat the frame of a 512×512 GIF until the frames add up to more
+	// than maxPixels while the file stays small.
+	one := encodeGIF(t, 512, 512, 1)
+	header := 13
+	if flags := one[10]; flags&0x80 != 0 {
+		header += 3 << ((flags & 7) + 1)
+	}
+	frame := one[header : len(one)-1]
+	data := append([]byte{}, one[:header]...)
+	for i := 0; i*512*512 <= maxPixels; i++ {
+		data = append(data, frame...)
+	}
+	data = append(data, 0x3B)
+	if int64(len(data)) > PurposeAvatar.MaxBytes() {
+		t.Fatalf("test GIF is %d bytes, over the avatar limit", len(data))
+	}
+	if _, err := Process(bytes.NewReader(data), PurposeAvatar); err == nil {
+		t.Error("Process() accepted a GIF whose frames exceed the pixel budget")
+	}
+}
@@ -0,0 +1,44 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/uploader"
+)
+
+// Stored is a variant saved to the storage backend.
+type Stored struct {
+	Name   string `bson:"name"`
+	URL    string `bson:"url"`
+	Key    string `bson:"key"`
+	Width  int    `bson:"width"`
+	Height int    `bson:"height"`
+}
+
+// Store uploads every variant of res, smallest first like res.Variants. When
+// one fails, the ones already stored are deleted again.
+func Store(ctx context.Context, storage uploader.Uploader, folder string, res *Result) ([]Stored, error) {
+	stored := make([]Stored, 0, len(res.Variants))
+	for _, v := range res.Variants {
+		f, err := storage.UploadFile(ctx, bytes.NewReader(v.Data), folder)
+		if err != nil {
+			Discard(ctx, storage, stored)
+			return nil, fmt.Errorf("failed to store %s image: %w", v.Name, err)
+		}
+		stored = append(stored, Stored{Name: v.Name, URL: f.URL, Key: f.Key, Width: v.Width, Height: v.Height})
+	}
+	return stored, nil
+}
+
+// Discard deletes stored variants that won't be used after all. Failures are
+// only logged.
+func Discard(ctx context.Context, storage uploader.Uploader, stored []Stored) {
+	for _, s := range stored {
+		if err := storage.Delete(ctx, s.Key); err != nil {
+			log.Printf("Failed to delete image variant %s: %v", s.Key, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package karma
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Source is what a user earned karma for.
+type Source string
+
+const (
+	SourcePost    Source = "POST"
+	SourceComment Source = "COMMENT"
+	SourceArticle Source = "ARTICLE"
+)
+
+func (s Source) field() string {
+	switch s {
+	case SourcePost:
+		return "posts"
+	case SourceComment:
+		return "comments"
+	}
+	return "articles"
+}
+
+// ArticlePoints is the karma an author earns for each published article.
+const ArticlePoints = 10
+
+// Entry holds a user's karma within one group. Karma earned outside groups,
+// such as for articles, is kept under an empty GroupID.
+type Entry struct {
+	UserID    string    `bson:"userId"`
+	GroupID   string    `bson:"groupId"`
+	Posts     int       `bson:"posts"`
+	Comments  int       `bson:"comments"`
+	Articles  int       `bson:"articles"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+func (e *Entry) Total() int {
+	return e.Posts + e.Comments + e.Articles
+}
+
+type Repository interface {
+	// Add changes a user's karma by delta, e.g. +2 when a downvote on their
+	// post becomes an upvote.
+	Add(ctx context.Context, userID, groupID string, source Source, delta int) error
+	Total(ctx context.Context, userID string) (int, error)
+	// Entries returns the user's karma per group, highest first.
+	Entries(ctx context.Context, userID string) ([]*Entry, error)
+	// Rebuild recomputes all karma from votes and articles, returning how
+	// many entries it wrote.
+	Rebuild(ctx context.Context) (int, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	db   *mongo.Database
+	coll *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{db: db, coll: db.Collection("karma")}
+}
+
+func (r *repository) Add(ctx context.Context, userID, groupID string, source Source, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+	_, err := r.coll.UpdateOne(ctx,
+		bson.M{"userId": userID, "groupId": groupID},
+		bson.M{
+			"$inc": bson.M{source.field(): delta},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *repository) Total(ctx context.Context, userID string) (int, error) {
+	cursor, err := r.coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": bson.M{"$add": bson.A{"$posts", "$comments", "$articles"}}},
+		}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var rows []struct {
+		Total int `bson:"total"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Total, nil
+}
+
+func (r *repository) Entries(ctx context.Context, userID string) ([]*Entry, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Total() > entries[j].Total()
+	})
+	return entries, nil
+}
+
+type entryKey struct {
+	userID  string
+	groupID string
+}
+
+const rebuildBatchSize = 500
+
+// Rebuild replaces every entry with karma recounted from the votes on posts
+// and comments, ignoring self-votes, plus ArticlePoints per article. Votes
+// cast while it runs may be lost, so run it when the site is quiet.
+func (r *repository) Rebuild(ctx context.Context) (int, error) {
+	start := time.Now()
+	entries := map[entryKey]*Entry{}
+	entry := func(userID, groupID string) *Entry {
+		key := entryKey{userID, groupID}
+		if entries[key] == nil {
+			entries[key] = &Entry{UserID: userID, GroupID: groupID}
+		}
+		return entries[key]
+	}
+
+	posts, err := r.tallyVotes(ctx, "votes", "postId", "posts", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to tally post votes: %w", err)
+	}
+	for _, t := range posts {
+		entry(t.Key.UserID, t.Key.GroupID).Posts += t.Karma
+	}
+
+	// Comments only know their post, so look it up for the group.
+	comments, err := r.tallyVotes(ctx, "commentVotes", "commentId", "comments", mongo.Pipeline{
+		{{Key: "$addFields", Value: bson.M{"postOid": toObjectID("$target.postId")}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "posts",
+			"localField":   "postOid",
+			"foreignField": "_id",
+			"as":           "post",
+		}}},
+		{{Key: "$unwind", Value: "$post"}},
+		{{Key: "$set", Value: bson.M{"target.groupId": "$post.groupId"}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to tally comment votes: %w", err)
+	}
+	for _, t := range comments {
+		entry(t.Key.UserID, t.Key.GroupID).Comments += t.Karma
+	}
+
+	cursor, err := r.db.Collection("articles").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"authorId": bson.M{"$nin": bson.A{nil, ""}}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$authorId", "n": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count articles: %w", err)
+	}
+	var articles []struct {
+		AuthorID string `bson:"_id"`
+		N        int    `bson:"n"`
+	}
+	if err := cursor.All(ctx, &articles); err != nil {
+		return 0, err
+	}
+	for _, a := range articles {
+		entry(a.AuthorID, "").Articles += a.N * ArticlePoints
+	}
+
+	written := 0
+	var models []mongo.WriteModel
+	flush := func() error {
+		if len(models) == 0 {
+			return nil
+		}
+		if _, err := r.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+		written += len(models)
+		models = models[:0]
+		return nil
+	}
+	for key, e := range entries {
+		e.UpdatedAt = time.Now()
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"userId": key.userID, "groupId": key.groupID}).
+			SetReplacement(e).
+			SetUpsert(true))
+		if len(models) == rebuildBatchSize {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return written, err
+	}
+
+	// Anything not written above no longer has votes or articles behind it.
+	_, err = r.coll.DeleteMany(ctx, bson.M{"updatedAt": bson.M{"$lt": start}})
+	return written, err
+}
+
+type voteTally struct {
+	Key struct {
+		UserID  string `bson:"userId"`
+		GroupID string `bson:"groupId"`
+	} `bson:"_id"`
+	Karma int `bson:"karma"`
+}
+
+// tallyVotes sums the votes in votesColl per author and group of the content
+// they were cast on. resolveGroup may set target.groupId for content that
+// doesn't carry one.
+func (r *repository) tallyVotes(ctx context.Context, votesColl, targetField, targetColl string, resolveGroup mongo.Pipeline) ([]voteTally, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$addFields", Value: bson.M{"targetOid": toObjectID("$" + targetField)}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         targetColl,
+			"localField":   "targetOid",
+			"foreignField": "_id",
+			"as":           "target",
+		}}},
+		{{Key: "$unwind", Value: "$target"}},
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$ne": bson.A{"$target.authorId", "$userId"}}}}},
+	}
+	pipeline = append(pipeline, resolveGroup...)
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{
+		"_id": bson.M{"userId": "$target.authorId", "groupId": "$target.groupId"},
+		"karma": bson.M{"$sum": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$type", "UP"}}, 1, -1,
+		}}},
+	}}})
+
+	cursor, err := r.db.Collection(votesColl).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var tallies []voteTally
+	if err := cursor.All(ctx, &tallies); err != nil {
+		return nil, err
+	}
+	return tallies, nil
+}
+
+// toObjectID converts a hex id field for $lookup, yielding null for ids
+// that aren't valid.
+func toObjectID(field string) bson.M {
+	return bson.M{"$convert": bson.M{"input": field, "to": "objectId", "onError": nil, "onNull": nil}}
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "groupId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create karma indexes: %w", err)
+	}
+	return nil
+}
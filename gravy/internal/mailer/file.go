@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+type fileMailer struct {
+	dir string
+}
+
+// NewFileMailer writes every message to dir as an .eml file. With an empty dir
+// the message is only logged, which is enough to copy links during development.
+func NewFileMailer(dir string) Mailer {
+	return &fileMailer{dir: dir}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	if m.dir == "" {
+		log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Text)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail dir: %w", err)
+	}
+
+	body, err := buildMIME("wikinitt@localhost", msg)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	log.Printf("Mail to %s written to %s", msg.To, path)
+	return nil
+}
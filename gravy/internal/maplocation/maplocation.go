@@ -28,6 +28,7 @@ type Repository interface {
 	Create(ctx context.Context, loc *MapLocation) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context) ([]*MapLocation, error)
+	GetByID(ctx context.Context, id string) (*MapLocation, error)
 }
 
 type repository struct {
@@ -70,3 +71,15 @@ func (r *repository) List(ctx context.Context) ([]*MapLocation, error) {
 	}
 	return locs, nil
 }
+
+func (r *repository) GetByID(ctx context.Context, id string) (*MapLocation, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var loc MapLocation
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&loc); err != nil {
+		return nil, err
+	}
+	return &loc, nil
+}
@@ -0,0 +1,105 @@
+package media
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/quota"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/uploader"
+)
+
+const (
+	// UnusedTTL is how long an upload may go without being used before it is
+	// collected.
+	UnusedTTL = 24 * time.Hour
+
+	gcBatchSize = 100
+)
+
+// Collector deletes media that no article, post, comment, message, avatar or
+// group icon uses.
+type Collector struct {
+	Repo    Repository
+	Storage uploader.Uploader
+	Quota   quota.Repository
+}
+
+// Run collects one batch and returns how many media items were removed.
+// Recorded references can miss content written before media was tracked or
+// edited outside the API, so every candidate is checked against the content
+// itself first. Media found in use gets its references repaired instead.
+func (c *Collector) Run(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.Add(-UnusedTTL)
+	list, err := c.Repo.ListUnused(ctx, cutoff, gcBatchSize)
+	if err != nil || len(list) == 0 {
+		return 0, err
+	}
+	var urls []string
+	for _, m := range list {
+		urls = append(urls, m.URLs...)
+	}
+	found, err := c.Repo.FindReferences(ctx, urls)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, m := range list {
+		if refs := mediaRefs(m, found); len(refs) > 0 {
+			if err := c.Repo.ReplaceReferences(ctx, m.ID, refs); err != nil {
+				log.Printf("Failed to repair references of media %s: %v", m.ID, err)
+			}
+			continue
+		}
+		// Claiming fails if the media was uploaded again or started being
+		// used since it was listed.
+		claimed, err := c.Repo.Claim(ctx, m.ID, cutoff)
+		if err != nil {
+			log.Printf("Failed to claim media %s: %v", m.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := Remove(ctx, c.Repo, c.Storage, c.Quota, m); err != nil {
+			log.Printf("Failed to delete media %s: %v", m.ID, err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Remove deletes the files of m and then its record, and gives the space
+// back to the owner's quota. The record is kept if a file fails to delete, so
+// the next attempt can retry.
+func Remove(ctx context.Context, repo Repository, storage uploader.Uploader, usage quota.Repository, m *Media) error {
+	for _, key := range m.Keys() {
+		if err := storage.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	if err := repo.Delete(ctx, m.ID); err != nil {
+		return err
+	}
+	if err := usage.AddStorage(ctx, m.OwnerID, -m.StoredSize); err != nil {
+		log.Printf("Failed to update storage usage of user %s: %v", m.OwnerID, err)
+	}
+	return nil
+}
+
+// mediaRefs returns the distinct references found for any URL of m.
+func mediaRefs(m *Media, found map[string][]Reference) []Reference {
+	seen := map[Reference]bool{}
+	var refs []Reference
+	for _, u := range m.URLs {
+		for _, ref := range found[u] {
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
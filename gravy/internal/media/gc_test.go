@@ -0,0 +1,147 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/db"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/quota"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/uploader"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// testDatabase connects to WIKINITT_TEST_MONGODB_URI and returns a throwaway
+// database, skipping the test when it isn't set.
+func testDatabase(t *testing.T) (*mongo.Database, context.Context) {
+	t.Helper()
+	uri := os.Getenv("WIKINITT_TEST_MONGODB_URI")
+	if uri == "" {
+		t.Skip("WIKINITT_TEST_MONGODB_URI not set")
+	}
+	client, err := db.Connect(uri)
+	if err != nil {
+		t.Skipf("MongoDB not reachable: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	database := client.Database(fmt.Sprintf("wikinitt_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		_ = database.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+		cancel()
+	})
+	return database, ctx
+}
+
+type fakeStorage struct {
+	uploader.Uploader
+	deleted []string
+}
+
+func (s *fakeStorage) Delete(ctx context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+type fakeQuota struct {
+	quota.Repository
+}
+
+func (fakeQuota) AddStorage(ctx context.Context, userID string, delta int64) error {
+	return nil
+}
+
+func TestCollectorKeepsMediaUsedByMessages(t *testing.T) {
+	database, ctx := testDatabase(t)
+	repo := NewRepository(database)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+
+	newMedia := func(name string) *Media {
+		m := New("u1", imaging.PurposePost, "posts", name+".png", Hash([]byte(name)), "image/png", 10, []imaging.Stored{
+			{Name: "full", URL: "https://cdn.example/" + name + ".png", Key: "posts/" + name + ".png"},
+		})
+		if err := repo.Create(ctx, m); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+		return m
+	}
+	used := newMedia("used")
+	unused := newMedia("unused")
+
+	res, err := database.Collection("messages").InsertOne(ctx, bson.M{
+		"channelId": "c1",
+		"senderId":  "u1",
+		"content":   "look ![](" + used.URL + ")",
+		"createdAt": time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("insert message: %v", err)
+	}
+	messageID := res.InsertedID.(bson.ObjectID).Hex()
+
+	storage := &fakeStorage{}
+	c := &Collector{Repo: repo, Storage: storage, Quota: fakeQuota{}}
+	removed, err := c.Run(ctx, time.Now().Add(2*UnusedTTL))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("Run removed %d media, want 1", removed)
+	}
+	if len(storage.deleted) != 1 || storage.deleted[0] != unused.Key {
+		t.Fatalf("deleted keys = %q, want [%q]", storage.deleted, unused.Key)
+	}
+
+	got, err := repo.Get(ctx, used.ID)
+	if err != nil {
+		t.Fatalf("message media was collected: %v", err)
+	}
+	want := Reference{Type: RefMessage, ID: messageID}
+	if len(got.References) != 1 || got.References[0] != want {
+		t.Fatalf("references = %+v, want [%+v]", got.References, want)
+	}
+}
+
+func TestClaimExcludesReuploads(t *testing.T) {
+	database, ctx := testDatabase(t)
+	repo := NewRepository(database)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+
+	stored := []imaging.Stored{{Name: "full", URL: "https://cdn.example/a.png", Key: "posts/a.png"}}
+	m := New("u1", imaging.PurposePost, "posts", "a.png", Hash([]byte("a")), "image/png", 10, stored)
+	if err := repo.Create(ctx, m); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	cutoff := time.Now().Add(time.Minute)
+
+	// An upload that touched the media first keeps it.
+	if touched, err := repo.Touch(ctx, m.ID, cutoff.Add(time.Minute)); err != nil || !touched {
+		t.Fatalf("Touch = %v, %v, want true", touched, err)
+	}
+	if claimed, err := repo.Claim(ctx, m.ID, cutoff); err != nil || claimed {
+		t.Fatalf("Claim after Touch = %v, %v, want false", claimed, err)
+	}
+
+	// Once claimed, the media is no longer handed out for the same file.
+	if claimed, err := repo.Claim(ctx, m.ID, cutoff.Add(time.Hour)); err != nil || !claimed {
+		t.Fatalf("Claim = %v, %v, want true", claimed, err)
+	}
+	if touched, err := repo.Touch(ctx, m.ID, time.Now()); err != nil || touched {
+		t.Fatalf("Touch after Claim = %v, %v, want false", touched, err)
+	}
+	if got, err := repo.GetByHash(ctx, m.Hash, m.Purpose); err != nil || got != nil {
+		t.Fatalf("GetByHash after Claim = %+v, %v, want nil", got, err)
+	}
+	again := New("u2", imaging.PurposePost, "posts", "a.png", m.Hash, "image/png", 10, stored)
+	if err := repo.Create(ctx, again); err != nil {
+		t.Fatalf("Create after Claim: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+)
+
+type RefType string
+
+const (
+	RefArticle RefType = "ARTICLE"
+	RefPost    RefType = "POST"
+	RefComment RefType = "COMMENT"
+	RefMessage RefType = "MESSAGE"
+	RefUser    RefType = "USER"  // Avatar
+	RefGroup   RefType = "GROUP" // Icon
+)
+
+// Reference is a piece of content that shows a media item.
+type Reference struct {
+	Type RefType `bson:"type"`
+	ID   string  `bson:"id"`
+}
+
+// Media is an uploaded image. Identical uploads for the same purpose are
+// stored once and share a record.
+type Media struct {
+	ID          string           `bson:"_id,omitempty"`
+	OwnerID     string           `bson:"ownerId"`
+	Purpose     imaging.Purpose  `bson:"purpose"`
+	Folder      string           `bson:"folder"`
+	Filename    string           `bson:"filename"`
+	Hash        string           `bson:"hash"` // SHA-256 of the upload as received
+	ContentType string           `bson:"contentType"`
+	Size        int64            `bson:"size"`       // Of the full variant
+	StoredSize  int64            `bson:"storedSize"` // Of all variants, counted against the owner's quota
+	Width       int              `bson:"width"`
+	Height      int              `bson:"height"`
+	URL         string           `bson:"url"`
+	Key         string           `bson:"key"`
+	Variants    []imaging.Stored `bson:"variants"`
+	// URLs holds the URL of every variant so content using any of them can
+	// be matched.
+	URLs       []string    `bson:"urls"`
+	References []Reference `bson:"references"`
+	CreatedAt  time.Time   `bson:"createdAt"`
+	// UploadedAt is bumped when an identical file is uploaded again, so the
+	// new upload gets the full grace period before it must be referenced.
+	UploadedAt time.Time `bson:"uploadedAt"`
+	// Deleting is set once the collector has claimed the media. It is no
+	// longer handed out for identical uploads.
+	Deleting bool `bson:"deleting,omitempty"`
+}
+
+// New describes stored variants, smallest first, of an upload with hash.
+func New(ownerID string, purpose imaging.Purpose, folder, filename, hash, contentType string, size int64, variants []imaging.Stored) *Media {
+	full := variants[len(variants)-1]
+	m := &Media{
+		OwnerID:     ownerID,
+		Purpose:     purpose,
+		Folder:      folder,
+		Filename:    filename,
+		Hash:        hash,
+		ContentType: contentType,
+		Size:        size,
+		Width:       full.Width,
+		Height:      full.Height,
+		URL:         full.URL,
+		Key:         full.Key,
+		Variants:    variants,
+		References:  []Reference{},
+	}
+	for _, v := range variants {
+		m.URLs = append(m.URLs, v.URL)
+	}
+	return m
+}
+
+// Keys returns the storage keys of every variant.
+func (m *Media) Keys() []string {
+	keys := make([]string, 0, len(m.Variants))
+	for _, v := range m.Variants {
+		keys = append(keys, v.Key)
+	}
+	return keys
+}
+
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// urlPattern matches absolute URLs in HTML and Markdown content.
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>()\[\]\\]+`)
+
+// ExtractURLs returns the distinct URLs that appear in texts, such as image
+// sources in content or an avatar field.
+func ExtractURLs(texts ...string) []string {
+	seen := map[string]bool{}
+	urls := []string{}
+	for _, text := range texts {
+		for _, u := range urlPattern.FindAllString(text, -1) {
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+	}
+	return urls
+}
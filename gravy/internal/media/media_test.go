@@ -0,0 +1,46 @@
+package media
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractURLs(t *testing.T) {
+	got := ExtractURLs(
+		`<p>Hi</p><img src="https://cdn.example/a.png"><img src='https://cdn.example/a.png'>`,
+		`![alt](http://localhost:8080/uploads/wikinitt/b.jpg) and https://x.example/c?d=1`,
+		"https://cdn.example/avatar.png",
+		"",
+	)
+	want := []string{
+		"https://cdn.example/a.png",
+		"http://localhost:8080/uploads/wikinitt/b.jpg",
+		"https://x.example/c?d=1",
+		"https://cdn.example/avatar.png",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtractURLs = %q, want %q", got, want)
+	}
+	if got := ExtractURLs("no links here"); got == nil || len(got) != 0 {
+		t.Fatalf("ExtractURLs without links = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestMediaRefs(t *testing.T) {
+	m := &Media{URLs: []string{"https://cdn/thumb.png", "https://cdn/full.png"}}
+	post := Reference{Type: RefPost, ID: "p1"}
+	article := Reference{Type: RefArticle, ID: "a1"}
+	found := map[string][]Reference{
+		"https://cdn/thumb.png": {post},
+		"https://cdn/full.png":  {post, article},
+		"https://cdn/other.png": {{Type: RefUser, ID: "u1"}},
+	}
+	got := mediaRefs(m, found)
+	want := []Reference{post, article}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mediaRefs = %v, want %v", got, want)
+	}
+	if got := mediaRefs(&Media{URLs: []string{"https://cdn/unused.png"}}, found); len(got) != 0 {
+		t.Fatalf("mediaRefs of unused media = %v, want none", got)
+	}
+}
@@ -0,0 +1,297 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/imaging"
+)
+
+// ErrDuplicate is returned by Create when identical media already exists.
+var ErrDuplicate = errors.New("media already exists")
+
+type Filter struct {
+	OwnerID *string
+	Purpose *imaging.Purpose
+	// Unused limits the list to media nothing references.
+	Unused bool
+}
+
+type Repository interface {
+	Create(ctx context.Context, m *Media) error
+	Get(ctx context.Context, id string) (*Media, error)
+	// GetByHash returns nil when no media with the hash exists for purpose.
+	GetByHash(ctx context.Context, hash string, purpose imaging.Purpose) (*Media, error)
+	// Touch restarts the grace period of media and reports false when the
+	// collector has already claimed it.
+	Touch(ctx context.Context, id string, at time.Time) (bool, error)
+	// List returns media newest first. after is the ID of the last item of
+	// the previous page.
+	List(ctx context.Context, filter Filter, after string, limit int) ([]*Media, error)
+	// SetReferences records that ref shows exactly the media among urls.
+	SetReferences(ctx context.Context, ref Reference, urls []string) error
+	ReplaceReferences(ctx context.Context, id string, refs []Reference) error
+	// FindReferences looks through articles, posts, comments, messages,
+	// avatars and group icons for content using urls, regardless of what was
+	// recorded, and returns what uses each URL.
+	FindReferences(ctx context.Context, urls []string) (map[string][]Reference, error)
+	// ListUnused returns media without references uploaded before cutoff.
+	ListUnused(ctx context.Context, cutoff time.Time, limit int) ([]*Media, error)
+	// Claim marks media for deletion if it is still unused and was uploaded
+	// before cutoff. Claimed media leaves deduplication, so an identical
+	// upload gets new files instead of a URL about to disappear.
+	Claim(ctx context.Context, id string, cutoff time.Time) (bool, error)
+	Delete(ctx context.Context, id string) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	db   *mongo.Database
+	coll *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{db: db, coll: db.Collection("media")}
+}
+
+func (r *repository) find(ctx context.Context, filter bson.M, opts ...options.Lister[options.FindOptions]) ([]*Media, error) {
+	cursor, err := r.coll.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var list []*Media
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repository) Create(ctx context.Context, m *Media) error {
+	m.CreatedAt = time.Now()
+	m.UploadedAt = m.CreatedAt
+	res, err := r.coll.InsertOne(ctx, m)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		m.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) Get(ctx context.Context, id string) (*Media, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var m Media
+	if err := r.coll.FindOne(ctx, bson.M{"_id": oid}).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *repository) GetByHash(ctx context.Context, hash string, purpose imaging.Purpose) (*Media, error) {
+	var m Media
+	err := r.coll.FindOne(ctx, bson.M{"hash": hash, "purpose": purpose}).Decode(&m)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *repository) Touch(ctx context.Context, id string, at time.Time) (bool, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": oid, "deleting": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"uploadedAt": at}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (r *repository) List(ctx context.Context, f Filter, after string, limit int) ([]*Media, error) {
+	filter := bson.M{}
+	if f.OwnerID != nil {
+		filter["ownerId"] = *f.OwnerID
+	}
+	if f.Purpose != nil {
+		filter["purpose"] = *f.Purpose
+	}
+	if f.Unused {
+		filter["references"] = bson.M{"$size": 0}
+	}
+	if after != "" {
+		oid, err := bson.ObjectIDFromHex(after)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		filter["_id"] = bson.M{"$lt": oid}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	return r.find(ctx, filter, opts)
+}
+
+func (r *repository) SetReferences(ctx context.Context, ref Reference, urls []string) error {
+	if urls == nil {
+		urls = []string{}
+	}
+	_, err := r.coll.UpdateMany(ctx,
+		bson.M{"references": ref, "urls": bson.M{"$nin": urls}},
+		bson.M{"$pull": bson.M{"references": ref}},
+	)
+	if err != nil || len(urls) == 0 {
+		return err
+	}
+	_, err = r.coll.UpdateMany(ctx,
+		bson.M{"urls": bson.M{"$in": urls}},
+		bson.M{"$addToSet": bson.M{"references": ref}},
+	)
+	return err
+}
+
+func (r *repository) ReplaceReferences(ctx context.Context, id string, refs []Reference) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	if refs == nil {
+		refs = []Reference{}
+	}
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"references": refs}})
+	return err
+}
+
+// referenceSources are the collections and fields FindReferences searches.
+var referenceSources = []struct {
+	coll    string
+	refType RefType
+	fields  []string
+}{
+	{"articles", RefArticle, []string{"content", "thumbnail"}},
+	{"posts", RefPost, []string{"content"}},
+	{"comments", RefComment, []string{"content"}},
+	{"messages", RefMessage, []string{"content"}},
+	{"users", RefUser, []string{"avatar"}},
+	{"groups", RefGroup, []string{"icon"}},
+}
+
+func (r *repository) FindReferences(ctx context.Context, urls []string) (map[string][]Reference, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+	quoted := make([]string, 0, len(urls))
+	for _, u := range urls {
+		quoted = append(quoted, regexp.QuoteMeta(u))
+	}
+	pattern := bson.Regex{Pattern: strings.Join(quoted, "|")}
+
+	refs := map[string][]Reference{}
+	for _, src := range referenceSources {
+		or := bson.A{}
+		projection := bson.M{"_id": 1}
+		for _, field := range src.fields {
+			or = append(or, bson.M{field: pattern})
+			projection[field] = 1
+		}
+		cursor, err := r.db.Collection(src.coll).Find(ctx, bson.M{"$or": or}, options.Find().SetProjection(projection))
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", src.coll, err)
+		}
+		var docs []bson.M
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", src.coll, err)
+		}
+		for _, doc := range docs {
+			id, ok := doc["_id"].(bson.ObjectID)
+			if !ok {
+				continue
+			}
+			ref := Reference{Type: src.refType, ID: id.Hex()}
+			for _, u := range urls {
+				for _, field := range src.fields {
+					if value, _ := doc[field].(string); strings.Contains(value, u) {
+						refs[u] = append(refs[u], ref)
+						break
+					}
+				}
+			}
+		}
+	}
+	return refs, nil
+}
+
+func (r *repository) ListUnused(ctx context.Context, cutoff time.Time, limit int) ([]*Media, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "uploadedAt", Value: 1}}).
+		SetLimit(int64(limit))
+	return r.find(ctx, bson.M{
+		"references": bson.M{"$size": 0},
+		"uploadedAt": bson.M{"$lt": cutoff},
+	}, opts)
+}
+
+func (r *repository) Claim(ctx context.Context, id string, cutoff time.Time) (bool, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	// The hash is unique per purpose, so it is replaced to let a new upload
+	// of the same file be stored while this one is removed.
+	res, err := r.coll.UpdateOne(ctx, bson.M{
+		"_id":        oid,
+		"references": bson.M{"$size": 0},
+		"uploadedAt": bson.M{"$lt": cutoff},
+	}, bson.M{"$set": bson.M{"deleting": true, "hash": "deleting:" + id}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}, {Key: "purpose", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "urls", Value: 1}}},
+		{Keys: bson.D{{Key: "references.type", Value: 1}, {Key: "references.id", Value: 1}}},
+		{Keys: bson.D{{Key: "ownerId", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "uploadedAt", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create media indexes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package mentions
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxPerSource caps how many distinct users and articles one piece of
+// content can mention, so a pasted member list can't spam notifications.
+const maxPerSource = 20
+
+var (
+	// A mention starts a word, so emails like a@b.com don't count.
+	userPattern    = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_.@-])@([a-zA-Z0-9_.-]+)`)
+	articlePattern = regexp.MustCompile(`\[\[([^\[\]\n]+)\]\]`)
+)
+
+// Parsed holds the distinct usernames and article titles found in content,
+// in the order they first appear.
+type Parsed struct {
+	Usernames []string
+	Titles    []string
+}
+
+func (p Parsed) Empty() bool {
+	return len(p.Usernames) == 0 && len(p.Titles) == 0
+}
+
+// Parse finds @username and [[Article Title]] mentions in content.
+func Parse(content string) Parsed {
+	var p Parsed
+
+	seen := make(map[string]bool)
+	for _, m := range userPattern.FindAllStringSubmatch(content, -1) {
+		// Trailing punctuation ends the sentence, not the username.
+		name := strings.TrimRight(m[1], ".-")
+		if name == "" || seen[name] || len(p.Usernames) == maxPerSource {
+			continue
+		}
+		seen[name] = true
+		p.Usernames = append(p.Usernames, name)
+	}
+
+	seen = make(map[string]bool)
+	for _, m := range articlePattern.FindAllStringSubmatch(content, -1) {
+		title := strings.Join(strings.Fields(m[1]), " ")
+		key := strings.ToLower(title)
+		if title == "" || seen[key] || len(p.Titles) == maxPerSource {
+			continue
+		}
+		seen[key] = true
+		p.Titles = append(p.Titles, title)
+	}
+	return p
+}
@@ -0,0 +1,52 @@
+package mentions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		usernames []string
+		titles    []string
+	}{
+		{
+			name:      "users and articles",
+			content:   "Thanks @alice and @bob.smith, see [[Main Building]] and [[ Hostel  Rules ]].",
+			usernames: []string{"alice", "bob.smith"},
+			titles:    []string{"Main Building", "Hostel Rules"},
+		},
+		{
+			name:      "trailing punctuation",
+			content:   "ping @carol.",
+			usernames: []string{"carol"},
+		},
+		{
+			name:    "emails are not mentions",
+			content: "mail me at dave@nitt.edu",
+		},
+		{
+			name:      "duplicates",
+			content:   "@erin @erin [[Library]] [[library]]",
+			usernames: []string{"erin"},
+			titles:    []string{"Library"},
+		},
+		{
+			name:    "unclosed brackets",
+			content: "[[Library",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.content)
+			if !reflect.DeepEqual(got.Usernames, tt.usernames) {
+				t.Errorf("Usernames = %v, want %v", got.Usernames, tt.usernames)
+			}
+			if !reflect.DeepEqual(got.Titles, tt.titles) {
+				t.Errorf("Titles = %v, want %v", got.Titles, tt.titles)
+			}
+		})
+	}
+}
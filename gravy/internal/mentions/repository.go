@@ -0,0 +1,123 @@
+package mentions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// SourceType is the kind of content a mention appears in.
+type SourceType string
+
+const (
+	SourcePost    SourceType = "POST"
+	SourceComment SourceType = "COMMENT"
+	SourceMessage SourceType = "MESSAGE"
+)
+
+type Kind string
+
+const (
+	KindUser    Kind = "USER"
+	KindArticle Kind = "ARTICLE"
+)
+
+// Mention is a resolved @username or [[Article Title]] in a post, comment or
+// message. Text is what the author wrote, without the @ or brackets.
+type Mention struct {
+	ID         string     `bson:"_id,omitempty"`
+	SourceType SourceType `bson:"sourceType"`
+	SourceID   string     `bson:"sourceId"`
+	GroupID    string     `bson:"groupId"`
+	AuthorID   string     `bson:"authorId"`
+	Kind       Kind       `bson:"kind"`
+	Text       string     `bson:"text"`
+	UserID     string     `bson:"userId,omitempty"`
+	ArticleID  string     `bson:"articleId,omitempty"`
+	CreatedAt  time.Time  `bson:"createdAt"`
+}
+
+type Repository interface {
+	// Replace swaps the mentions stored for a source with list and returns
+	// the ones that were there before.
+	Replace(ctx context.Context, sourceType SourceType, sourceID string, list []*Mention) ([]*Mention, error)
+	List(ctx context.Context, sourceType SourceType, sourceID string) ([]*Mention, error)
+	DeleteForSource(ctx context.Context, sourceType SourceType, sourceID string) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{coll: db.Collection("mentions")}
+}
+
+func (r *repository) Replace(ctx context.Context, sourceType SourceType, sourceID string, list []*Mention) ([]*Mention, error) {
+	previous, err := r.List(ctx, sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.DeleteForSource(ctx, sourceType, sourceID); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return previous, nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(list))
+	for _, m := range list {
+		m.SourceType = sourceType
+		m.SourceID = sourceID
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		docs = append(docs, m)
+	}
+	res, err := r.coll.InsertMany(ctx, docs)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range res.InsertedIDs {
+		if oid, ok := id.(bson.ObjectID); ok {
+			list[i].ID = oid.Hex()
+		}
+	}
+	return previous, nil
+}
+
+func (r *repository) List(ctx context.Context, sourceType SourceType, sourceID string) ([]*Mention, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.coll.Find(ctx, bson.M{"sourceType": sourceType, "sourceId": sourceID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var list []*Mention
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repository) DeleteForSource(ctx context.Context, sourceType SourceType, sourceID string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"sourceType": sourceType, "sourceId": sourceID})
+	return err
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sourceType", Value: 1}, {Key: "sourceId", Value: 1}}},
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "articleId", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create mention indexes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBuffer is how many notifications a slow subscriber may fall
+// behind before further ones are dropped. They are still in the inbox.
+const subscriberBuffer = 16
+
+// Notifier saves notifications and pushes them to the recipient's live
+// subscriptions on this server.
+type Notifier struct {
+	repo Repository
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan *Notification]struct{}
+}
+
+func NewNotifier(repo Repository) *Notifier {
+	return &Notifier{repo: repo, subscribers: make(map[string]map[chan *Notification]struct{})}
+}
+
+// Notify delivers a notification unless the recipient turned its type off or
+// caused it themselves. It returns whether the notification was sent.
+func (n *Notifier) Notify(ctx context.Context, notification *Notification) (bool, error) {
+	if notification.UserID == "" || notification.UserID == notification.ActorID {
+		return false, nil
+	}
+	prefs, err := n.repo.GetPreferences(ctx, notification.UserID)
+	if err != nil {
+		return false, err
+	}
+	if !prefs.Enabled(notification.Type) {
+		return false, nil
+	}
+	if err := n.repo.Create(ctx, notification); err != nil {
+		return false, err
+	}
+	n.publish(notification)
+	return true, nil
+}
+
+// Subscribe streams the user's new notifications until ctx is done.
+func (n *Notifier) Subscribe(ctx context.Context, userID string) <-chan *Notification {
+	ch := make(chan *Notification, subscriberBuffer)
+	n.mu.Lock()
+	if n.subscribers[userID] == nil {
+		n.subscribers[userID] = make(map[chan *Notification]struct{})
+	}
+	n.subscribers[userID][ch] = struct{}{}
+	n.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		n.mu.Lock()
+		delete(n.subscribers[userID], ch)
+		if len(n.subscribers[userID]) == 0 {
+			delete(n.subscribers, userID)
+		}
+		n.mu.Unlock()
+		close(ch)
+	}()
+	return ch
+}
+
+func (n *Notifier) publish(notification *Notification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
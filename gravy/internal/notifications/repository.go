@@ -0,0 +1,215 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Type string
+
+const (
+	TypeReply           Type = "REPLY"
+	TypeMention         Type = "MENTION"
+	TypeJoinRequest     Type = "JOIN_REQUEST"
+	TypeRequestAccepted Type = "REQUEST_ACCEPTED"
+	TypePostRemoved     Type = "POST_REMOVED"
+	TypeArticleUpdated  Type = "ARTICLE_UPDATED"
+)
+
+// Types lists every notification type, in the order preferences are shown.
+var Types = []Type{
+	TypeReply,
+	TypeMention,
+	TypeJoinRequest,
+	TypeRequestAccepted,
+	TypePostRemoved,
+	TypeArticleUpdated,
+}
+
+func Valid(t Type) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification tells UserID about something ActorID did. Only the ids that
+// apply to its Type are set.
+type Notification struct {
+	ID        string    `bson:"_id,omitempty"`
+	UserID    string    `bson:"userId"`
+	Type      Type      `bson:"type"`
+	ActorID   string    `bson:"actorId,omitempty"`
+	GroupID   string    `bson:"groupId,omitempty"`
+	PostID    string    `bson:"postId,omitempty"`
+	CommentID string    `bson:"commentId,omitempty"`
+	ChannelID string    `bson:"channelId,omitempty"`
+	ArticleID string    `bson:"articleId,omitempty"`
+	Message   string    `bson:"message"`
+	Read      bool      `bson:"read"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+// Preferences holds the notification types a user turned off. Everything is
+// on by default.
+type Preferences struct {
+	UserID   string `bson:"userId"`
+	Disabled []Type `bson:"disabled"`
+}
+
+func (p *Preferences) Enabled(t Type) bool {
+	for _, d := range p.Disabled {
+		if d == t {
+			return false
+		}
+	}
+	return true
+}
+
+// retention is how long notifications are kept before they expire.
+const retention = 90 * 24 * time.Hour
+
+type Repository interface {
+	Create(ctx context.Context, n *Notification) error
+	// List returns the user's notifications, newest first, starting after
+	// the notification with id after ("" for the first page).
+	List(ctx context.Context, userID, after string, unreadOnly bool, limit int) ([]*Notification, error)
+	UnreadCount(ctx context.Context, userID string) (int64, error)
+	// MarkRead marks the given notifications, or all of them when ids is
+	// empty, as read and returns how many changed.
+	MarkRead(ctx context.Context, userID string, ids []string) (int64, error)
+
+	GetPreferences(ctx context.Context, userID string) (*Preferences, error)
+	SetEnabled(ctx context.Context, userID string, t Type, enabled bool) (*Preferences, error)
+
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll        *mongo.Collection
+	preferences *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{
+		coll:        db.Collection("notifications"),
+		preferences: db.Collection("notificationPreferences"),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, n *Notification) error {
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	res, err := r.coll.InsertOne(ctx, n)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		n.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) List(ctx context.Context, userID, after string, unreadOnly bool, limit int) ([]*Notification, error) {
+	filter := bson.M{"userId": userID}
+	if after != "" {
+		oid, err := bson.ObjectIDFromHex(after)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$lt": oid}
+	}
+	if unreadOnly {
+		filter["read"] = false
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var list []*Notification
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repository) UnreadCount(ctx context.Context, userID string) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{"userId": userID, "read": false})
+}
+
+func (r *repository) MarkRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	filter := bson.M{"userId": userID, "read": false}
+	if len(ids) > 0 {
+		oids := make([]bson.ObjectID, 0, len(ids))
+		for _, id := range ids {
+			oid, err := bson.ObjectIDFromHex(id)
+			if err != nil {
+				return 0, fmt.Errorf("invalid notification id %s", id)
+			}
+			oids = append(oids, oid)
+		}
+		filter["_id"] = bson.M{"$in": oids}
+	}
+	res, err := r.coll.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"read": true}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *repository) GetPreferences(ctx context.Context, userID string) (*Preferences, error) {
+	prefs := &Preferences{UserID: userID}
+	err := r.preferences.FindOne(ctx, bson.M{"userId": userID}).Decode(prefs)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func (r *repository) SetEnabled(ctx context.Context, userID string, t Type, enabled bool) (*Preferences, error) {
+	update := bson.M{"$addToSet": bson.M{"disabled": t}}
+	if enabled {
+		update = bson.M{"$pull": bson.M{"disabled": t}}
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	prefs := &Preferences{}
+	err := r.preferences.FindOneAndUpdate(ctx, bson.M{"userId": userID}, update, opts).Decode(prefs)
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "read", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(retention.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification indexes: %w", err)
+	}
+	_, err = r.preferences.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification preference indexes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package polls
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MinOptions      = 2
+	MaxOptions      = 10
+	maxOptionLength = 200
+)
+
+type Option struct {
+	Text  string `bson:"text"`
+	Count int    `bson:"count"`
+}
+
+// Poll is attached to a community post. Option counts are kept on the poll
+// and adjusted as votes are cast and retracted.
+type Poll struct {
+	ID             string     `bson:"_id,omitempty"`
+	PostID         string     `bson:"postId"`
+	Options        []Option   `bson:"options"`
+	MultipleChoice bool       `bson:"multipleChoice"`
+	Anonymous      bool       `bson:"anonymous"` // Voters aren't shown
+	ClosesAt       *time.Time `bson:"closesAt,omitempty"`
+	Voters         int        `bson:"voters"`
+	CreatedAt      time.Time  `bson:"createdAt"`
+}
+
+// Vote records the options one user picked, by index.
+type Vote struct {
+	ID        string    `bson:"_id,omitempty"`
+	PollID    string    `bson:"pollId"`
+	UserID    string    `bson:"userId"`
+	Choices   []int     `bson:"choices"`
+	CreatedAt time.Time `bson:"createdAt"`
+	// Retracted votes are kept without choices, since the voter has already
+	// seen the results.
+	Retracted bool `bson:"retracted,omitempty"`
+}
+
+// New validates the options of a poll being created at now.
+func New(options []string, multipleChoice, anonymous bool, closesAt *time.Time, now time.Time) (*Poll, error) {
+	if len(options) < MinOptions || len(options) > MaxOptions {
+		return nil, fmt.Errorf("a poll needs between %d and %d options", MinOptions, MaxOptions)
+	}
+	poll := &Poll{
+		Options:        make([]Option, 0, len(options)),
+		MultipleChoice: multipleChoice,
+		Anonymous:      anonymous,
+		CreatedAt:      now,
+	}
+	seen := make(map[string]bool, len(options))
+	for _, text := range options {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, fmt.Errorf("poll options can't be empty")
+		}
+		if utf8.RuneCountInString(text) > maxOptionLength {
+			return nil, fmt.Errorf("poll options can be at most %d characters", maxOptionLength)
+		}
+		key := strings.ToLower(text)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate poll option %q", text)
+		}
+		seen[key] = true
+		poll.Options = append(poll.Options, Option{Text: text})
+	}
+	if closesAt != nil {
+		if !closesAt.After(now) {
+			return nil, fmt.Errorf("poll closing time must be in the future")
+		}
+		poll.ClosesAt = closesAt
+	}
+	return poll, nil
+}
+
+func (p *Poll) Closed(now time.Time) bool {
+	return p.ClosesAt != nil && !now.Before(*p.ClosesAt)
+}
+
+// CheckChoices validates option indexes for a vote and returns them sorted
+// without duplicates.
+func (p *Poll) CheckChoices(choices []int) ([]int, error) {
+	seen := make(map[int]bool, len(choices))
+	result := make([]int, 0, len(choices))
+	for _, c := range choices {
+		if c < 0 || c >= len(p.Options) {
+			return nil, fmt.Errorf("invalid poll option %d", c)
+		}
+		if !seen[c] {
+			seen[c] = true
+			result = append(result, c)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("pick at least one option")
+	}
+	if len(result) > 1 && !p.MultipleChoice {
+		return nil, fmt.Errorf("this poll allows only one choice")
+	}
+	sort.Ints(result)
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package polls
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		options  []string
+		closesAt *time.Time
+		wantErr  bool
+	}{
+		{name: "valid", options: []string{"Yes", " No "}},
+		{name: "too few options", options: []string{"Yes"}, wantErr: true},
+		{name: "empty option", options: []string{"Yes", "  "}, wantErr: true},
+		{name: "duplicate options", options: []string{"Yes", "yes"}, wantErr: true},
+		{name: "closes in the past", options: []string{"Yes", "No"}, closesAt: &past, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll, err := New(tt.options, false, false, tt.closesAt, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && poll.Options[1].Text != "No" {
+				t.Errorf("option text = %q, want trimmed", poll.Options[1].Text)
+			}
+		})
+	}
+}
+
+func TestCheckChoices(t *testing.T) {
+	single := &Poll{Options: make([]Option, 3)}
+	multiple := &Poll{Options: make([]Option, 3), MultipleChoice: true}
+
+	tests := []struct {
+		name    string
+		poll    *Poll
+		choices []int
+		want    []int
+		wantErr bool
+	}{
+		{name: "single", poll: single, choices: []int{1}, want: []int{1}},
+		{name: "single with repeats", poll: single, choices: []int{2, 2}, want: []int{2}},
+		{name: "several on single choice", poll: single, choices: []int{0, 1}, wantErr: true},
+		{name: "several", poll: multiple, choices: []int{2, 0, 2}, want: []int{0, 2}},
+		{name: "out of range", poll: multiple, choices: []int{3}, wantErr: true},
+		{name: "none", poll: multiple, choices: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.poll.CheckChoices(tt.choices)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckChoices() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckChoices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package polls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	ErrAlreadyVoted = errors.New("you already voted in this poll")
+	ErrNotVoted     = errors.New("you haven't voted in this poll")
+)
+
+type Repository interface {
+	Create(ctx context.Context, poll *Poll) error
+	// GetByPost returns the post's poll, or nil when it has none.
+	GetByPost(ctx context.Context, postID string) (*Poll, error)
+	// Vote records the user's choices and returns the updated poll. Each
+	// user votes once; ErrAlreadyVoted is returned for a second vote.
+	Vote(ctx context.Context, poll *Poll, userID string, choices []int) (*Poll, error)
+	// Retract clears the user's choices so they can vote again.
+	Retract(ctx context.Context, poll *Poll, userID string) (*Poll, error)
+	// GetVote returns the user's vote, or nil when they never voted. A
+	// retracted vote is returned with Retracted set.
+	GetVote(ctx context.Context, pollID, userID string) (*Vote, error)
+	// ListVoters returns the IDs of the first users who picked option.
+	ListVoters(ctx context.Context, pollID string, option, limit int) ([]string, error)
+	DeleteForPost(ctx context.Context, postID string) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	polls *mongo.Collection
+	votes *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{
+		polls: db.Collection("polls"),
+		votes: db.Collection("pollVotes"),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, poll *Poll) error {
+	res, err := r.polls.InsertOne(ctx, poll)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		poll.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) GetByPost(ctx context.Context, postID string) (*Poll, error) {
+	var poll Poll
+	err := r.polls.FindOne(ctx, bson.M{"postId": postID}).Decode(&poll)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// adjust moves the counts of choices and the voter count by delta.
+func (r *repository) adjust(ctx context.Context, pollID string, choices []int, delta int) (*Poll, error) {
+	oid, err := bson.ObjectIDFromHex(pollID)
+	if err != nil {
+		return nil, err
+	}
+	inc := bson.M{"voters": delta}
+	for _, c := range choices {
+		inc["options."+strconv.Itoa(c)+".count"] = delta
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var poll Poll
+	if err := r.polls.FindOneAndUpdate(ctx, bson.M{"_id": oid}, bson.M{"$inc": inc}, opts).Decode(&poll); err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// The unique pollId+userId index on votes is what enforces one vote per user,
+// so counts are only adjusted once the vote document was written or removed.
+func (r *repository) Vote(ctx context.Context, poll *Poll, userID string, choices []int) (*Poll, error) {
+	now := time.Now()
+	res, err := r.votes.UpdateOne(ctx,
+		bson.M{"pollId": poll.ID, "userId": userID, "retracted": true},
+		bson.M{
+			"$set":   bson.M{"choices": choices, "createdAt": now},
+			"$unset": bson.M{"retracted": ""},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.MatchedCount > 0 {
+		return r.adjust(ctx, poll.ID, choices, 1)
+	}
+
+	_, err = r.votes.InsertOne(ctx, &Vote{
+		PollID:    poll.ID,
+		UserID:    userID,
+		Choices:   choices,
+		CreatedAt: now,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrAlreadyVoted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.adjust(ctx, poll.ID, choices, 1)
+}
+
+func (r *repository) Retract(ctx context.Context, poll *Poll, userID string) (*Poll, error) {
+	var vote Vote
+	err := r.votes.FindOneAndUpdate(ctx,
+		bson.M{"pollId": poll.ID, "userId": userID, "retracted": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"choices": bson.A{}, "retracted": true}},
+	).Decode(&vote)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotVoted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.adjust(ctx, poll.ID, vote.Choices, -1)
+}
+
+func (r *repository) GetVote(ctx context.Context, pollID, userID string) (*Vote, error) {
+	var vote Vote
+	err := r.votes.FindOne(ctx, bson.M{"pollId": pollID, "userId": userID}).Decode(&vote)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+func (r *repository) ListVoters(ctx context.Context, pollID string, option, limit int) ([]string, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"userId": 1})
+	cursor, err := r.votes.Find(ctx, bson.M{"pollId": pollID, "choices": option}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var votes []Vote
+	if err := cursor.All(ctx, &votes); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(votes))
+	for _, v := range votes {
+		ids = append(ids, v.UserID)
+	}
+	return ids, nil
+}
+
+func (r *repository) DeleteForPost(ctx context.Context, postID string) error {
+	poll, err := r.GetByPost(ctx, postID)
+	if err != nil || poll == nil {
+		return err
+	}
+	if _, err := r.votes.DeleteMany(ctx, bson.M{"pollId": poll.ID}); err != nil {
+		return err
+	}
+	_, err = r.polls.DeleteMany(ctx, bson.M{"postId": postID})
+	return err
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.polls.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "postId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create poll indexes: %w", err)
+	}
+	_, err = r.votes.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "pollId", Value: 1}, {Key: "userId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "pollId", Value: 1}, {Key: "choices", Value: 1}, {Key: "createdAt", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create poll vote indexes: %w", err)
+	}
+	return nil
+}
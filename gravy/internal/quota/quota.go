@@ -0,0 +1,57 @@
+package quota
+
+import (
+	"fmt"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+)
+
+// Limits caps how much a user may keep in storage and how many files they may
+// upload per UTC day.
+type Limits struct {
+	StorageBytes int64
+	DailyUploads int
+}
+
+var roleLimits = map[roles.Role]Limits{
+	roles.User:       {StorageBytes: 200 << 20, DailyUploads: 50},
+	roles.MapManager: {StorageBytes: 500 << 20, DailyUploads: 200},
+	roles.Moderator:  {StorageBytes: 500 << 20, DailyUploads: 200},
+	roles.Editor:     {StorageBytes: 2 << 30, DailyUploads: 500},
+	roles.Admin:      {StorageBytes: 5 << 30, DailyUploads: 1000},
+	roles.SuperAdmin: {StorageBytes: 5 << 30, DailyUploads: 1000},
+}
+
+// For returns the most generous limits among the user's roles.
+func For(rs []roles.Role) Limits {
+	limits := roleLimits[roles.User]
+	for _, r := range rs {
+		l, ok := roleLimits[r]
+		if !ok {
+			continue
+		}
+		limits.StorageBytes = max(limits.StorageBytes, l.StorageBytes)
+		limits.DailyUploads = max(limits.DailyUploads, l.DailyUploads)
+	}
+	return limits
+}
+
+type Kind string
+
+const (
+	KindStorage Kind = "STORAGE"
+	KindDaily   Kind = "DAILY_UPLOADS"
+)
+
+// ExceededError is returned when an upload would go over a limit.
+type ExceededError struct {
+	Kind  Kind
+	Limit int64
+}
+
+func (e *ExceededError) Error() string {
+	if e.Kind == KindDaily {
+		return fmt.Sprintf("upload quota exceeded: at most %d uploads per day", e.Limit)
+	}
+	return fmt.Sprintf("upload quota exceeded: at most %d MB of storage", e.Limit>>20)
+}
@@ -0,0 +1,44 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
+)
+
+func TestFor(t *testing.T) {
+	if got := For(nil); got != roleLimits[roles.User] {
+		t.Fatalf("For(nil) = %+v, want the USER limits", got)
+	}
+	got := For([]roles.Role{roles.User, roles.Moderator, roles.Editor})
+	want := Limits{StorageBytes: roleLimits[roles.Editor].StorageBytes, DailyUploads: roleLimits[roles.Editor].DailyUploads}
+	if got != want {
+		t.Fatalf("For(user, moderator, editor) = %+v, want %+v", got, want)
+	}
+	if got := For([]roles.Role{"UNKNOWN"}); got != roleLimits[roles.User] {
+		t.Fatalf("For(unknown) = %+v, want the USER limits", got)
+	}
+}
+
+func TestResetsAt(t *testing.T) {
+	now := time.Date(2026, 10, 19, 3, 0, 0, 0, time.FixedZone("IST", 5*3600+1800))
+	want := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	if got := ResetsAt(now); !got.Equal(want) {
+		t.Fatalf("ResetsAt(%v) = %v, want %v", now, got, want)
+	}
+	if day(now) != "2026-10-18" {
+		t.Fatalf("day(%v) = %s, want 2026-10-18", now, day(now))
+	}
+}
+
+func TestExceededError(t *testing.T) {
+	storage := &ExceededError{Kind: KindStorage, Limit: 200 << 20}
+	if got := storage.Error(); got != "upload quota exceeded: at most 200 MB of storage" {
+		t.Fatalf("storage error = %q", got)
+	}
+	daily := &ExceededError{Kind: KindDaily, Limit: 50}
+	if got := daily.Error(); got != "upload quota exceeded: at most 50 uploads per day" {
+		t.Fatalf("daily error = %q", got)
+	}
+}
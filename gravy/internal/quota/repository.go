@@ -0,0 +1,146 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Usage is what a user has stored and how many files they uploaded on Day.
+type Usage struct {
+	UserID       string `bson:"userId"`
+	StorageBytes int64  `bson:"storageBytes"`
+	Day          string `bson:"day"` // UTC, 2006-01-02
+	DayUploads   int    `bson:"dayUploads"`
+}
+
+func day(now time.Time) string {
+	return now.UTC().Format("2006-01-02")
+}
+
+// ResetsAt is when the daily upload count after now starts over.
+func ResetsAt(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
+
+type Repository interface {
+	// Get returns the user's usage, with DayUploads counting today only.
+	Get(ctx context.Context, userID string, now time.Time) (*Usage, error)
+	// Reserve counts an upload of bytes against the user's limits, or
+	// returns an *ExceededError without counting it.
+	Reserve(ctx context.Context, userID string, limits Limits, bytes int64, now time.Time) error
+	// Cancel gives back a reservation for an upload that failed.
+	Cancel(ctx context.Context, userID string, bytes int64, now time.Time) error
+	// AddStorage corrects the stored bytes, e.g. once the real size of a
+	// reserved upload is known or when files are deleted. Usage never goes
+	// below zero.
+	AddStorage(ctx context.Context, userID string, delta int64) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{coll: db.Collection("uploadUsage")}
+}
+
+func (r *repository) Get(ctx context.Context, userID string, now time.Time) (*Usage, error) {
+	usage := &Usage{UserID: userID}
+	err := r.coll.FindOne(ctx, bson.M{"userId": userID}).Decode(usage)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	if usage.Day != day(now) {
+		usage.Day = day(now)
+		usage.DayUploads = 0
+	}
+	return usage, nil
+}
+
+func (r *repository) Reserve(ctx context.Context, userID string, limits Limits, bytes int64, now time.Time) error {
+	today := day(now)
+	// Create the document if needed and start the count over on a new day.
+	rollover := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "day", Value: today},
+		{Key: "dayUploads", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{"$day", today}}}, "$dayUploads", 0,
+		}}}},
+		{Key: "storageBytes", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$storageBytes", 0}}}},
+	}}}}
+	_, err := r.coll.UpdateOne(ctx, bson.M{"userId": userID}, rollover, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// Another first upload created the document between our find and
+		// insert, so the retry updates it instead.
+		_, err = r.coll.UpdateOne(ctx, bson.M{"userId": userID}, rollover, options.UpdateOne().SetUpsert(true))
+	}
+	if err != nil {
+		return err
+	}
+
+	// Checking and counting in one update keeps concurrent uploads from
+	// slipping past the limits together.
+	res, err := r.coll.UpdateOne(ctx, bson.M{
+		"userId":       userID,
+		"day":          today,
+		"dayUploads":   bson.M{"$lt": limits.DailyUploads},
+		"storageBytes": bson.M{"$lte": limits.StorageBytes - bytes},
+	}, bson.M{"$inc": bson.M{"dayUploads": 1, "storageBytes": bytes}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	usage, err := r.Get(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+	if usage.DayUploads >= limits.DailyUploads {
+		return &ExceededError{Kind: KindDaily, Limit: int64(limits.DailyUploads)}
+	}
+	return &ExceededError{Kind: KindStorage, Limit: limits.StorageBytes}
+}
+
+func (r *repository) Cancel(ctx context.Context, userID string, bytes int64, now time.Time) error {
+	// A reservation made before midnight stays counted for that day.
+	_, err := r.coll.UpdateOne(ctx,
+		bson.M{"userId": userID, "day": day(now), "dayUploads": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"dayUploads": -1}},
+	)
+	if err != nil {
+		return err
+	}
+	return r.AddStorage(ctx, userID, -bytes)
+}
+
+func (r *repository) AddStorage(ctx context.Context, userID string, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "storageBytes", Value: bson.D{{Key: "$max", Value: bson.A{
+			0, bson.D{{Key: "$add", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$storageBytes", 0}}}, delta}}},
+		}}}},
+	}}}}
+	_, err := r.coll.UpdateOne(ctx, bson.M{"userId": userID}, update)
+	return err
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create upload usage indexes: %w", err)
+	}
+	return nil
+}
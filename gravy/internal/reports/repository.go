@@ -0,0 +1,252 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TargetType string
+
+const (
+	TargetPost    TargetType = "POST"
+	TargetComment TargetType = "COMMENT"
+	TargetMessage TargetType = "MESSAGE"
+)
+
+type Reason string
+
+const (
+	ReasonSpam           Reason = "SPAM"
+	ReasonHarassment     Reason = "HARASSMENT"
+	ReasonHateSpeech     Reason = "HATE_SPEECH"
+	ReasonNSFW           Reason = "NSFW"
+	ReasonMisinformation Reason = "MISINFORMATION"
+	ReasonOther          Reason = "OTHER"
+	ReasonAutomod        Reason = "AUTOMOD"
+)
+
+type Status string
+
+const (
+	StatusOpen      Status = "OPEN"
+	StatusResolved  Status = "RESOLVED"
+	StatusDismissed Status = "DISMISSED"
+)
+
+type Action string
+
+const (
+	ActionDismiss       Action = "DISMISS"
+	ActionRemoveContent Action = "REMOVE_CONTENT"
+	ActionWarnUser      Action = "WARN_USER"
+	ActionBanUser       Action = "BAN_USER"
+)
+
+var (
+	ErrAlreadyReported = errors.New("you have already reported this")
+	ErrNotOpen         = errors.New("report is not open")
+)
+
+// Entry is one user's report. Reports of the same target are collected on a
+// single Case while it is open.
+type Entry struct {
+	ReporterID string    `bson:"reporterId"`
+	Reason     Reason    `bson:"reason"`
+	Note       string    `bson:"note,omitempty"`
+	CreatedAt  time.Time `bson:"createdAt"`
+}
+
+type Resolution struct {
+	Action      Action    `bson:"action"`
+	ModeratorID string    `bson:"moderatorId"`
+	Note        string    `bson:"note,omitempty"`
+	ResolvedAt  time.Time `bson:"resolvedAt"`
+}
+
+type Case struct {
+	ID             string      `bson:"_id,omitempty"`
+	TargetType     TargetType  `bson:"targetType"`
+	TargetID       string      `bson:"targetId"`
+	TargetAuthorID string      `bson:"targetAuthorId"`
+	GroupID        string      `bson:"groupId"`
+	Status         Status      `bson:"status"`
+	Reports        []Entry     `bson:"reports"`
+	ReportCount    int         `bson:"reportCount"`
+	Resolution     *Resolution `bson:"resolution,omitempty"`
+	CreatedAt      time.Time   `bson:"createdAt"`
+	UpdatedAt      time.Time   `bson:"updatedAt"`
+}
+
+// Warning is issued to the author of reported content.
+type Warning struct {
+	ID          string    `bson:"_id,omitempty"`
+	UserID      string    `bson:"userId"`
+	ModeratorID string    `bson:"moderatorId"`
+	CaseID      string    `bson:"caseId"`
+	Note        string    `bson:"note,omitempty"`
+	CreatedAt   time.Time `bson:"createdAt"`
+}
+
+// Target identifies the reported content.
+type Target struct {
+	Type     TargetType
+	ID       string
+	AuthorID string
+	GroupID  string
+}
+
+type QueueFilter struct {
+	Status   Status
+	GroupIDs []string // nil for every group
+}
+
+type Repository interface {
+	// Add files a report, opening a case for the target if none is open.
+	Add(ctx context.Context, target Target, entry Entry) (*Case, error)
+	GetByID(ctx context.Context, id string) (*Case, error)
+	ListQueue(ctx context.Context, filter QueueFilter, limit, offset int) ([]*Case, error)
+	Resolve(ctx context.Context, id string, status Status, resolution Resolution) (*Case, error)
+	AddWarning(ctx context.Context, warning *Warning) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	coll     *mongo.Collection
+	warnings *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{
+		coll:     db.Collection("reports"),
+		warnings: db.Collection("userWarnings"),
+	}
+}
+
+func (r *repository) Add(ctx context.Context, target Target, entry Entry) (*Case, error) {
+	now := time.Now()
+	filter := bson.M{
+		"targetType":         target.Type,
+		"targetId":           target.ID,
+		"status":             StatusOpen,
+		"reports.reporterId": bson.M{"$ne": entry.ReporterID},
+	}
+	update := bson.M{
+		"$push": bson.M{"reports": entry},
+		"$inc":  bson.M{"reportCount": 1},
+		"$set":  bson.M{"updatedAt": now},
+		"$setOnInsert": bson.M{
+			"targetAuthorId": target.AuthorID,
+			"groupId":        target.GroupID,
+			"createdAt":      now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var c Case
+	err := r.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&c)
+	// The reporter is already on the open case, so the upsert collided with
+	// the one-open-case-per-target index.
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrAlreadyReported
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id string) (*Case, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var c Case
+	if err := r.coll.FindOne(ctx, bson.M{"_id": oid}).Decode(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *repository) ListQueue(ctx context.Context, f QueueFilter, limit, offset int) ([]*Case, error) {
+	filter := bson.M{"status": f.Status}
+	if f.GroupIDs != nil {
+		filter["groupId"] = bson.M{"$in": f.GroupIDs}
+	}
+	// Most reported first, then oldest.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "reportCount", Value: -1}, {Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var cases []*Case
+	if err := cursor.All(ctx, &cases); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+func (r *repository) Resolve(ctx context.Context, id string, status Status, resolution Resolution) (*Case, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	resolution.ResolvedAt = time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var c Case
+	err = r.coll.FindOneAndUpdate(ctx, bson.M{
+		"_id":    oid,
+		"status": StatusOpen,
+	}, bson.M{"$set": bson.M{
+		"status":     status,
+		"resolution": resolution,
+		"updatedAt":  resolution.ResolvedAt,
+	}}, opts).Decode(&c)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotOpen
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *repository) AddWarning(ctx context.Context, warning *Warning) error {
+	warning.CreatedAt = time.Now()
+	res, err := r.warnings.InsertOne(ctx, warning)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		warning.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": StatusOpen}),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "groupId", Value: 1}, {Key: "reportCount", Value: -1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.warnings.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+	})
+	return err
+}
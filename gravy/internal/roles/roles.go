@@ -0,0 +1,63 @@
+package roles
+
+type Role string
+
+const (
+	User       Role = "USER"
+	Editor     Role = "EDITOR"
+	MapManager Role = "MAP_MANAGER"
+	Moderator  Role = "MODERATOR"
+	Admin      Role = "ADMIN"
+	SuperAdmin Role = "SUPERADMIN"
+)
+
+type Permission string
+
+const (
+	ArticlesWrite     Permission = "ARTICLES_WRITE"
+	CategoriesWrite   Permission = "CATEGORIES_WRITE"
+	MapWrite          Permission = "MAP_WRITE"
+	CommunityModerate Permission = "COMMUNITY_MODERATE"
+	UsersView         Permission = "USERS_VIEW"
+	UsersBan          Permission = "USERS_BAN"
+	RolesManage       Permission = "ROLES_MANAGE"
+	AuditView         Permission = "AUDIT_VIEW"
+	WebhooksManage    Permission = "WEBHOOKS_MANAGE"
+	MediaManage       Permission = "MEDIA_MANAGE"
+)
+
+var rolePermissions = map[Role][]Permission{
+	User:       {},
+	Editor:     {ArticlesWrite, CategoriesWrite},
+	MapManager: {MapWrite},
+	Moderator:  {CommunityModerate, UsersView, UsersBan},
+	Admin: {
+		ArticlesWrite, CategoriesWrite, MapWrite,
+		CommunityModerate, UsersView, UsersBan, RolesManage, AuditView,
+		WebhooksManage, MediaManage,
+	},
+	SuperAdmin: {
+		ArticlesWrite, CategoriesWrite, MapWrite,
+		CommunityModerate, UsersView, UsersBan, RolesManage, AuditView,
+		WebhooksManage, MediaManage,
+	},
+}
+
+func Valid(r Role) bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Privileged roles can only be granted or revoked by a SUPERADMIN.
+func Privileged(r Role) bool {
+	return r == Admin || r == SuperAdmin
+}
+
+func Grants(r Role, perm Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
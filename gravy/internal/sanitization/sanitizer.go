@@ -1,6 +1,9 @@
 package sanitization
 
 import (
+	"html"
+	"strings"
+
 	"github.com/microcosm-cc/bluemonday"
 )
 
@@ -13,3 +16,8 @@ func SanitizeString(input string) string {
 	p := bluemonday.StrictPolicy()
 	return p.Sanitize(input)
 }
+
+// PlainText strips the markup from HTML content and collapses whitespace.
+func PlainText(input string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(SanitizeString(input))), " ")
+}
@@ -0,0 +1,229 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+type ArticleSort string
+
+const (
+	ArticleSortRelevance ArticleSort = "RELEVANCE"
+	ArticleSortNewest    ArticleSort = "NEWEST"
+	ArticleSortOldest    ArticleSort = "OLDEST"
+	ArticleSortTitle     ArticleSort = "TITLE"
+)
+
+// ArticleFilter narrows an article search. Zero fields match everything.
+type ArticleFilter struct {
+	// Categories matches articles in any of the categories.
+	Categories []string
+	AuthorID   string
+	Since      *time.Time
+	Until      *time.Time
+	Featured   *bool
+}
+
+type ArticleQuery struct {
+	Query  string
+	Filter ArticleFilter
+	Sort   ArticleSort
+	Limit  int
+	Offset int
+}
+
+// ArticleHit is a matching article. Title and Snippet are HTML with the
+// matched words wrapped in <mark>; everything else in them is escaped.
+type ArticleHit struct {
+	ID      string
+	Title   string
+	Snippet string
+}
+
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// ArticleResult holds a page of hits and, for the whole result set, how many
+// articles have each category, author and featured value. Facets are sorted
+// by count, highest first.
+type ArticleResult struct {
+	Hits       []ArticleHit
+	Total      int
+	Categories []FacetCount
+	Authors    []FacetCount
+	Featured   []FacetCount
+}
+
+var (
+	articleFilterableAttributes = []string{"category", "authorID", "createdAt", "featured"}
+	articleSortableAttributes   = []string{"createdAt", "title"}
+	articleFacets               = []string{"category", "authorID", "featured"}
+)
+
+// Meilisearch wraps matches in these; they are swapped for <mark> once the
+// rest of the text has been escaped. Private use characters never appear in
+// indexed text.
+const (
+	highlightPre  = "\ue000"
+	highlightPost = "\ue001"
+)
+
+const snippetWords = 24
+
+func (c *Client) configureArticles() error {
+	filterable := make([]interface{}, len(articleFilterableAttributes))
+	for i, v := range articleFilterableAttributes {
+		filterable[i] = v
+	}
+	task, err := c.client.Index("articles").UpdateFilterableAttributes(&filterable)
+	if err != nil {
+		return fmt.Errorf("failed to update filterable attributes for articles: %w", err)
+	}
+	log.Printf("Update articles filterable attributes task: %v", task.TaskUID)
+
+	sortable := articleSortableAttributes
+	task, err = c.client.Index("articles").UpdateSortableAttributes(&sortable)
+	if err != nil {
+		return fmt.Errorf("failed to update sortable attributes for articles: %w", err)
+	}
+	log.Printf("Update articles sortable attributes task: %v", task.TaskUID)
+	return nil
+}
+
+func (c *Client) SearchArticles(ctx context.Context, q ArticleQuery) (*ArticleResult, error) {
+	req := &meilisearch.SearchRequest{
+		Limit:                 int64(q.Limit),
+		Offset:                int64(q.Offset),
+		AttributesToRetrieve:  []string{"id"},
+		AttributesToHighlight: []string{"title", "content"},
+		AttributesToCrop:      []string{"content"},
+		CropLength:            snippetWords,
+		CropMarker:            "…",
+		HighlightPreTag:       highlightPre,
+		HighlightPostTag:      highlightPost,
+		Facets:                articleFacets,
+		Sort:                  articleSortRules(q.Sort),
+	}
+	if filter := articleFilterExpr(q.Filter); filter != "" {
+		req.Filter = filter
+	}
+
+	searchRes, err := c.client.Index("articles").SearchWithContext(ctx, q.Query, req)
+	if err != nil {
+		return nil, fmt.Errorf("search articles failed: %w", err)
+	}
+
+	result := &ArticleResult{
+		Hits:  make([]ArticleHit, 0, len(searchRes.Hits)),
+		Total: int(searchRes.EstimatedTotalHits),
+	}
+	for _, hit := range searchRes.Hits {
+		var doc struct {
+			ID        string `json:"id"`
+			Formatted struct {
+				Title   string `json:"title"`
+				Content string `json:"content"`
+			} `json:"_formatted"`
+		}
+		if err := hit.DecodeInto(&doc); err != nil || doc.ID == "" {
+			continue
+		}
+		result.Hits = append(result.Hits, ArticleHit{
+			ID:      doc.ID,
+			Title:   highlightMarkup(doc.Formatted.Title),
+			Snippet: highlightMarkup(doc.Formatted.Content),
+		})
+	}
+
+	facets, err := parseFacets(searchRes.FacetDistribution)
+	if err != nil {
+		return nil, fmt.Errorf("search articles failed: %w", err)
+	}
+	result.Categories = facets["category"]
+	result.Authors = facets["authorID"]
+	result.Featured = facets["featured"]
+	return result, nil
+}
+
+func articleSortRules(s ArticleSort) []string {
+	switch s {
+	case ArticleSortNewest:
+		return []string{"createdAt:desc"}
+	case ArticleSortOldest:
+		return []string{"createdAt:asc"}
+	case ArticleSortTitle:
+		return []string{"title:asc"}
+	}
+	return nil
+}
+
+// filterString quotes s for use as a value in a filter expression.
+func filterString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func articleFilterExpr(f ArticleFilter) string {
+	var clauses []string
+	if len(f.Categories) > 0 {
+		values := make([]string, len(f.Categories))
+		for i, c := range f.Categories {
+			values[i] = filterString(c)
+		}
+		clauses = append(clauses, "category IN ["+strings.Join(values, ", ")+"]")
+	}
+	if f.AuthorID != "" {
+		clauses = append(clauses, "authorID = "+filterString(f.AuthorID))
+	}
+	if f.Since != nil {
+		clauses = append(clauses, fmt.Sprintf("createdAt >= %d", f.Since.Unix()))
+	}
+	if f.Until != nil {
+		clauses = append(clauses, fmt.Sprintf("createdAt <= %d", f.Until.Unix()))
+	}
+	if f.Featured != nil {
+		clauses = append(clauses, fmt.Sprintf("featured = %t", *f.Featured))
+	}
+	return strings.Join(clauses, " AND ")
+}
+
+func highlightMarkup(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, highlightPre, "<mark>")
+	return strings.ReplaceAll(s, highlightPost, "</mark>")
+}
+
+func parseFacets(raw json.RawMessage) (map[string][]FacetCount, error) {
+	facets := map[string][]FacetCount{}
+	if len(raw) == 0 {
+		return facets, nil
+	}
+	var dist map[string]map[string]int
+	if err := json.Unmarshal(raw, &dist); err != nil {
+		return nil, fmt.Errorf("invalid facet distribution: %w", err)
+	}
+	for name, values := range dist {
+		counts := make([]FacetCount, 0, len(values))
+		for value, count := range values {
+			counts = append(counts, FacetCount{Value: value, Count: count})
+		}
+		sort.Slice(counts, func(i, j int) bool {
+			if counts[i].Count != counts[j].Count {
+				return counts[i].Count > counts[j].Count
+			}
+			return counts[i].Value < counts[j].Value
+		})
+		facets[name] = counts
+	}
+	return facets, nil
+}
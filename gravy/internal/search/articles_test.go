@@ -0,0 +1,52 @@
+package search
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestArticleFilterExpr(t *testing.T) {
+	if got := articleFilterExpr(ArticleFilter{}); got != "" {
+		t.Fatalf("empty filter = %q, want none", got)
+	}
+	since := time.Unix(1700000000, 0)
+	featured := true
+	got := articleFilterExpr(ArticleFilter{
+		Categories: []string{"Clubs", `Say "hi"`},
+		AuthorID:   "abc",
+		Since:      &since,
+		Featured:   &featured,
+	})
+	want := `category IN ["Clubs", "Say \"hi\""] AND authorID = "abc" AND createdAt >= 1700000000 AND featured = true`
+	if got != want {
+		t.Fatalf("filter = %q, want %q", got, want)
+	}
+}
+
+func TestHighlightMarkup(t *testing.T) {
+	got := highlightMarkup("a <b> " + highlightPre + "match" + highlightPost + " & more")
+	want := "a &lt;b&gt; <mark>match</mark> &amp; more"
+	if got != want {
+		t.Fatalf("highlightMarkup = %q, want %q", got, want)
+	}
+}
+
+func TestParseFacets(t *testing.T) {
+	raw := json.RawMessage(`{"category":{"Clubs":2,"Hostels":5,"Academics":2},"featured":{"true":1}}`)
+	facets, err := parseFacets(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FacetCount{{"Hostels", 5}, {"Academics", 2}, {"Clubs", 2}}
+	if !reflect.DeepEqual(facets["category"], want) {
+		t.Fatalf("categories = %v, want %v", facets["category"], want)
+	}
+	if len(facets["authorID"]) != 0 {
+		t.Fatalf("authors = %v, want none", facets["authorID"])
+	}
+	if _, err := parseFacets(nil); err != nil {
+		t.Fatalf("parseFacets(nil) = %v", err)
+	}
+}
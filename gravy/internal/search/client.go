@@ -50,7 +50,7 @@ func (c *Client) CreateIndexes() error {
 	}
 	log.Printf("Update filterable attributes task: %v", task.TaskUID)
 
-	return nil
+	return c.configureArticles()
 }
 
 func (c *Client) IndexArticles(ctx context.Context, documents interface{}) error {
@@ -169,28 +169,6 @@ func (c *Client) DeleteCommunityDataByPostID(ctx context.Context, postID string)
 	return nil
 }
 
-func (c *Client) SearchArticles(ctx context.Context, query string, limit, offset int) ([]string, error) {
-	searchRes, err := c.client.Index("articles").Search(query, &meilisearch.SearchRequest{
-		Limit:  int64(limit),
-		Offset: int64(offset),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("search articles failed: %w", err)
-	}
-
-	ids := make([]string, len(searchRes.Hits))
-	for i, hit := range searchRes.Hits {
-		var hitMap map[string]interface{}
-		b, _ := json.Marshal(hit)
-		_ = json.Unmarshal(b, &hitMap)
-
-		if id, ok := hitMap["id"].(string); ok {
-			ids[i] = id
-		}
-	}
-	return ids, nil
-}
-
 func (c *Client) SearchPosts(ctx context.Context, query string, limit, offset int) ([]string, error) {
 	searchRes, err := c.client.Index("community").Search(query, &meilisearch.SearchRequest{
 		Limit:  int64(limit),
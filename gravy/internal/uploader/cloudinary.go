@@ -2,15 +2,13 @@ package uploader
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 )
 
-type Uploader interface {
-	UploadImage(ctx context.Context, file interface{}, folder string) (string, error)
-}
-
 type cloudinaryUploader struct {
 	cld *cloudinary.Cloudinary
 }
@@ -32,3 +30,37 @@ func (u *cloudinaryUploader) UploadImage(ctx context.Context, file interface{},
 	}
 	return resp.SecureURL, nil
 }
+
+// Keys are "<resource type>/<public id>" since Cloudinary needs both to
+// delete an asset.
+func (u *cloudinaryUploader) UploadFile(ctx context.Context, file interface{}, folder string) (*File, error) {
+	resp, err := u.cld.Upload.Upload(ctx, file, uploader.UploadParams{
+		Folder:       folder,
+		ResourceType: "auto",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error.Message != "" {
+		return nil, fmt.Errorf("upload failed: %s", resp.Error.Message)
+	}
+	return &File{URL: resp.SecureURL, Key: resp.ResourceType + "/" + resp.PublicID}, nil
+}
+
+func (u *cloudinaryUploader) Delete(ctx context.Context, key string) error {
+	resourceType, publicID, ok := strings.Cut(key, "/")
+	if !ok {
+		return fmt.Errorf("invalid file key %q", key)
+	}
+	resp, err := u.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     publicID,
+		ResourceType: resourceType,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error.Message != "" {
+		return fmt.Errorf("delete failed: %s", resp.Error.Message)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package uploader
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalPathPrefix is where the server mounts Local.Handler.
+const LocalPathPrefix = "/uploads/"
+
+// Local stores uploads on disk under dir and serves them from baseURL.
+type Local struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	return &Local{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+func (l *Local) UploadImage(ctx context.Context, file interface{}, folder string) (string, error) {
+	f, err := l.UploadFile(ctx, file, folder)
+	if err != nil {
+		return "", err
+	}
+	return f.URL, nil
+}
+
+func (l *Local) UploadFile(ctx context.Context, file interface{}, folder string) (*File, error) {
+	data, err := readAll(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := newKey(folder, contentType(data))
+	if err != nil {
+		return nil, err
+	}
+	name, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		return nil, err
+	}
+	return &File{URL: l.baseURL + "/" + key, Key: key}, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path maps a key to a file inside dir, rejecting keys that would escape it.
+func (l *Local) path(key string) (string, error) {
+	name := filepath.Join(l.dir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(l.dir, name)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid file key %q", key)
+	}
+	return name, nil
+}
+
+// Handler serves the stored files. Keys are random and never reused, so
+// responses can be cached for good. Files are user content, so browsers are
+// told not to sniff or run them.
+func (l *Local) Handler() http.Handler {
+	files := http.StripPrefix(LocalPathPrefix, http.FileServer(http.Dir(l.dir)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+		ext := strings.ToLower(filepath.Ext(r.URL.Path))
+		if ext != ".jpg" && ext != ".png" && ext != ".gif" && ext != ".webp" {
+			w.Header().Set("Content-Disposition", "attachment")
+		}
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,29 @@
+package uploader
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	l, err := NewLocal(t.TempDir(), "http://localhost/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"../secret", "a/../../secret", ""} {
+		if _, err := l.path(key); err == nil {
+			t.Errorf("path(%q) was accepted", key)
+		}
+	}
+	f, err := l.UploadFile(context.Background(), strings.NewReader("hello"), "attachments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(f.URL, "http://localhost/uploads/attachments/") || !strings.HasSuffix(f.Key, ".txt") {
+		t.Errorf("UploadFile() = %+v", f)
+	}
+	if err := l.Delete(context.Background(), f.Key); err != nil {
+		t.Errorf("Delete() = %v", err)
+	}
+}
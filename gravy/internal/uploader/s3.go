@@ -0,0 +1,227 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config configures an S3-compatible bucket, e.g. AWS S3 or MinIO.
+type S3Config struct {
+	Endpoint  string // e.g. https://s3.us-east-1.amazonaws.com or http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL is the base URL files are served from, such as a CDN. It
+	// defaults to the bucket URL, which then has to allow public reads.
+	PublicURL string
+	// PathStyle addresses the bucket as <endpoint>/<bucket> instead of
+	// <bucket>.<endpoint>. MinIO needs it.
+	PathStyle bool
+}
+
+type s3Uploader struct {
+	cfg       S3Config
+	bucketURL *url.URL
+	client    *http.Client
+}
+
+func NewS3(cfg S3Config) (Uploader, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 backend")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT %q", cfg.Endpoint)
+	}
+	bucketURL := *endpoint
+	if cfg.PathStyle {
+		bucketURL.Path += "/" + cfg.Bucket
+	} else {
+		bucketURL.Host = cfg.Bucket + "." + bucketURL.Host
+	}
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = bucketURL.String()
+	}
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &s3Uploader{
+		cfg:       cfg,
+		bucketURL: &bucketURL,
+		client:    &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+func (s *s3Uploader) UploadImage(ctx context.Context, file interface{}, folder string) (string, error) {
+	f, err := s.UploadFile(ctx, file, folder)
+	if err != nil {
+		return "", err
+	}
+	return f.URL, nil
+}
+
+func (s *s3Uploader) UploadFile(ctx context.Context, file interface{}, folder string) (*File, error) {
+	data, err := readAll(file)
+	if err != nil {
+		return nil, err
+	}
+	ct := contentType(data)
+	key, err := newKey(folder, ct)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.request(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return nil, err
+	}
+	served, disposition := s3ServedAs(ct)
+	req.Header.Set("Content-Type", served)
+	if disposition != "" {
+		req.Header.Set("Content-Disposition", disposition)
+	}
+	req.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	if err := s.do(req); err != nil {
+		return nil, err
+	}
+	return &File{URL: s.cfg.PublicURL + "/" + key, Key: key}, nil
+}
+
+// s3ServedAs picks the Content-Type and Content-Disposition an object is
+// stored with. Unlike Local.Handler, a bucket can't add nosniff or a sandbox
+// CSP when serving, so types a browser would render as a document are stored
+// as opaque bytes, and anything but the inline image types downloads.
+func s3ServedAs(ct string) (contentType, disposition string) {
+	switch ct {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return ct, ""
+	}
+	if strings.HasPrefix(ct, "text/html") || strings.HasPrefix(ct, "text/xml") ||
+		ct == "image/svg+xml" || ct == "application/xml" || ct == "application/xhtml+xml" {
+		ct = "application/octet-stream"
+	}
+	return ct, "attachment"
+}
+
+func (s *s3Uploader) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req)
+}
+
+func (s *s3Uploader) request(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	u := *s.bucketURL
+	u.Path += "/" + key
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	sum := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	return req, nil
+}
+
+func (s *s3Uploader) do(req *http.Request) error {
+	signV4(req, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// signV4 adds an AWS Signature Version 4 Authorization header for the s3
+// service. The request must already carry X-Amz-Content-Sha256. Host,
+// Range and the x-amz-* headers are signed.
+func signV4(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" || lower == "content-md5" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := day + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vs := append([]string(nil), values[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,134 @@
+package uploader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// File is a stored upload. Key identifies it to Delete.
+type File struct {
+	URL string
+	Key string
+}
+
+type Uploader interface {
+	UploadImage(ctx context.Context, file interface{}, folder string) (string, error)
+	// UploadFile stores a file of any type.
+	UploadFile(ctx context.Context, file interface{}, folder string) (*File, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFromEnv picks the backend named by STORAGE_BACKEND ("cloudinary",
+// "local" or "s3"). When it is unset, Cloudinary is used if its credentials
+// are configured. Otherwise development falls back to local disk, so gravy
+// runs without any external service, while production refuses to start
+// rather than keep uploads on a container's disk.
+func NewFromEnv() (Uploader, error) {
+	backend := strings.ToLower(os.Getenv("STORAGE_BACKEND"))
+	cldName := os.Getenv("CLOUDINARY_CLOUD_NAME")
+	cldKey := os.Getenv("CLOUDINARY_API_KEY")
+	cldSecret := os.Getenv("CLOUDINARY_API_SECRET")
+	if backend == "" {
+		switch {
+		case cldName != "" && cldKey != "" && cldSecret != "":
+			backend = "cloudinary"
+		case strings.ToLower(os.Getenv("GO_ENV")) == "production":
+			return nil, fmt.Errorf("CLOUDINARY credentials are missing; set them or choose a STORAGE_BACKEND")
+		default:
+			backend = "local"
+		}
+	}
+
+	switch backend {
+	case "cloudinary":
+		if cldName == "" || cldKey == "" || cldSecret == "" {
+			return nil, fmt.Errorf("CLOUDINARY credentials are required for the cloudinary backend")
+		}
+		return NewUploader(cldName, cldKey, cldSecret)
+	case "local":
+		dir := os.Getenv("UPLOAD_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		baseURL := os.Getenv("UPLOAD_BASE_URL")
+		if baseURL == "" {
+			apiURL := os.Getenv("API_URL")
+			if apiURL == "" {
+				apiURL = "http://localhost:8080"
+			}
+			baseURL = strings.TrimRight(apiURL, "/") + strings.TrimRight(LocalPathPrefix, "/")
+		}
+		local, err := NewLocal(dir, baseURL)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Storing uploads in %s", dir)
+		return local, nil
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+			PathStyle: strings.ToLower(os.Getenv("S3_PATH_STYLE")) == "true",
+		})
+	}
+	return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+}
+
+// readAll reads an upload into memory. Cloudinary also accepts paths and
+// URLs, but the other backends need the content itself.
+func readAll(file interface{}) ([]byte, error) {
+	r, ok := file.(io.Reader)
+	if !ok {
+		return nil, fmt.Errorf("unsupported upload type %T", file)
+	}
+	return io.ReadAll(r)
+}
+
+// extensions overrides mime.ExtensionsByType, which picks the alphabetically
+// first extension (".jfif" for JPEG).
+var extensions = map[string]string{
+	"image/jpeg":                ".jpg",
+	"image/png":                 ".png",
+	"image/gif":                 ".gif",
+	"image/webp":                ".webp",
+	"application/pdf":           ".pdf",
+	"text/plain; charset=utf-8": ".txt",
+}
+
+// newKey returns a random key under folder with an extension matching the
+// content type.
+func newKey(folder, contentType string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	ext, ok := extensions[contentType]
+	if !ok {
+		ext = ".bin"
+		if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	return path.Join(strings.Trim(folder, "/"), hex.EncodeToString(b)+ext), nil
+}
+
+func contentType(data []byte) string {
+	t := http.DetectContentType(data)
+	if i := strings.Index(t, ";"); i >= 0 && !strings.HasPrefix(t, "text/") {
+		t = t[:i]
+	}
+	return t
+}
@@ -0,0 +1,31 @@
+package uploader
+
+import "testing"
+
+func TestNewFromEnvDefault(t *testing.T) {
+	t.Setenv("STORAGE_BACKEND", "")
+	t.Setenv("CLOUDINARY_CLOUD_NAME", "wikinitt")
+	t.Setenv("CLOUDINARY_API_KEY", "")
+	t.Setenv("CLOUDINARY_API_SECRET", "")
+	t.Setenv("UPLOAD_DIR", t.TempDir())
+
+	t.Setenv("GO_ENV", "production")
+	if u, err := NewFromEnv(); err == nil {
+		t.Fatalf("NewFromEnv() in production = %T, want an error", u)
+	}
+
+	t.Setenv("GO_ENV", "development")
+	u, err := NewFromEnv()
+	if err != nil {
+		t.Fatalf("NewFromEnv() in development: %v", err)
+	}
+	if _, ok := u.(*Local); !ok {
+		t.Errorf("NewFromEnv() in development = %T, want *Local", u)
+	}
+
+	t.Setenv("GO_ENV", "production")
+	t.Setenv("STORAGE_BACKEND", "local")
+	if _, err := NewFromEnv(); err != nil {
+		t.Errorf("NewFromEnv() with STORAGE_BACKEND=local in production: %v", err)
+	}
+}
@@ -19,10 +19,20 @@ type User struct {
 	Avatar      string `bson:"avatar"`
 	PhoneNumber string `bson:"phoneNumber"`
 
-	OAuthID       string    `bson:"oauthId"`
-	PasswordHash  string    `bson:"passwordHash"`
+	OAuthID           string     `bson:"oauthId"`
+	PasswordHash      string     `bson:"passwordHash"`
+	PasswordChangedAt *time.Time `bson:"passwordChangedAt,omitempty"`
+	EmailVerified     bool       `bson:"emailVerified"`
+
+	TwoFactorEnabled       bool     `bson:"twoFactorEnabled"`
+	TwoFactorSecret        string   `bson:"twoFactorSecret,omitempty"`
+	TwoFactorPendingSecret string   `bson:"twoFactorPendingSecret,omitempty"`
+	TwoFactorLastStep      int64    `bson:"twoFactorLastStep,omitempty"`
+	RecoveryCodeHashes     []string `bson:"recoveryCodeHashes,omitempty"`
+
 	SetupComplete bool      `bson:"setupComplete"`
 	IsAdmin       bool      `bson:"isAdmin"`
+	Roles         []string  `bson:"roles,omitempty"`
 	IsBanned      bool      `bson:"isBanned"`
 	CreatedAt     time.Time `bson:"createdAt"`
 }
@@ -47,16 +57,32 @@ type Repository interface {
 	Unblock(ctx context.Context, id string) error
 	CompleteSetup(ctx context.Context, id, username, displayName string) error
 	Update(ctx context.Context, id string, updates map[string]interface{}) (*User, error)
+	AddRole(ctx context.Context, id, role string) (*User, error)
+	RemoveRole(ctx context.Context, id, role string) (*User, error)
+	SetPassword(ctx context.Context, id, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, id string) error
 	EnsureIndexes(ctx context.Context) error
+
+	CreateToken(ctx context.Context, userID, purpose string, ttl time.Duration) (string, error)
+	ConsumeToken(ctx context.Context, purpose, raw string) (string, error)
+
+	SetPendingTwoFactorSecret(ctx context.Context, id, secret string) error
+	EnableTwoFactor(ctx context.Context, id, secret string, recoveryCodeHashes []string) error
+	DisableTwoFactor(ctx context.Context, id string) error
+	SetRecoveryCodes(ctx context.Context, id string, recoveryCodeHashes []string) error
+	UseTOTPStep(ctx context.Context, id string, step int64) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, id, codeHash string) (bool, error)
 }
 
 type repository struct {
-	coll *mongo.Collection
+	coll   *mongo.Collection
+	tokens *mongo.Collection
 }
 
 func NewRepository(db *mongo.Database) Repository {
 	return &repository{
-		coll: db.Collection("users"),
+		coll:   db.Collection("users"),
+		tokens: db.Collection("userTokens"),
 	}
 }
 
@@ -141,6 +167,46 @@ func (r *repository) Unblock(ctx context.Context, id string) error {
 	return err
 }
 
+// AddRole grants role to the user. The ADMIN role also sets the legacy
+// isAdmin flag so older clients keep working.
+func (r *repository) AddRole(ctx context.Context, id, role string) (*User, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$addToSet": bson.M{"roles": role}}
+	if role == "ADMIN" {
+		update["$set"] = bson.M{"isAdmin": true}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var user User
+	if err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": oid}, update, opts).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *repository) RemoveRole(ctx context.Context, id, role string) (*User, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$pull": bson.M{"roles": role}}
+	if role == "ADMIN" {
+		update["$set"] = bson.M{"isAdmin": false}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var user User
+	if err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": oid}, update, opts).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *repository) CompleteSetup(ctx context.Context, id, username, displayName string) error {
 	oid, err := bson.ObjectIDFromHex(id)
 	if err != nil {
@@ -187,6 +253,8 @@ func (r *repository) EnsureIndexes(ctx context.Context) error {
 		},
 	}
 
-	_, err := r.coll.Indexes().CreateMany(ctx, indices)
-	return err
+	if _, err := r.coll.Indexes().CreateMany(ctx, indices); err != nil {
+		return err
+	}
+	return r.ensureTokenIndexes(ctx)
 }
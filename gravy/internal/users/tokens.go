@@ -0,0 +1,124 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// UserToken is a single-use secret mailed to a user. Only the SHA-256 hash of
+// the token is stored so a database leak can't be used to reset passwords.
+type UserToken struct {
+	ID        string     `bson:"_id,omitempty"`
+	UserID    string     `bson:"userId"`
+	Purpose   string     `bson:"purpose"`
+	TokenHash string     `bson:"tokenHash"`
+	ExpiresAt time.Time  `bson:"expiresAt"`
+	UsedAt    *time.Time `bson:"usedAt,omitempty"`
+	CreatedAt time.Time  `bson:"createdAt"`
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *repository) CreateToken(ctx context.Context, userID, purpose string, ttl time.Duration) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := base64.RawURLEncoding.EncodeToString(b)
+
+	// Requesting a new token invalidates any outstanding ones for the same purpose.
+	now := time.Now()
+	_, err := r.tokens.UpdateMany(ctx, bson.M{
+		"userId":  userID,
+		"purpose": purpose,
+		"usedAt":  bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{"usedAt": now}})
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.tokens.InsertOne(ctx, UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(raw),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (r *repository) ConsumeToken(ctx context.Context, purpose, raw string) (string, error) {
+	now := time.Now()
+	var token UserToken
+	err := r.tokens.FindOneAndUpdate(ctx, bson.M{
+		"tokenHash": hashToken(raw),
+		"purpose":   purpose,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}, bson.M{"$set": bson.M{"usedAt": now}}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", ErrInvalidToken
+		}
+		return "", err
+	}
+	return token.UserID, nil
+}
+
+func (r *repository) SetPassword(ctx context.Context, id, passwordHash string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{
+		"passwordHash":      passwordHash,
+		"passwordChangedAt": time.Now(),
+	}})
+	return err
+}
+
+func (r *repository) MarkEmailVerified(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"emailVerified": true}})
+	return err
+}
+
+func (r *repository) ensureTokenIndexes(ctx context.Context) error {
+	_, err := r.tokens.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "purpose", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
@@ -0,0 +1,93 @@
+package users
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (r *repository) SetPendingTwoFactorSecret(ctx context.Context, id, secret string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"twoFactorPendingSecret": secret}})
+	return err
+}
+
+func (r *repository) EnableTwoFactor(ctx context.Context, id, secret string, recoveryCodeHashes []string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$set": bson.M{
+			"twoFactorEnabled":   true,
+			"twoFactorSecret":    secret,
+			"recoveryCodeHashes": recoveryCodeHashes,
+		},
+		"$unset": bson.M{"twoFactorPendingSecret": ""},
+	})
+	return err
+}
+
+func (r *repository) DisableTwoFactor(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$set": bson.M{"twoFactorEnabled": false},
+		"$unset": bson.M{
+			"twoFactorSecret":        "",
+			"twoFactorPendingSecret": "",
+			"twoFactorLastStep":      "",
+			"recoveryCodeHashes":     "",
+		},
+	})
+	return err
+}
+
+func (r *repository) SetRecoveryCodes(ctx context.Context, id string, recoveryCodeHashes []string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"recoveryCodeHashes": recoveryCodeHashes}})
+	return err
+}
+
+// UseTOTPStep records step as the last accepted TOTP step. It returns false if
+// that step (or a later one) was already used, so each code works only once.
+func (r *repository) UseTOTPStep(ctx context.Context, id string, step int64) (bool, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.coll.UpdateOne(ctx, bson.M{
+		"_id": oid,
+		"$or": []bson.M{
+			{"twoFactorLastStep": bson.M{"$exists": false}},
+			{"twoFactorLastStep": bson.M{"$lt": step}},
+		},
+	}, bson.M{"$set": bson.M{"twoFactorLastStep": step}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// ConsumeRecoveryCode removes the code hash if present and reports whether it was.
+func (r *repository) ConsumeRecoveryCode(ctx context.Context, id, codeHash string) (bool, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": oid, "recoveryCodeHashes": codeHash}, bson.M{
+		"$pull": bson.M{"recoveryCodeHashes": codeHash},
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
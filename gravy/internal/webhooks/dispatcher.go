@@ -0,0 +1,271 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// MaxAttempts is how many times a delivery is tried before it is marked
+	// FAILED. Retries back off exponentially from retryBase.
+	MaxAttempts = 6
+	retryBase   = 30 * time.Second
+
+	requestTimeout = 10 * time.Second
+	pollInterval   = 5 * time.Second
+)
+
+// RetryDelay is how long to wait before the attempt after the given one:
+// 30s, 1m, 2m, 4m and 8m.
+func RetryDelay(attempt int) time.Duration {
+	return retryBase << (attempt - 1)
+}
+
+// Sign returns the X-WikiNITT-Signature header for a payload sent at
+// timestamp. Receivers recompute it over "<timestamp>.<body>" with their
+// secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+var errPrivateAddress = errors.New("webhook URL resolves to a private address")
+
+// ValidateURL checks that a webhook URL is an absolute http(s) URL.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL")
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return fmt.Errorf("webhook URL must use http or https")
+	}
+	return nil
+}
+
+// Dispatcher queues events for the webhooks subscribed to them and delivers
+// them in the background.
+type Dispatcher struct {
+	repo   Repository
+	client *http.Client
+	wake   chan struct{}
+}
+
+// NewDispatcher refuses to connect to loopback and private addresses unless
+// allowPrivate is set, so group owners can't use webhooks to reach internal
+// services.
+func NewDispatcher(repo Repository, allowPrivate bool) *Dispatcher {
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Dispatcher{
+		repo: repo,
+		client: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: transport,
+			// Redirects could point anywhere, so they count as failures.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		wake: make(chan struct{}, 1),
+	}
+}
+
+type payload struct {
+	Event     Event     `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// Publish queues event for the group's webhooks and, when siteWide is set,
+// the site-wide ones. Pass an empty groupID for events outside groups.
+func (d *Dispatcher) Publish(ctx context.Context, groupID string, siteWide bool, event Event, data any) error {
+	hooks, err := d.repo.Subscribed(ctx, groupID, siteWide, event)
+	if err != nil {
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(payload{Event: event, CreatedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, hook := range hooks {
+		err := d.repo.CreateDelivery(ctx, &Delivery{
+			WebhookID:     hook.ID,
+			Event:         event,
+			Payload:       string(body),
+			Status:        StatusPending,
+			NextAttemptAt: &now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Test sends a PING to hook right away and returns the logged delivery. It
+// isn't retried.
+func (d *Dispatcher) Test(ctx context.Context, hook *Webhook) (*Delivery, error) {
+	body, err := json.Marshal(payload{
+		Event:     EventPing,
+		CreatedAt: time.Now().UTC(),
+		Data:      map[string]string{"webhookId": hook.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	delivery := &Delivery{WebhookID: hook.ID, Event: EventPing, Payload: string(body), Status: StatusPending}
+	if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	d.attempt(ctx, hook, delivery, false)
+	if err := d.repo.FinishAttempt(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Run delivers due deliveries until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		d.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivery, err := d.repo.ClaimDue(ctx, time.Now(), 2*requestTimeout)
+		if err != nil {
+			log.Printf("Failed to load webhook deliveries: %v", err)
+			return
+		}
+		if delivery == nil {
+			return
+		}
+
+		hook, err := d.repo.Get(ctx, delivery.WebhookID)
+		if err != nil || !hook.Active {
+			// Deleted or disabled since the event was queued.
+			delivery.Status = StatusFailed
+			delivery.Error = "webhook disabled"
+			delivery.NextAttemptAt = nil
+		} else {
+			d.attempt(ctx, hook, delivery, true)
+		}
+		if err := d.repo.FinishAttempt(ctx, delivery); err != nil {
+			log.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+		}
+	}
+}
+
+// attempt sends delivery once and updates its status. With retry set, a
+// failure schedules the next attempt until MaxAttempts is reached.
+func (d *Dispatcher) attempt(ctx context.Context, hook *Webhook, delivery *Delivery, retry bool) {
+	delivery.Attempts++
+	code, err := d.send(ctx, hook, delivery)
+	delivery.ResponseCode = code
+	now := time.Now()
+
+	if err == nil {
+		delivery.Status = StatusSucceeded
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		return
+	}
+	delivery.Error = err.Error()
+	if retry && delivery.Attempts < MaxAttempts {
+		next := now.Add(RetryDelay(delivery.Attempts))
+		delivery.Status = StatusPending
+		delivery.NextAttemptAt = &next
+		return
+	}
+	delivery.Status = StatusFailed
+	delivery.NextAttemptAt = nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, hook *Webhook, delivery *Delivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "WikiNITT-Webhooks/1.0")
+	req.Header.Set("X-WikiNITT-Event", string(delivery.Event))
+	req.Header.Set("X-WikiNITT-Delivery", delivery.ID)
+	req.Header.Set("X-WikiNITT-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-WikiNITT-Signature", Sign(hook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute}
+	for i, w := range want {
+		if got := RetryDelay(i + 1); got != w {
+			t.Errorf("RetryDelay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestAttemptSignsPayload(t *testing.T) {
+	hook := &Webhook{ID: "hook", Secret: "whsec_test"}
+	var gotSignature, wantSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get("X-WikiNITT-Timestamp"), 10, 64)
+		gotSignature = r.Header.Get("X-WikiNITT-Signature")
+		wantSignature = Sign(hook.Secret, ts, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	hook.URL = server.URL
+
+	d := NewDispatcher(nil, true)
+	delivery := &Delivery{ID: "1", Event: EventPing, Payload: `{"event":"PING"}`, Status: StatusPending}
+	d.attempt(context.Background(), hook, delivery, true)
+
+	if delivery.Status != StatusSucceeded || delivery.ResponseCode != http.StatusNoContent {
+		t.Fatalf("delivery = %s (%d), want SUCCEEDED (204)", delivery.Status, delivery.ResponseCode)
+	}
+	if gotSignature == "" || gotSignature != wantSignature {
+		t.Errorf("signature = %q, want %q", gotSignature, wantSignature)
+	}
+}
+
+func TestAttemptRetriesFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	hook := &Webhook{ID: "hook", URL: server.URL, Secret: "whsec_test"}
+	d := NewDispatcher(nil, true)
+
+	delivery := &Delivery{ID: "1", Event: EventPing, Payload: "{}", Status: StatusPending}
+	d.attempt(context.Background(), hook, delivery, true)
+	if delivery.Status != StatusPending || delivery.NextAttemptAt == nil {
+		t.Fatalf("after first failure: status %s, next attempt %v", delivery.Status, delivery.NextAttemptAt)
+	}
+
+	delivery.Attempts = MaxAttempts - 1
+	d.attempt(context.Background(), hook, delivery, true)
+	if delivery.Status != StatusFailed || delivery.NextAttemptAt != nil {
+		t.Fatalf("after last attempt: status %s, next attempt %v", delivery.Status, delivery.NextAttemptAt)
+	}
+}
+
+func TestPrivateAddressesBlocked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	hook := &Webhook{ID: "hook", URL: server.URL, Secret: "whsec_test"}
+
+	delivery := &Delivery{ID: "1", Event: EventPing, Payload: "{}", Status: StatusPending}
+	NewDispatcher(nil, false).attempt(context.Background(), hook, delivery, false)
+	if delivery.Status != StatusFailed {
+		t.Fatalf("delivery to loopback = %s, want FAILED", delivery.Status)
+	}
+}
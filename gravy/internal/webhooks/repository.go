@@ -0,0 +1,319 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Event string
+
+const (
+	EventArticlePublished Event = "ARTICLE_PUBLISHED"
+	EventArticleUpdated   Event = "ARTICLE_UPDATED"
+	EventArticleDeleted   Event = "ARTICLE_DELETED"
+	EventPostCreated      Event = "POST_CREATED"
+	EventCommentCreated   Event = "COMMENT_CREATED"
+	EventMemberJoined     Event = "MEMBER_JOINED"
+	// EventPing is only sent by testWebhook and can't be subscribed to.
+	EventPing Event = "PING"
+)
+
+// Events lists the events webhooks can subscribe to.
+var Events = []Event{
+	EventArticlePublished,
+	EventArticleUpdated,
+	EventArticleDeleted,
+	EventPostCreated,
+	EventCommentCreated,
+	EventMemberJoined,
+}
+
+func Valid(e Event) bool {
+	for _, known := range Events {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupEvent reports whether e happens inside a group. Group webhooks can
+// only subscribe to these.
+func GroupEvent(e Event) bool {
+	return e == EventPostCreated || e == EventCommentCreated || e == EventMemberJoined
+}
+
+// Webhook posts the events it subscribes to to URL. Site-wide webhooks have
+// an empty GroupID and see group events from public groups only.
+type Webhook struct {
+	ID        string    `bson:"_id,omitempty"`
+	GroupID   string    `bson:"groupId"`
+	URL       string    `bson:"url"`
+	Secret    string    `bson:"secret"`
+	Events    []Event   `bson:"events"`
+	Active    bool      `bson:"active"`
+	CreatedBy string    `bson:"createdBy"`
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+func (w *Webhook) Subscribes(e Event) bool {
+	for _, s := range w.Events {
+		if s == e {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	StatusPending   DeliveryStatus = "PENDING"
+	StatusSucceeded DeliveryStatus = "SUCCEEDED"
+	StatusFailed    DeliveryStatus = "FAILED" // Out of retries
+)
+
+// Delivery is one event sent to one webhook. Payload is stored so that
+// retries send exactly the same body.
+type Delivery struct {
+	ID            string         `bson:"_id,omitempty"`
+	WebhookID     string         `bson:"webhookId"`
+	Event         Event          `bson:"event"`
+	Payload       string         `bson:"payload"`
+	Status        DeliveryStatus `bson:"status"`
+	Attempts      int            `bson:"attempts"`
+	ResponseCode  int            `bson:"responseCode,omitempty"`
+	Error         string         `bson:"error,omitempty"`
+	NextAttemptAt *time.Time     `bson:"nextAttemptAt,omitempty"`
+	LockedUntil   *time.Time     `bson:"lockedUntil,omitempty"`
+	CreatedAt     time.Time      `bson:"createdAt"`
+	DeliveredAt   *time.Time     `bson:"deliveredAt,omitempty"`
+}
+
+// deliveryRetention is how long the delivery log is kept.
+const deliveryRetention = 30 * 24 * time.Hour
+
+type Repository interface {
+	Create(ctx context.Context, hook *Webhook) error
+	Get(ctx context.Context, id string) (*Webhook, error)
+	Update(ctx context.Context, hook *Webhook) error
+	Delete(ctx context.Context, id string) error
+	// List returns the webhooks of a group, or the site-wide ones for "".
+	List(ctx context.Context, groupID string) ([]*Webhook, error)
+	// Subscribed returns the active webhooks of the group and, when
+	// siteWide is set, the site-wide ones that subscribe to event.
+	Subscribed(ctx context.Context, groupID string, siteWide bool, event Event) ([]*Webhook, error)
+
+	CreateDelivery(ctx context.Context, d *Delivery) error
+	// ClaimDue locks the next pending delivery that is due so that only one
+	// worker sends it. It returns nil when nothing is due.
+	ClaimDue(ctx context.Context, now time.Time, lock time.Duration) (*Delivery, error)
+	// FinishAttempt records the outcome of sending d and releases its lock.
+	FinishAttempt(ctx context.Context, d *Delivery) error
+	ListDeliveries(ctx context.Context, webhookID string, limit, offset int) ([]*Delivery, error)
+
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	hooks      *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{
+		hooks:      db.Collection("webhooks"),
+		deliveries: db.Collection("webhookDeliveries"),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, hook *Webhook) error {
+	hook.CreatedAt = time.Now()
+	hook.UpdatedAt = hook.CreatedAt
+	res, err := r.hooks.InsertOne(ctx, hook)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		hook.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) Get(ctx context.Context, id string) (*Webhook, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var hook Webhook
+	if err := r.hooks.FindOne(ctx, bson.M{"_id": oid}).Decode(&hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func (r *repository) Update(ctx context.Context, hook *Webhook) error {
+	oid, err := bson.ObjectIDFromHex(hook.ID)
+	if err != nil {
+		return err
+	}
+	hook.UpdatedAt = time.Now()
+	_, err = r.hooks.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{
+		"url":       hook.URL,
+		"secret":    hook.Secret,
+		"events":    hook.Events,
+		"active":    hook.Active,
+		"updatedAt": hook.UpdatedAt,
+	}})
+	return err
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	if _, err := r.hooks.DeleteOne(ctx, bson.M{"_id": oid}); err != nil {
+		return err
+	}
+	_, err = r.deliveries.DeleteMany(ctx, bson.M{"webhookId": id})
+	return err
+}
+
+func (r *repository) findHooks(ctx context.Context, filter bson.M) ([]*Webhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.hooks.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var hooks []*Webhook
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func (r *repository) List(ctx context.Context, groupID string) ([]*Webhook, error) {
+	return r.findHooks(ctx, bson.M{"groupId": groupID})
+}
+
+func (r *repository) Subscribed(ctx context.Context, groupID string, siteWide bool, event Event) ([]*Webhook, error) {
+	groups := bson.A{}
+	if groupID != "" {
+		groups = append(groups, groupID)
+	}
+	if siteWide {
+		groups = append(groups, "")
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	return r.findHooks(ctx, bson.M{
+		"groupId": bson.M{"$in": groups},
+		"events":  event,
+		"active":  true,
+	})
+}
+
+func (r *repository) CreateDelivery(ctx context.Context, d *Delivery) error {
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	res, err := r.deliveries.InsertOne(ctx, d)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		d.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *repository) ClaimDue(ctx context.Context, now time.Time, lock time.Duration) (*Delivery, error) {
+	filter := bson.M{
+		"status":        StatusPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"lockedUntil": bson.M{"$exists": false}},
+			bson.M{"lockedUntil": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"lockedUntil": now.Add(lock)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+		SetReturnDocument(options.After)
+	var d Delivery
+	err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&d)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *repository) FinishAttempt(ctx context.Context, d *Delivery) error {
+	oid, err := bson.ObjectIDFromHex(d.ID)
+	if err != nil {
+		return err
+	}
+	set := bson.M{
+		"status":       d.Status,
+		"attempts":     d.Attempts,
+		"responseCode": d.ResponseCode,
+		"error":        d.Error,
+		"deliveredAt":  d.DeliveredAt,
+	}
+	unset := bson.M{"lockedUntil": ""}
+	if d.NextAttemptAt != nil {
+		set["nextAttemptAt"] = d.NextAttemptAt
+	} else {
+		unset["nextAttemptAt"] = ""
+	}
+	_, err = r.deliveries.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": set, "$unset": unset})
+	return err
+}
+
+func (r *repository) ListDeliveries(ctx context.Context, webhookID string, limit, offset int) ([]*Delivery, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+	cursor, err := r.deliveries.Find(ctx, bson.M{"webhookId": webhookID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var list []*Delivery
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.hooks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "groupId", Value: 1}, {Key: "events", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook indexes: %w", err)
+	}
+	_, err = r.deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(deliveryRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery indexes: %w", err)
+	}
+	return nil
+}
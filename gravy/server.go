@@ -6,27 +6,50 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
 	"github.com/joho/godotenv"
 	"github.com/pranava-mohan/wikinitt/gravy/graph"
 	"github.com/pranava-mohan/wikinitt/gravy/graph/model"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/apitokens"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/articles"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/attachments"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/audit"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/auth"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/automod"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/bans"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/categories"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/community"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/db"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/digest"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/karma"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mailer"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/maplocation"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/media"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/mentions"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/notifications"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/polls"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/quota"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/rag"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/ratelimit"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/reports"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/roles"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/search"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/uploader"
 	"github.com/pranava-mohan/wikinitt/gravy/internal/users"
+	"github.com/pranava-mohan/wikinitt/gravy/internal/webhooks"
 	"github.com/rs/cors"
+	"github.com/vektah/gqlparser/v2/ast"
 	"golang.org/x/time/rate"
 )
 
@@ -67,8 +90,22 @@ func main() {
 	userRepo := users.NewRepository(database)
 	articleRepo := articles.NewRepository(database, searchClient)
 	categoryRepo := categories.NewRepository(database)
-	communityRepo := community.NewRepository(database, searchClient)
+	karmaRepo := karma.NewRepository(database)
+	communityRepo := community.NewRepository(database, searchClient, karmaRepo)
 	mapLocationRepo := maplocation.NewRepository(database)
+	apiTokenRepo := apitokens.NewRepository(database)
+	banRepo := bans.NewRepository(database)
+	auditRepo := audit.NewRepository(database)
+	reportRepo := reports.NewRepository(database)
+	automodRepo := automod.NewRepository(database)
+	notificationRepo := notifications.NewRepository(database)
+	mentionRepo := mentions.NewRepository(database)
+	digestRepo := digest.NewRepository(database)
+	webhookRepo := webhooks.NewRepository(database)
+	pollRepo := polls.NewRepository(database)
+	attachmentRepo := attachments.NewRepository(database)
+	mediaRepo := media.NewRepository(database)
+	quotaRepo := quota.NewRepository(database)
 
 	ctx := context.Background()
 	if err := userRepo.EnsureIndexes(ctx); err != nil {
@@ -80,18 +117,57 @@ func main() {
 	if err := communityRepo.EnsureIndexes(ctx); err != nil {
 		log.Printf("Failed to create community indexes: %v", err)
 	}
+	if err := apiTokenRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create api token indexes: %v", err)
+	}
+	if err := banRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create ban indexes: %v", err)
+	}
+	if err := auditRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create audit log indexes: %v", err)
+	}
+	if err := reportRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create report indexes: %v", err)
+	}
+	if err := automodRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create automod indexes: %v", err)
+	}
+	if err := karmaRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create karma indexes: %v", err)
+	}
+	if err := notificationRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create notification indexes: %v", err)
+	}
+	if err := mentionRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create mention indexes: %v", err)
+	}
+	if err := digestRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create digest indexes: %v", err)
+	}
+	if err := webhookRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create webhook indexes: %v", err)
+	}
+	if err := pollRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create poll indexes: %v", err)
+	}
+	if err := attachmentRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create attachment indexes: %v", err)
+	}
+	if err := mediaRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create media indexes: %v", err)
+	}
+	if err := quotaRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to create upload usage indexes: %v", err)
+	}
 
-	cldName := os.Getenv("CLOUDINARY_CLOUD_NAME")
-	cldKey := os.Getenv("CLOUDINARY_API_KEY")
-	cldSecret := os.Getenv("CLOUDINARY_API_SECRET")
-
-	if cldName == "" || cldKey == "" || cldSecret == "" {
-		log.Fatal("CLOUDINARY credentials are required")
+	uploaderService, err := uploader.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure file storage: %v", err)
 	}
 
-	uploaderService, err := uploader.NewUploader(cldName, cldKey, cldSecret)
+	mailService, err := mailer.NewFromEnv()
 	if err != nil {
-		log.Fatalf("Failed to create Cloudinary uploader: %v", err)
+		log.Fatalf("Failed to configure mailer: %v", err)
 	}
 
 	redisHost := os.Getenv("REDIS_HOST")
@@ -126,16 +202,7 @@ func main() {
 
 			docs := make([]interface{}, len(unindexedArticles))
 			for i, a := range unindexedArticles {
-				docs[i] = map[string]interface{}{
-					"id":        a.ID,
-					"title":     a.Title,
-					"content":   a.Content,
-					"slug":      a.Slug,
-					"category":  a.Category,
-					"thumbnail": a.Thumbnail,
-					"authorID":  a.AuthorID,
-					"createdAt": a.CreatedAt.Unix(),
-				}
+				docs[i] = articles.SearchDocument(a)
 			}
 			if err := searchClient.IndexArticles(ctx, docs); err != nil {
 				log.Printf("Failed to index articles batch: %v", err)
@@ -328,32 +395,211 @@ func main() {
 		log.Println("Meilisearch indexing reference complete.")
 	}()
 
+	rescoreInterval := 10 * time.Minute
+	if v := os.Getenv("HOT_SCORE_INTERVAL_MINUTES"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes <= 0 {
+			log.Fatalf("Invalid HOT_SCORE_INTERVAL_MINUTES: %q", v)
+		}
+		rescoreInterval = time.Duration(minutes) * time.Minute
+	}
+	go func() {
+		ctx := context.Background()
+		ticker := time.NewTicker(rescoreInterval)
+		defer ticker.Stop()
+		for {
+			if n, err := communityRepo.RecomputeScores(ctx); err != nil {
+				log.Printf("Failed to recompute ranking scores: %v", err)
+			} else if n > 0 {
+				log.Printf("Recomputed ranking scores for %d posts and comments", n)
+			}
+			<-ticker.C
+		}
+	}()
+
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "https://wikinitt.netlify.app"
+	}
+	apiURL := os.Getenv("API_URL")
+	if apiURL == "" {
+		apiURL = "http://localhost:" + port
+	}
+	digestJob := &digest.Job{
+		Users:       userRepo,
+		Community:   communityRepo,
+		Articles:    articleRepo,
+		Settings:    digestRepo,
+		Mailer:      mailService,
+		FrontendURL: frontendURL,
+		APIURL:      apiURL,
+	}
+	// Digests are checked hourly; each user gets theirs once their daily or
+	// weekly period has passed.
+	go func() {
+		ctx := context.Background()
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if n, err := digestJob.Run(ctx, time.Now()); err != nil {
+				log.Printf("Failed to send digests: %v", err)
+			} else if n > 0 {
+				log.Printf("Sent %d digest emails", n)
+			}
+			<-ticker.C
+		}
+	}()
+
+	attachmentCollector := &attachments.Collector{Repo: attachmentRepo, Storage: uploaderService, Quota: quotaRepo}
+	go func() {
+		ctx := context.Background()
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if n, err := attachmentCollector.Run(ctx, time.Now()); err != nil {
+				log.Printf("Failed to collect attachments: %v", err)
+			} else if n > 0 {
+				log.Printf("Deleted %d unused attachments", n)
+			}
+			<-ticker.C
+		}
+	}()
+
+	mediaCollector := &media.Collector{Repo: mediaRepo, Storage: uploaderService, Quota: quotaRepo}
+	go func() {
+		ctx := context.Background()
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if n, err := mediaCollector.Run(ctx, time.Now()); err != nil {
+				log.Printf("Failed to collect media: %v", err)
+			} else if n > 0 {
+				log.Printf("Deleted %d unused media", n)
+			}
+			<-ticker.C
+		}
+	}()
+
+	// Webhooks may only target private addresses when explicitly allowed,
+	// e.g. for local development.
+	webhookDispatcher := webhooks.NewDispatcher(webhookRepo, strings.ToLower(os.Getenv("WEBHOOK_ALLOW_PRIVATE")) == "true")
+	go webhookDispatcher.Run(context.Background())
+
 	c := graph.Config{
 		Resolvers: &graph.Resolver{
-			UserRepo:        userRepo,
-			ArticleRepo:     articleRepo,
-			CategoryRepo:    categoryRepo,
-			CommunityRepo:   communityRepo,
-			MapLocationRepo: mapLocationRepo,
-			Uploader:        uploaderService,
-			SearchClient:    searchClient,
-			RagClient:       ragClient,
+			UserRepo:         userRepo,
+			ArticleRepo:      articleRepo,
+			CategoryRepo:     categoryRepo,
+			CommunityRepo:    communityRepo,
+			MapLocationRepo:  mapLocationRepo,
+			Uploader:         uploaderService,
+			SearchClient:     searchClient,
+			RagClient:        ragClient,
+			Mailer:           mailService,
+			APITokenRepo:     apiTokenRepo,
+			BanRepo:          banRepo,
+			AuditRepo:        auditRepo,
+			ReportRepo:       reportRepo,
+			AutomodRepo:      automodRepo,
+			Automod:          automod.NewEngine(automodRepo),
+			KarmaRepo:        karmaRepo,
+			NotificationRepo: notificationRepo,
+			Notifier:         notifications.NewNotifier(notificationRepo),
+			MentionRepo:      mentionRepo,
+			DigestRepo:       digestRepo,
+			WebhookRepo:      webhookRepo,
+			Webhooks:         webhookDispatcher,
+			PollRepo:         pollRepo,
+			AttachmentRepo:   attachmentRepo,
+			MediaRepo:        mediaRepo,
+			QuotaRepo:        quotaRepo,
 		},
 	}
-	c.Directives.Auth = func(ctx context.Context, obj interface{}, next graphql.Resolver, requires *model.Role) (interface{}, error) {
+	// API tokens may read anything their owner can, but mutations need a
+	// matching scope. Mutations without one are session-only.
+	checkTokenScope := func(ctx context.Context, scope *apitokens.Scope) error {
+		token := auth.APITokenForContext(ctx)
+		if token == nil {
+			return nil
+		}
+		fc := graphql.GetFieldContext(ctx)
+		if fc == nil || fc.Object != "Mutation" {
+			return nil
+		}
+		if scope == nil {
+			return fmt.Errorf("access denied: not available to api tokens")
+		}
+		if !token.Allows(*scope) {
+			return fmt.Errorf("access denied: api token is missing scope %s", *scope)
+		}
+		return nil
+	}
+
+	// Banned users can still read and appeal, but not change anything.
+	checkBanned := func(ctx context.Context, user *users.User) error {
+		if !user.IsBanned {
+			return nil
+		}
+		if fc := graphql.GetFieldContext(ctx); fc != nil && fc.Object == "Mutation" {
+			return fmt.Errorf("access denied: your account is banned")
+		}
+		return nil
+	}
+
+	c.Directives.Auth = func(ctx context.Context, obj interface{}, next graphql.Resolver, requires *model.Role, scope *model.TokenScope, allowBanned *bool) (interface{}, error) {
 		user := auth.ForContext(ctx)
 		if user == nil {
 			return nil, fmt.Errorf("access denied: not authenticated")
 		}
 
-		if requires != nil && *requires == model.RoleAdmin && !user.IsAdmin {
-			return nil, fmt.Errorf("access denied: admins only")
+		if allowBanned == nil || !*allowBanned {
+			if err := checkBanned(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+
+		var tokenScope *apitokens.Scope
+		if scope != nil {
+			s := apitokens.Scope(*scope)
+			tokenScope = &s
+		}
+		if err := checkTokenScope(ctx, tokenScope); err != nil {
+			return nil, err
+		}
+
+		if requires != nil && *requires != model.RoleUser {
+			if !auth.HasRole(user, roles.Role(*requires)) {
+				return nil, fmt.Errorf("access denied: %s only", strings.ToLower(requires.String()))
+			}
+			if err := auth.CheckAdminTwoFactor(ctx, user); err != nil {
+				return nil, err
+			}
 		}
 
 		return next(ctx)
 	}
+	c.Directives.HasPermission = func(ctx context.Context, obj interface{}, next graphql.Resolver, perm model.Permission) (interface{}, error) {
+		user := auth.ForContext(ctx)
+		if user == nil {
+			return nil, fmt.Errorf("access denied: not authenticated")
+		}
+
+		if err := checkBanned(ctx, user); err != nil {
+			return nil, err
+		}
+		if err := auth.RequirePermission(ctx, user, roles.Permission(perm)); err != nil {
+			return nil, err
+		}
+		var tokenScope *apitokens.Scope
+		if s, ok := apitokens.ScopeForPermission(roles.Permission(perm)); ok {
+			tokenScope = &s
+		}
+		if err := checkTokenScope(ctx, tokenScope); err != nil {
+			return nil, err
+		}
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(c))
+		return next(ctx)
+	}
 
 	isProduction := strings.ToLower(os.Getenv("GO_ENV")) == "production"
 
@@ -366,6 +612,31 @@ func main() {
 		log.Println("Running in DEVELOPMENT mode")
 	}
 
+	srv := handler.New(graph.NewExecutableSchema(c))
+	// Browsers can't set headers on a websocket, so subscriptions send the
+	// token in their connection params instead.
+	srv.AddTransport(transport.Websocket{
+		InitFunc: func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+			ctx, err := auth.Authenticate(ctx, userRepo, apiTokenRepo, banRepo, payload.Authorization())
+			return ctx, nil, err
+		},
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return slices.Contains(allowedOrigins, r.Header.Get("Origin"))
+			},
+		},
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](100),
+	})
+
 	corsMiddleware := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodOptions},
@@ -386,7 +657,17 @@ func main() {
 		log.Printf("GraphQL playground available at http://localhost:%s/", port)
 	}
 
-	mux.Handle("/query", auth.Middleware(userRepo)(srv))
+	authMiddleware := auth.Middleware(userRepo, apiTokenRepo, banRepo)
+	mux.Handle("/query", audit.Middleware(authMiddleware(srv)))
+	mux.Handle("/admin/audit.csv", authMiddleware(audit.CSVHandler(auditRepo, func(r *http.Request) bool {
+		user := auth.ForContext(r.Context())
+		return user != nil && auth.RequirePermission(r.Context(), user, roles.AuditView) == nil
+	})))
+
+	mux.Handle("/digest/unsubscribe", digest.UnsubscribeHandler(digestRepo))
+	if local, ok := uploaderService.(*uploader.Local); ok {
+		mux.Handle(uploader.LocalPathPrefix, local.Handler())
+	}
 
 	var finalHandler http.Handler = mux
 
@@ -396,7 +677,17 @@ func main() {
 		rateLimiter := ratelimit.NewIPRateLimiter(rate.Limit(10), 20)
 		finalHandler = ratelimit.Middleware(rateLimiter)(finalHandler)
 
-		finalHandler = http.TimeoutHandler(finalHandler, 30*time.Second, `{"errors":[{"message":"Request timeout"}]}`)
+		// Subscriptions stay open and need to hijack the connection, which
+		// the timeout handler doesn't allow.
+		streaming := finalHandler
+		timed := http.TimeoutHandler(finalHandler, 30*time.Second, `{"errors":[{"message":"Request timeout"}]}`)
+		finalHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if websocket.IsWebSocketUpgrade(r) {
+				streaming.ServeHTTP(w, r)
+				return
+			}
+			timed.ServeHTTP(w, r)
+		})
 
 		finalHandler = recoveryMiddleware(finalHandler)
 	}